@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -13,8 +12,11 @@ import (
 	"startup-scout/config"
 	"startup-scout/internal/api"
 	"startup-scout/internal/infrastructure"
+	"startup-scout/internal/repository"
 	"startup-scout/internal/services"
 	"startup-scout/pkg/clients"
+	"startup-scout/pkg/mail"
+	"startup-scout/pkg/storage"
 
 	"github.com/go-chi/jwtauth/v5"
 	"go.uber.org/zap"
@@ -23,8 +25,6 @@ import (
 func main() {
 	cfg := config.Load()
 
-	fmt.Println(cfg.Auth.TelegramBotToken)
-
 	logger, err := zap.NewProduction()
 	if err != nil {
 		log.Fatal("Failed to initialize logger:", err)
@@ -41,36 +41,212 @@ func main() {
 	}
 	defer db.Close()
 
-	userRepo := infrastructure.NewUserRepository(db)
+	userRepo := infrastructure.NewCachedUserRepository(infrastructure.NewUserRepository(db), cfg.Auth.UserCacheTTL)
 	projectRepo := infrastructure.NewProjectRepository(db)
 	voteRepo := infrastructure.NewVoteRepository(db)
 	launchRepo := infrastructure.NewLaunchRepository(db)
+	categoryRepo := infrastructure.NewCategoryRepository(db)
+	teamRepo := infrastructure.NewTeamRepository(db)
+	imageRepo := infrastructure.NewImageRepository(db)
 	commentRepo := infrastructure.NewCommentRepository(db)
+	followRepo := infrastructure.NewFollowRepository(db)
+	notificationRepo := infrastructure.NewNotificationRepository(db)
+	telegramOutboxRepo := infrastructure.NewTelegramOutboxRepository(db)
+	newsletterRepo := infrastructure.NewNewsletterRepository(db)
+	webhookRepo := infrastructure.NewWebhookRepository(db)
+	sessionRepo := infrastructure.NewSessionRepository(db)
+	searchRepo := infrastructure.NewSearchRepository(db)
+	recoveryCodeRepo := infrastructure.NewRecoveryCodeRepository(db)
+	twoFactorChallengeRepo := infrastructure.NewTwoFactorChallengeRepository(db)
+
+	var rateLimitStore repository.RateLimitStore
+	if cfg.RateLimit.Store == "postgres" {
+		rateLimitStore = infrastructure.NewRateLimitStore(db)
+	} else {
+		rateLimitStore = infrastructure.NewMemoryRateLimitStore()
+	}
 
 	// Инициализируем сервисы
-	authService := services.NewAuthService(userRepo, services.AuthConfig{
+	loginGuard := services.NewLoginGuard(rateLimitStore, services.LoginGuardConfig{
+		MaxFailures:   cfg.RateLimit.MaxLoginFailures,
+		FailureWindow: cfg.RateLimit.LoginFailureWindow,
+		BaseLockout:   cfg.RateLimit.LoginLockout,
+		MaxLockout:    cfg.RateLimit.LoginMaxLockout,
+	})
+
+	authService := services.NewAuthService(userRepo, sessionRepo, loginGuard, services.AuthConfig{
 		TelegramBotToken: cfg.Auth.TelegramBotToken,
 		JWTSecret:        cfg.Auth.JWTSecret,
 		SessionDuration:  cfg.Auth.SessionDuration,
 	})
 
-	launchService := services.NewLaunchService(launchRepo)
-	projectService := services.NewProjectService(projectRepo, voteRepo, launchRepo, launchService)
-	commentService := services.NewCommentService(commentRepo)
-	imageService := services.NewImageService(&cfg.Storage)
+	twoFactorService := services.NewTwoFactorService(userRepo, recoveryCodeRepo, twoFactorChallengeRepo, loginGuard, "Startup Scout")
+
+	// Уведомления в Telegram ставятся в очередь, если канал включен
+	var notificationChannels []services.NotificationChannel
+	var telegramService *services.TelegramService
+	if cfg.Telegram.Enabled {
+		telegramService = services.NewTelegramService(
+			telegramOutboxRepo,
+			clients.NewTelegramClient(cfg.Auth.TelegramBotToken, cfg.Telegram.APIURL, 10*time.Second),
+			services.TelegramConfig{
+				SiteURL:        cfg.Telegram.SiteURL,
+				BatchSize:      cfg.Telegram.BatchSize,
+				MaxAttempts:    cfg.Telegram.MaxAttempts,
+				RetryBaseDelay: cfg.Telegram.RetryBaseDelay,
+				RetryMaxDelay:  cfg.Telegram.RetryMaxDelay,
+			},
+			logger,
+		)
+		notificationChannels = append(notificationChannels, telegramService)
+	}
+
+	notificationService := services.NewNotificationService(notificationRepo, userRepo, projectRepo, teamRepo, logger, notificationChannels...)
+
+	mailSender, err := mail.NewSender(&cfg.Mail)
+	if err != nil {
+		logger.Fatal("Failed to initialize mail transport", zap.Error(err))
+	}
+	newsletterService := services.NewNewsletterService(
+		newsletterRepo,
+		launchRepo,
+		projectRepo,
+		mailSender,
+		services.NewsletterConfig{
+			From:        cfg.Mail.From,
+			SiteURL:     cfg.Newsletter.SiteURL,
+			APIURL:      cfg.Newsletter.APIURL,
+			TopProjects: cfg.Newsletter.TopProjects,
+		},
+		logger,
+	)
+	webhookService := services.NewWebhookService(
+		webhookRepo,
+		projectRepo,
+		clients.NewWebhookClient(cfg.Webhook.Timeout),
+		services.WebhookConfig{
+			SiteURL:        cfg.Webhook.SiteURL,
+			BatchSize:      cfg.Webhook.BatchSize,
+			MaxAttempts:    cfg.Webhook.MaxAttempts,
+			RetryBaseDelay: cfg.Webhook.RetryBaseDelay,
+			RetryMaxDelay:  cfg.Webhook.RetryMaxDelay,
+		},
+		logger,
+	)
+	launchService := services.NewLaunchService(launchRepo, notificationService, webhookService)
+	fileStorage, err := storage.New(&cfg.Storage)
+	if err != nil {
+		logger.Fatal("Failed to initialize file storage", zap.Error(err))
+	}
+	imageService := services.NewImageService(&cfg.Storage, fileStorage, imageRepo)
+	projectService := services.NewProjectService(projectRepo, voteRepo, launchRepo, categoryRepo, teamRepo, launchService, imageService, notificationService, webhookService, cfg.Cache.ListingTTL)
+	commentService := services.NewCommentService(commentRepo, notificationService, webhookService)
+	userService := services.NewUserService(userRepo, projectRepo, commentRepo, followRepo, imageService)
+	followService := services.NewFollowService(followRepo, userRepo, projectRepo)
+	searchService := services.NewSearchService(searchRepo)
+	categoryService := services.NewCategoryService(categoryRepo, projectRepo, launchService, projectService)
+	teamService := services.NewTeamService(
+		teamRepo,
+		projectRepo,
+		userRepo,
+		notificationService,
+		mailSender,
+		services.TeamConfig{
+			From:          cfg.Mail.From,
+			SiteURL:       cfg.Team.SiteURL,
+			InvitationTTL: cfg.Team.InvitationTTL,
+		},
+		logger,
+	)
+	accountService := services.NewAccountService(
+		userRepo,
+		projectRepo,
+		voteRepo,
+		commentRepo,
+		followRepo,
+		notificationRepo,
+		telegramOutboxRepo,
+		newsletterRepo,
+		teamRepo,
+		sessionRepo,
+		projectService,
+		imageService,
+		services.AccountConfig{
+			DeletionGracePeriod:   cfg.Account.DeletionGracePeriod,
+			DeletionProjectPolicy: cfg.Account.DeletionProjectPolicy,
+		},
+		logger,
+	)
 
 	handlers := api.NewHandlers(
 		projectService,
 		authService,
+		twoFactorService,
 		commentService,
 		imageService,
 		launchService,
+		userService,
+		accountService,
+		followService,
+		notificationService,
+		newsletterService,
+		webhookService,
+		searchService,
+		categoryService,
+		teamService,
 		userRepo,
 		logger,
 		jwtAuth,
 	)
 
-	router := api.SetupRoutes(handlers, jwtAuth, userRepo)
+	trustedProxies, err := cfg.RateLimit.ParseTrustedProxies()
+	if err != nil {
+		logger.Fatal("Invalid rate limit config", zap.Error(err))
+	}
+
+	router := api.SetupRoutes(handlers, jwtAuth, userRepo, rateLimitStore, cfg.RateLimit, trustedProxies)
+
+	// Периодически очищаем устаревшие счетчики ограничения запросов, завершенные сессии
+	// и истекшие вызовы 2FA
+	cleanupCtx, stopCleanup := context.WithCancel(context.Background())
+	defer stopCleanup()
+	go func() {
+		ticker := time.NewTicker(10 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cleanupCtx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := rateLimitStore.DeleteExpired(cleanupCtx, now); err != nil {
+					logger.Error("Failed to clean up rate limits", zap.Error(err))
+				}
+				if _, err := sessionRepo.DeleteExpired(cleanupCtx, now); err != nil {
+					logger.Error("Failed to clean up sessions", zap.Error(err))
+				}
+				if _, err := twoFactorService.DeleteExpiredChallenges(cleanupCtx, now); err != nil {
+					logger.Error("Failed to clean up two-factor challenges", zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	if telegramService != nil && cfg.Auth.TelegramBotToken == "" {
+		logger.Warn("Telegram notifications are enabled but TELEGRAM_BOT_TOKEN is not set, outbox will not be processed")
+	} else if telegramService != nil {
+		pollInterval := cfg.Telegram.PollInterval
+		if pollInterval <= 0 {
+			pollInterval = 5 * time.Second
+		}
+		go telegramService.Run(cleanupCtx, pollInterval)
+	}
+
+	// События для внешних подписчиков доставляются только основным сервером
+	webhookPollInterval := cfg.Webhook.PollInterval
+	if webhookPollInterval <= 0 {
+		webhookPollInterval = 5 * time.Second
+	}
+	go webhookService.Run(cleanupCtx, webhookPollInterval)
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -3,25 +3,52 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"startup-scout/config"
 	"startup-scout/internal/infrastructure"
 	"startup-scout/internal/services"
 	"startup-scout/pkg/clients"
+	"startup-scout/pkg/mail"
+	"startup-scout/pkg/storage"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 func main() {
 	var configPath = flag.String("config", "config/config.yaml", "Path to config file")
+	var job = flag.String("job", "launch", "Job to run: launch, purge-accounts, digest, purge-webhooks, purge-images, migrate-storage, all")
+	var migrateFrom = flag.String("from", "local", "Source storage type for migrate-storage: local or s3")
+	var migrateTo = flag.String("to", "s3", "Destination storage type for migrate-storage: local or s3")
 	flag.Parse()
 
+	switch *job {
+	case "launch", "purge-accounts", "digest", "purge-webhooks", "purge-images", "migrate-storage", "all":
+	default:
+		log.Fatalf("Unknown job: %s", *job)
+	}
+
 	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Без обязательных параметров задачи молча работали бы с пустыми значениями
+	// (например, не находили бы файлы изображений), поэтому cron сразу завершается
+	if err := cfg.Storage.Validate(); err != nil {
+		log.Fatalf("Invalid storage config: %v", err)
+	}
+	if *job == "digest" || *job == "all" {
+		if err := cfg.Mail.Validate(); err != nil {
+			log.Fatalf("Invalid mail config: %v", err)
+		}
+		if cfg.Newsletter.SiteURL == "" || cfg.Newsletter.APIURL == "" {
+			log.Fatalf("Invalid newsletter config: newsletter.site_url and newsletter.api_url are required")
+		}
+	}
+
 	logger, err := zap.NewProduction()
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
@@ -34,20 +61,215 @@ func main() {
 	}
 	defer db.Close()
 
+	userRepo := infrastructure.NewUserRepository(db)
+	projectRepo := infrastructure.NewProjectRepository(db)
+	voteRepo := infrastructure.NewVoteRepository(db)
 	launchRepo := infrastructure.NewLaunchRepository(db)
-	launchService := services.NewLaunchService(launchRepo)
+	categoryRepo := infrastructure.NewCategoryRepository(db)
+	teamRepo := infrastructure.NewTeamRepository(db)
+	imageRepo := infrastructure.NewImageRepository(db)
+	commentRepo := infrastructure.NewCommentRepository(db)
+	followRepo := infrastructure.NewFollowRepository(db)
+	notificationRepo := infrastructure.NewNotificationRepository(db)
+	telegramOutboxRepo := infrastructure.NewTelegramOutboxRepository(db)
+	newsletterRepo := infrastructure.NewNewsletterRepository(db)
+	webhookRepo := infrastructure.NewWebhookRepository(db)
+	sessionRepo := infrastructure.NewSessionRepository(db)
 
-	ctx := context.Background()
+	// Cron только ставит сообщения Telegram и события вебхуков в очередь,
+	// отправляет их основной сервер
+	var notificationChannels []services.NotificationChannel
+	if cfg.Telegram.Enabled {
+		notificationChannels = append(notificationChannels, services.NewTelegramService(
+			telegramOutboxRepo,
+			clients.NewTelegramClient(cfg.Auth.TelegramBotToken, cfg.Telegram.APIURL, 10*time.Second),
+			services.TelegramConfig{
+				SiteURL:        cfg.Telegram.SiteURL,
+				BatchSize:      cfg.Telegram.BatchSize,
+				MaxAttempts:    cfg.Telegram.MaxAttempts,
+				RetryBaseDelay: cfg.Telegram.RetryBaseDelay,
+				RetryMaxDelay:  cfg.Telegram.RetryMaxDelay,
+			},
+			logger,
+		))
+	}
+
+	notificationService := services.NewNotificationService(notificationRepo, userRepo, projectRepo, teamRepo, logger, notificationChannels...)
 
-	launch, err := launchService.EnsureActiveLaunch(ctx)
+	mailSender, err := mail.NewSender(&cfg.Mail)
 	if err != nil {
-		logger.Error("Failed to ensure active launch", zap.Error(err))
+		logger.Fatal("Failed to initialize mail transport", zap.Error(err))
+	}
+	newsletterService := services.NewNewsletterService(
+		newsletterRepo,
+		launchRepo,
+		projectRepo,
+		mailSender,
+		services.NewsletterConfig{
+			From:        cfg.Mail.From,
+			SiteURL:     cfg.Newsletter.SiteURL,
+			APIURL:      cfg.Newsletter.APIURL,
+			TopProjects: cfg.Newsletter.TopProjects,
+		},
+		logger,
+	)
+	webhookService := services.NewWebhookService(
+		webhookRepo,
+		projectRepo,
+		clients.NewWebhookClient(cfg.Webhook.Timeout),
+		services.WebhookConfig{
+			SiteURL:        cfg.Webhook.SiteURL,
+			BatchSize:      cfg.Webhook.BatchSize,
+			MaxAttempts:    cfg.Webhook.MaxAttempts,
+			RetryBaseDelay: cfg.Webhook.RetryBaseDelay,
+			RetryMaxDelay:  cfg.Webhook.RetryMaxDelay,
+		},
+		logger,
+	)
+	launchService := services.NewLaunchService(launchRepo, notificationService, webhookService)
+	fileStorage, err := storage.New(&cfg.Storage)
+	if err != nil {
+		logger.Fatal("Failed to initialize file storage", zap.Error(err))
+	}
+	imageService := services.NewImageService(&cfg.Storage, fileStorage, imageRepo)
+	// Задачи cron меняют запуски и рейтинги, поэтому рейтинг здесь не кешируется
+	projectService := services.NewProjectService(projectRepo, voteRepo, launchRepo, categoryRepo, teamRepo, launchService, imageService, notificationService, webhookService, 0)
+	accountService := services.NewAccountService(
+		userRepo,
+		projectRepo,
+		voteRepo,
+		commentRepo,
+		followRepo,
+		notificationRepo,
+		telegramOutboxRepo,
+		newsletterRepo,
+		teamRepo,
+		sessionRepo,
+		projectService,
+		imageService,
+		services.AccountConfig{
+			DeletionGracePeriod:   cfg.Account.DeletionGracePeriod,
+			DeletionProjectPolicy: cfg.Account.DeletionProjectPolicy,
+		},
+		logger,
+	)
+
+	ctx := context.Background()
+
+	failed := false
+	if *job == "launch" || *job == "all" {
+		if err := rolloverLaunch(ctx, launchService, logger); err != nil {
+			logger.Error("Failed to roll over launch", zap.Error(err))
+			failed = true
+		}
+	}
+
+	if *job == "purge-accounts" || *job == "all" {
+		purged, err := accountService.PurgeScheduledAccounts(ctx, time.Now())
+		if err != nil {
+			logger.Error("Failed to purge deleted accounts", zap.Error(err))
+			failed = true
+		} else {
+			logger.Info("Deleted accounts purged", zap.Int("count", purged))
+		}
+	}
+
+	if *job == "digest" || *job == "all" {
+		sent, err := newsletterService.SendLaunchDigests(ctx, time.Now())
+		if err != nil {
+			logger.Error("Failed to send launch digests", zap.Error(err))
+			failed = true
+		} else {
+			logger.Info("Launch digests sent", zap.Int("count", sent))
+		}
+	}
+
+	if *job == "purge-webhooks" || *job == "all" {
+		retention := cfg.Webhook.DeliveryRetention
+		if retention <= 0 {
+			retention = 30 * 24 * time.Hour
+		}
+		purged, err := webhookService.PurgeDeliveries(ctx, time.Now().Add(-retention))
+		if err != nil {
+			logger.Error("Failed to purge webhook deliveries", zap.Error(err))
+			failed = true
+		} else {
+			logger.Info("Webhook deliveries purged", zap.Int64("count", purged))
+		}
+	}
+
+	if *job == "purge-images" || *job == "all" {
+		ttl := cfg.Storage.OrphanImageTTL
+		if ttl <= 0 {
+			ttl = 24 * time.Hour
+		}
+		purged, err := imageService.DeleteOrphans(ctx, time.Now().Add(-ttl))
+		if err != nil {
+			logger.Error("Failed to purge orphan images", zap.Int("purged", purged), zap.Error(err))
+			failed = true
+		} else {
+			logger.Info("Orphan images purged", zap.Int("count", purged))
+		}
+	}
+
+	// Перенос файлов между хранилищами запускается только явно и не входит в "all"
+	if *job == "migrate-storage" {
+		if err := migrateStorage(ctx, &cfg.Storage, *migrateFrom, *migrateTo, logger); err != nil {
+			logger.Error("Failed to migrate storage", zap.Error(err))
+			failed = true
+		}
+	}
+
+	if failed {
 		os.Exit(1)
 	}
+}
+
+// migrateStorage копирует все файлы из одного хранилища в другое. Оба хранилища
+// описываются одной секцией конфигурации, отличается только тип. Ссылки на
+// изображения ведут на API, поэтому после переноса достаточно переключить STORAGE_TYPE.
+func migrateStorage(ctx context.Context, cfg *config.StorageConfig, from, to string, logger *zap.Logger) error {
+	if from == to {
+		return fmt.Errorf("source and destination storage are the same: %s", from)
+	}
+
+	srcConfig := *cfg
+	srcConfig.Type = from
+	src, err := storage.New(&srcConfig)
+	if err != nil {
+		return fmt.Errorf("failed to initialize source storage: %w", err)
+	}
 
-	logger.Info("Weekly launch created successfully", 
+	dstConfig := *cfg
+	dstConfig.Type = to
+	dst, err := storage.New(&dstConfig)
+	if err != nil {
+		return fmt.Errorf("failed to initialize destination storage: %w", err)
+	}
+
+	result, err := storage.Migrate(ctx, src, dst)
+	logger.Info("Storage migration finished",
+		zap.String("from", from),
+		zap.String("to", to),
+		zap.Int("copied", result.Copied),
+		zap.Int("skipped", result.Skipped))
+
+	return err
+}
+
+// rolloverLaunch завершает истекший запуск и открывает новый. Без cron это
+// делают первые добавление проекта или голос после окончания запуска.
+func rolloverLaunch(ctx context.Context, launchService *services.LaunchService, logger *zap.Logger) error {
+	launch, err := launchService.Rollover(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Active launch is up to date",
 		zap.String("launch_id", launch.ID.String()),
 		zap.String("launch_name", launch.Name),
 		zap.Time("start_date", launch.StartDate),
 		zap.Time("end_date", launch.EndDate))
+
+	return nil
 }
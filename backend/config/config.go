@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,46 +14,193 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Auth     AuthConfig
-	Logger   LoggerConfig
-	Storage  StorageConfig
+	Server     ServerConfig     `yaml:"server"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Logger     LoggerConfig     `yaml:"logger"`
+	Storage    StorageConfig    `yaml:"storage"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Account    AccountConfig    `yaml:"account"`
+	Telegram   TelegramConfig   `yaml:"telegram"`
+	Mail       MailConfig       `yaml:"mail"`
+	Newsletter NewsletterConfig `yaml:"newsletter"`
+	Webhook    WebhookConfig    `yaml:"webhook"`
+	Team       TeamConfig       `yaml:"team"`
+	Cache      CacheConfig      `yaml:"cache"`
 }
 
 type ServerConfig struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	Port         string        `yaml:"port"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	DBName   string `yaml:"dbname"`
+	SSLMode  string `yaml:"sslmode"`
 }
 
 type AuthConfig struct {
-	TelegramBotToken string
-	JWTSecret        string
-	SessionDuration  time.Duration
+	TelegramBotToken string        `yaml:"telegram_bot_token"`
+	JWTSecret        string        `yaml:"jwt_secret"`
+	SessionDuration  time.Duration `yaml:"session_duration"`
+	UserCacheTTL     time.Duration `yaml:"user_cache_ttl"`
+}
+
+// RateLimit лимит запросов в окне
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Window   time.Duration `yaml:"window"`
+}
+
+type RateLimitConfig struct {
+	Store        string    `yaml:"store"`         // "memory" or "postgres"
+	Login        RateLimit `yaml:"login"`         // попытки входа с одного IP
+	LoginAccount RateLimit `yaml:"login_account"` // попытки входа в один аккаунт
+	Register     RateLimit `yaml:"register"`      // регистрации с одного IP
+	Newsletter   RateLimit `yaml:"newsletter"`    // подписки на рассылку с одного IP
+
+	// Сети прокси (nginx), которым доверяется заголовок X-Real-IP. От остальных
+	// адресов заголовок игнорируется, иначе клиент подменял бы свой IP.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
+	// Прогрессивная блокировка после неудачных входов
+	MaxLoginFailures   int           `yaml:"max_login_failures"`
+	LoginFailureWindow time.Duration `yaml:"login_failure_window"`
+	LoginLockout       time.Duration `yaml:"login_lockout"`
+	LoginMaxLockout    time.Duration `yaml:"login_max_lockout"`
+}
+
+type AccountConfig struct {
+	DeletionGracePeriod   time.Duration `yaml:"deletion_grace_period"`
+	DeletionProjectPolicy string        `yaml:"deletion_project_policy"` // "detach" or "delete"
+}
+
+// TelegramConfig доставка уведомлений через бота (токен берется из Auth.TelegramBotToken)
+type TelegramConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	APIURL         string        `yaml:"api_url"`  // можно подменить на локальную заглушку Bot API
+	SiteURL        string        `yaml:"site_url"` // адрес сайта для ссылок в сообщениях
+	PollInterval   time.Duration `yaml:"poll_interval"`
+	BatchSize      int           `yaml:"batch_size"`
+	MaxAttempts    int           `yaml:"max_attempts"`
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay"`
+}
+
+type MailConfig struct {
+	Transport    string `yaml:"transport"` // "smtp" or "file"
+	From         string `yaml:"from"`
+	SMTPHost     string `yaml:"smtp_host"`
+	SMTPPort     string `yaml:"smtp_port"`
+	SMTPUsername string `yaml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password"`
+	FileDir      string `yaml:"file_dir"` // каталог для писем при Transport = "file"
+}
+
+type NewsletterConfig struct {
+	SiteURL     string `yaml:"site_url"`     // адрес сайта для ссылок на проекты
+	APIURL      string `yaml:"api_url"`      // публичный адрес API для ссылок подтверждения и отписки
+	TopProjects int    `yaml:"top_projects"` // сколько проектов показывать в дайджесте
+}
+
+// WebhookConfig доставка событий внешним подписчикам
+type WebhookConfig struct {
+	SiteURL           string        `yaml:"site_url"` // адрес сайта для ссылок в событиях
+	PollInterval      time.Duration `yaml:"poll_interval"`
+	BatchSize         int           `yaml:"batch_size"`
+	MaxAttempts       int           `yaml:"max_attempts"`
+	RetryBaseDelay    time.Duration `yaml:"retry_base_delay"`
+	RetryMaxDelay     time.Duration `yaml:"retry_max_delay"`
+	Timeout           time.Duration `yaml:"timeout"`            // таймаут запроса к подписчику
+	DeliveryRetention time.Duration `yaml:"delivery_retention"` // сколько хранить журнал завершенных доставок
+}
+
+// TeamConfig приглашения в команды проектов
+type TeamConfig struct {
+	SiteURL       string        `yaml:"site_url"`       // адрес сайта для ссылок в письмах с приглашениями
+	InvitationTTL time.Duration `yaml:"invitation_ttl"` // срок действия приглашения
+}
+
+// CacheConfig кеш публичных списков в памяти процесса
+type CacheConfig struct {
+	ListingTTL time.Duration `yaml:"listing_ttl"` // время жизни рейтинга и статистики, 0 отключает кеш
 }
 
 type LoggerConfig struct {
-	Level string
+	Level string `yaml:"level"`
 }
 
 type StorageConfig struct {
-	Type         string // "local" or "s3"
-	LocalPath    string
-	MaxFileSize  int64 // in bytes
-	AllowedTypes []string
-	BaseURL      string
+	Type         string   `yaml:"type"` // "local" or "s3"
+	LocalPath    string   `yaml:"local_path"`
+	MaxFileSize  int64    `yaml:"max_file_size"` // in bytes
+	AllowedTypes []string `yaml:"allowed_types"`
+	BaseURL      string   `yaml:"base_url"`
+
+	// S3-совместимое хранилище (AWS S3, MinIO)
+	S3Endpoint     string `yaml:"s3_endpoint"`
+	S3Region       string `yaml:"s3_region"`
+	S3Bucket       string `yaml:"s3_bucket"`
+	S3AccessKey    string `yaml:"s3_access_key"`
+	S3SecretKey    string `yaml:"s3_secret_key"`
+	S3UsePathStyle bool   `yaml:"s3_use_path_style"` // адресация endpoint/bucket/key, нужна для MinIO
+
+	// ImageSizes варианты изображений, создаваемые при загрузке (?size=name)
+	ImageSizes []ImageSize `yaml:"image_sizes"`
+
+	// PresignTTL срок действия подписанных ссылок на файлы
+	PresignTTL time.Duration `yaml:"presign_ttl"`
+	// PresignRedirect перенаправляет запросы /images/{filename} на подписанную
+	// ссылку хранилища вместо отдачи файла через API
+	PresignRedirect bool `yaml:"presign_redirect"`
+
+	// OrphanImageTTL возраст, после которого изображение без ссылок удаляется
+	OrphanImageTTL time.Duration `yaml:"orphan_image_ttl"`
+
+	// Квоты загрузок на пользователя за последние сутки и за все время
+	// (размер считается по сохраненному оригиналу); 0 - без ограничения
+	UploadDailyFiles int   `yaml:"upload_daily_files"`
+	UploadDailyBytes int64 `yaml:"upload_daily_bytes"`
+	UploadTotalFiles int   `yaml:"upload_total_files"`
+	UploadTotalBytes int64 `yaml:"upload_total_bytes"`
+
+	// Ограничения размеров изображения в пикселях, проверяемые по заголовку до
+	// декодирования (защита от "бомб" распаковки); 0 - без ограничения.
+	// Для анимаций MaxImagePixels считается по всем кадрам.
+	MaxImageSide   int   `yaml:"max_image_side"`
+	MaxImagePixels int64 `yaml:"max_image_pixels"`
+
+	// Ролики для галереи проекта: типы, размер файла и длительность
+	AllowedVideoTypes []string      `yaml:"allowed_video_types"`
+	MaxVideoSize      int64         `yaml:"max_video_size"`
+	MaxVideoDuration  time.Duration `yaml:"max_video_duration"`
+}
+
+// ImageSize размер варианта изображения: Crop обрезает до точных размеров
+// (квадратные аватары), иначе изображение вписывается в рамку
+type ImageSize struct {
+	Name   string `yaml:"name"`
+	Width  int    `yaml:"width"`
+	Height int    `yaml:"height"`
+	Crop   bool   `yaml:"crop"`
 }
 
+// DefaultImageSizes варианты изображений по умолчанию
+var DefaultImageSizes = []ImageSize{
+	{Name: "thumb", Width: 160, Height: 160},
+	{Name: "card", Width: 640, Height: 640},
+	{Name: "full", Width: 1920, Height: 1920},
+	{Name: "avatar", Width: 256, Height: 256, Crop: true},
+}
+
+// DefaultTrustedProxies loopback и частные сети, из которых nginx ходит в приложение
+var DefaultTrustedProxies = []string{"127.0.0.0/8", "::1/128", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+
 func Load() *Config {
 	if err := godotenv.Load(".env"); err != nil {
 		if os.Getenv("ENV") != "production" {
@@ -76,6 +226,7 @@ func Load() *Config {
 			TelegramBotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
 			JWTSecret:        getEnv("JWT_SECRET", ""),
 			SessionDuration:  getDurationEnv("SESSION_DURATION", 24*time.Hour),
+			UserCacheTTL:     getDurationEnv("USER_CACHE_TTL", 30*time.Second),
 		},
 		Logger: LoggerConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
@@ -86,6 +237,101 @@ func Load() *Config {
 			MaxFileSize:  getInt64Env("STORAGE_MAX_FILE_SIZE", 10*1024*1024), // 10MB
 			AllowedTypes: []string{"image/jpeg", "image/png", "image/gif", "image/webp"},
 			BaseURL:      getEnv("STORAGE_BASE_URL", "http://localhost:8080/images"),
+
+			S3Endpoint:     getEnv("STORAGE_S3_ENDPOINT", ""),
+			S3Region:       getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3Bucket:       getEnv("STORAGE_S3_BUCKET", ""),
+			S3AccessKey:    getEnv("STORAGE_S3_ACCESS_KEY", ""),
+			S3SecretKey:    getEnv("STORAGE_S3_SECRET_KEY", ""),
+			S3UsePathStyle: getEnv("STORAGE_S3_USE_PATH_STYLE", "true") == "true",
+
+			ImageSizes: getImageSizesEnv("STORAGE_IMAGE_SIZES", DefaultImageSizes),
+
+			PresignTTL:      getDurationEnv("STORAGE_PRESIGN_TTL", 15*time.Minute),
+			PresignRedirect: getEnv("STORAGE_PRESIGN_REDIRECT", "false") == "true",
+
+			OrphanImageTTL: getDurationEnv("STORAGE_ORPHAN_IMAGE_TTL", 24*time.Hour),
+
+			UploadDailyFiles: getIntEnv("STORAGE_UPLOAD_DAILY_FILES", 50),
+			UploadDailyBytes: getInt64Env("STORAGE_UPLOAD_DAILY_BYTES", 100*1024*1024), // 100MB
+			UploadTotalFiles: getIntEnv("STORAGE_UPLOAD_TOTAL_FILES", 500),
+			UploadTotalBytes: getInt64Env("STORAGE_UPLOAD_TOTAL_BYTES", 1024*1024*1024), // 1GB
+
+			MaxImageSide:   getIntEnv("STORAGE_MAX_IMAGE_SIDE", 8192),
+			MaxImagePixels: getInt64Env("STORAGE_MAX_IMAGE_PIXELS", 40_000_000),
+
+			AllowedVideoTypes: []string{"video/mp4", "video/webm"},
+			MaxVideoSize:      getInt64Env("STORAGE_MAX_VIDEO_SIZE", 50*1024*1024), // 50MB
+			MaxVideoDuration:  getDurationEnv("STORAGE_MAX_VIDEO_DURATION", 60*time.Second),
+		},
+		RateLimit: RateLimitConfig{
+			Store: getEnv("RATE_LIMIT_STORE", "memory"),
+			Login: RateLimit{
+				Requests: getIntEnv("RATE_LIMIT_LOGIN_REQUESTS", 20),
+				Window:   getDurationEnv("RATE_LIMIT_LOGIN_WINDOW", time.Minute),
+			},
+			LoginAccount: RateLimit{
+				Requests: getIntEnv("RATE_LIMIT_LOGIN_ACCOUNT_REQUESTS", 10),
+				Window:   getDurationEnv("RATE_LIMIT_LOGIN_ACCOUNT_WINDOW", time.Minute),
+			},
+			Register: RateLimit{
+				Requests: getIntEnv("RATE_LIMIT_REGISTER_REQUESTS", 5),
+				Window:   getDurationEnv("RATE_LIMIT_REGISTER_WINDOW", time.Hour),
+			},
+			Newsletter: RateLimit{
+				Requests: getIntEnv("RATE_LIMIT_NEWSLETTER_REQUESTS", 5),
+				Window:   getDurationEnv("RATE_LIMIT_NEWSLETTER_WINDOW", time.Hour),
+			},
+			TrustedProxies:     getListEnv("RATE_LIMIT_TRUSTED_PROXIES", DefaultTrustedProxies),
+			MaxLoginFailures:   getIntEnv("LOGIN_MAX_FAILURES", 5),
+			LoginFailureWindow: getDurationEnv("LOGIN_FAILURE_WINDOW", 24*time.Hour),
+			LoginLockout:       getDurationEnv("LOGIN_LOCKOUT", time.Minute),
+			LoginMaxLockout:    getDurationEnv("LOGIN_MAX_LOCKOUT", time.Hour),
+		},
+		Account: AccountConfig{
+			DeletionGracePeriod:   getDurationEnv("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+			DeletionProjectPolicy: getEnv("ACCOUNT_DELETION_PROJECT_POLICY", "detach"),
+		},
+		Telegram: TelegramConfig{
+			Enabled:        getEnv("TELEGRAM_NOTIFICATIONS_ENABLED", "false") == "true",
+			APIURL:         getEnv("TELEGRAM_API_URL", "https://api.telegram.org"),
+			SiteURL:        getEnv("SITE_URL", "https://startup-scout.ru"),
+			PollInterval:   getDurationEnv("TELEGRAM_OUTBOX_POLL_INTERVAL", 5*time.Second),
+			BatchSize:      getIntEnv("TELEGRAM_OUTBOX_BATCH_SIZE", 20),
+			MaxAttempts:    getIntEnv("TELEGRAM_MAX_ATTEMPTS", 8),
+			RetryBaseDelay: getDurationEnv("TELEGRAM_RETRY_BASE_DELAY", 30*time.Second),
+			RetryMaxDelay:  getDurationEnv("TELEGRAM_RETRY_MAX_DELAY", 6*time.Hour),
+		},
+		Mail: MailConfig{
+			Transport:    getEnv("MAIL_TRANSPORT", "file"),
+			From:         getEnv("MAIL_FROM", "Startup Scout <noreply@startup-scout.ru>"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			FileDir:      getEnv("MAIL_FILE_DIR", "./mail"),
+		},
+		Newsletter: NewsletterConfig{
+			SiteURL:     getEnv("SITE_URL", "https://startup-scout.ru"),
+			APIURL:      getEnv("PUBLIC_API_URL", "https://startup-scout.ru/api"),
+			TopProjects: getIntEnv("NEWSLETTER_TOP_PROJECTS", 5),
+		},
+		Webhook: WebhookConfig{
+			SiteURL:           getEnv("SITE_URL", "https://startup-scout.ru"),
+			PollInterval:      getDurationEnv("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+			BatchSize:         getIntEnv("WEBHOOK_BATCH_SIZE", 20),
+			MaxAttempts:       getIntEnv("WEBHOOK_MAX_ATTEMPTS", 10),
+			RetryBaseDelay:    getDurationEnv("WEBHOOK_RETRY_BASE_DELAY", 30*time.Second),
+			RetryMaxDelay:     getDurationEnv("WEBHOOK_RETRY_MAX_DELAY", 12*time.Hour),
+			Timeout:           getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second),
+			DeliveryRetention: getDurationEnv("WEBHOOK_DELIVERY_RETENTION", 30*24*time.Hour),
+		},
+		Team: TeamConfig{
+			SiteURL:       getEnv("SITE_URL", "https://startup-scout.ru"),
+			InvitationTTL: getDurationEnv("TEAM_INVITATION_TTL", 7*24*time.Hour),
+		},
+		Cache: CacheConfig{
+			ListingTTL: getDurationEnv("CACHE_LISTING_TTL", 30*time.Second),
 		},
 	}
 }
@@ -124,6 +370,56 @@ func getInt64Env(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+// getListEnv разбирает список значений через запятую
+func getListEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getImageSizesEnv разбирает список вариантов вида "thumb:160x160,avatar:256x256:crop"
+func getImageSizesEnv(key string, defaultValue []ImageSize) []ImageSize {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var sizes []ImageSize
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			log.Printf("Warning: invalid %s entry %q, using defaults", key, item)
+			return defaultValue
+		}
+
+		var size ImageSize
+		size.Name = parts[0]
+		if _, err := fmt.Sscanf(parts[1], "%dx%d", &size.Width, &size.Height); err != nil || size.Width <= 0 || size.Height <= 0 {
+			log.Printf("Warning: invalid %s entry %q, using defaults", key, item)
+			return defaultValue
+		}
+		if len(parts) == 3 {
+			if parts[2] != "crop" {
+				log.Printf("Warning: invalid %s entry %q, using defaults", key, item)
+				return defaultValue
+			}
+			size.Crop = true
+		}
+		sizes = append(sizes, size)
+	}
+
+	return sizes
+}
+
 // LoadConfig загружает конфигурацию из YAML файла
 func LoadConfig(configPath string) (*Config, error) {
 	// Сначала загружаем переменные окружения
@@ -164,5 +460,125 @@ func LoadConfig(configPath string) (*Config, error) {
 		config.Database.SSLMode = getEnv("DB_SSLMODE", config.Database.SSLMode)
 	}
 
+	// Параметры почты для cron берутся из окружения, как и в основном сервере
+	if getEnv("MAIL_TRANSPORT", "") != "" {
+		config.Mail.Transport = getEnv("MAIL_TRANSPORT", config.Mail.Transport)
+	}
+	if getEnv("MAIL_FROM", "") != "" {
+		config.Mail.From = getEnv("MAIL_FROM", config.Mail.From)
+	}
+	if getEnv("SMTP_HOST", "") != "" {
+		config.Mail.SMTPHost = getEnv("SMTP_HOST", config.Mail.SMTPHost)
+	}
+	if getEnv("SMTP_PORT", "") != "" {
+		config.Mail.SMTPPort = getEnv("SMTP_PORT", config.Mail.SMTPPort)
+	}
+	if getEnv("SMTP_USERNAME", "") != "" {
+		config.Mail.SMTPUsername = getEnv("SMTP_USERNAME", config.Mail.SMTPUsername)
+	}
+	if getEnv("SMTP_PASSWORD", "") != "" {
+		config.Mail.SMTPPassword = getEnv("SMTP_PASSWORD", config.Mail.SMTPPassword)
+	}
+
+	// Хранилище для cron настраивается так же, как у основного сервера,
+	// ключи S3 обычно передаются только через окружение
+	if getEnv("STORAGE_TYPE", "") != "" {
+		config.Storage.Type = getEnv("STORAGE_TYPE", config.Storage.Type)
+	}
+	if getEnv("STORAGE_LOCAL_PATH", "") != "" {
+		config.Storage.LocalPath = getEnv("STORAGE_LOCAL_PATH", config.Storage.LocalPath)
+	}
+	if getEnv("STORAGE_BASE_URL", "") != "" {
+		config.Storage.BaseURL = getEnv("STORAGE_BASE_URL", config.Storage.BaseURL)
+	}
+	if getEnv("STORAGE_S3_ENDPOINT", "") != "" {
+		config.Storage.S3Endpoint = getEnv("STORAGE_S3_ENDPOINT", config.Storage.S3Endpoint)
+	}
+	if getEnv("STORAGE_S3_BUCKET", "") != "" {
+		config.Storage.S3Bucket = getEnv("STORAGE_S3_BUCKET", config.Storage.S3Bucket)
+	}
+	if getEnv("STORAGE_S3_ACCESS_KEY", "") != "" {
+		config.Storage.S3AccessKey = getEnv("STORAGE_S3_ACCESS_KEY", config.Storage.S3AccessKey)
+	}
+	if getEnv("STORAGE_S3_SECRET_KEY", "") != "" {
+		config.Storage.S3SecretKey = getEnv("STORAGE_S3_SECRET_KEY", config.Storage.S3SecretKey)
+	}
+	if getEnv("STORAGE_IMAGE_SIZES", "") != "" {
+		config.Storage.ImageSizes = getImageSizesEnv("STORAGE_IMAGE_SIZES", config.Storage.ImageSizes)
+	}
+
 	return &config, nil
 }
+
+// Validate проверяет параметры хранилища, без которых нельзя сопоставить
+// ссылки на изображения с файлами
+func (c *StorageConfig) Validate() error {
+	if c.BaseURL == "" {
+		return fmt.Errorf("storage.base_url is required")
+	}
+
+	switch c.Type {
+	case "local", "":
+		if c.LocalPath == "" {
+			return fmt.Errorf("storage.local_path is required for local storage")
+		}
+	case "s3":
+		if c.S3Endpoint == "" || c.S3Bucket == "" {
+			return fmt.Errorf("storage.s3_endpoint and storage.s3_bucket are required for s3 storage")
+		}
+		if c.S3AccessKey == "" || c.S3SecretKey == "" {
+			return fmt.Errorf("storage.s3_access_key and storage.s3_secret_key are required for s3 storage")
+		}
+	default:
+		return fmt.Errorf("unknown storage type: %s", c.Type)
+	}
+
+	return nil
+}
+
+// Validate проверяет параметры отправки писем
+func (c *MailConfig) Validate() error {
+	if c.From == "" {
+		return fmt.Errorf("mail.from is required")
+	}
+
+	switch c.Transport {
+	case "smtp":
+		if c.SMTPHost == "" || c.SMTPPort == "" {
+			return fmt.Errorf("mail.smtp_host and mail.smtp_port are required for smtp transport")
+		}
+	case "file", "":
+		if c.FileDir == "" {
+			return fmt.Errorf("mail.file_dir is required for file transport")
+		}
+	default:
+		return fmt.Errorf("unknown mail transport: %s", c.Transport)
+	}
+
+	return nil
+}
+
+// ParseTrustedProxies разбирает сети доверенных прокси; адрес без маски - один хост
+func (c *RateLimitConfig) ParseTrustedProxies() ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(c.TrustedProxies))
+	for _, value := range c.TrustedProxies {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid rate_limit.trusted_proxies entry: %q", value)
+			}
+			if ipv4 := ip.To4(); ipv4 != nil {
+				ip = ipv4
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate_limit.trusted_proxies entry: %q", value)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
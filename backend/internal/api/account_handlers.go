@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ExportProfile выгружает все данные пользователя: ZIP-архив (по умолчанию) или JSON (?format=json)
+func (h *Handlers) ExportProfile(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	export, err := h.accountService.Export(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to export account", zap.String("user_id", userID.String()))
+		return
+	}
+
+	fileName := fmt.Sprintf("startup-scout-export-%s", time.Now().Format("2006-01-02"))
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, fileName))
+		json.NewEncoder(w).Encode(export)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, fileName))
+	if err := h.accountService.WriteExportZip(r.Context(), export, w); err != nil {
+		// Заголовки уже отправлены, остается только залогировать
+		h.logger.Error("failed to write account export archive", zap.Error(err), zap.String("user_id", userID.String()))
+	}
+}
+
+// DeleteProfile планирует удаление аккаунта после льготного периода
+func (h *Handlers) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value("user").(*entities.User)
+
+	var request struct {
+		Password string `json:"password"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, r, errors.ErrInvalidBody)
+			return
+		}
+	}
+
+	if err := h.authService.VerifyPassword(user, request.Password); err != nil {
+		h.writeError(w, r, err, "failed to verify password")
+		return
+	}
+
+	deleteAt, err := h.accountService.RequestDeletion(r.Context(), user.ID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to schedule account deletion", zap.String("user_id", user.ID.String()))
+		return
+	}
+
+	h.logger.Info("account deletion scheduled",
+		zap.String("user_id", user.ID.String()),
+		zap.Time("deletion_scheduled_at", deleteAt))
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":                "deletion_scheduled",
+		"deletion_scheduled_at": deleteAt,
+	})
+}
+
+// RestoreProfile отменяет запланированное удаление аккаунта
+func (h *Handlers) RestoreProfile(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	if err := h.accountService.CancelDeletion(r.Context(), userID); err != nil {
+		h.writeError(w, r, err, "failed to cancel account deletion", zap.String("user_id", userID.String()))
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/services"
+	"startup-scout/internal/validation"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// GetCategories возвращает список категорий
+func (h *Handlers) GetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.categoryService.GetAll(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "failed to get categories")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"categories": categories,
+	})
+}
+
+// GetCategoryProjects возвращает рейтинг проектов категории в запуске
+// (по умолчанию текущем) с теми же фильтрами, что и GetProjects
+func (h *Handlers) GetCategoryProjects(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseProjectListFilter(w, r)
+	if !ok {
+		return
+	}
+
+	leaderboard, err := h.categoryService.Leaderboard(r.Context(), chi.URLParam(r, "slug"), filter)
+	if err != nil {
+		h.writeCategoryError(w, r, err, "failed to get category leaderboard")
+		return
+	}
+
+	json.NewEncoder(w).Encode(leaderboard)
+}
+
+// CreateCategory создает категорию (только для администраторов)
+func (h *Handlers) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var category entities.Category
+	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+
+	if err := h.categoryService.Create(r.Context(), &category); err != nil {
+		h.writeCategoryError(w, r, err, "failed to create category")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
+
+// UpdateCategory изменяет категорию; незаданные в запросе поля сохраняют текущие значения
+func (h *Handlers) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := parseCategoryID(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryService.GetByID(r.Context(), categoryID)
+	if err != nil {
+		h.writeCategoryError(w, r, err, "failed to get category")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(category); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+	category.ID = categoryID
+
+	if err := h.categoryService.Update(r.Context(), category); err != nil {
+		h.writeCategoryError(w, r, err, "failed to update category")
+		return
+	}
+
+	json.NewEncoder(w).Encode(category)
+}
+
+// DeleteCategory удаляет категорию; проекты категории остаются без нее
+func (h *Handlers) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := parseCategoryID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.categoryService.Delete(r.Context(), categoryID); err != nil {
+		h.writeCategoryError(w, r, err, "failed to delete category")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+func parseCategoryID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	categoryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid category ID"))
+		return uuid.Nil, false
+	}
+	return categoryID, true
+}
+
+// parseProjectListFilter разбирает фильтры списков проектов:
+// ?launch_id=...&category=<slug>&tag=...&platform=...&stage=...&pricing_model=...
+// Без launch_id используется текущий запуск.
+func parseProjectListFilter(w http.ResponseWriter, r *http.Request) (services.ProjectListFilter, bool) {
+	query := r.URL.Query()
+	filter := services.ProjectListFilter{
+		Category:     query.Get("category"),
+		Tag:          query.Get("tag"),
+		Platform:     query.Get("platform"),
+		Stage:        entities.ProjectStage(query.Get("stage")),
+		PricingModel: entities.PricingModel(query.Get("pricing_model")),
+	}
+
+	if raw := query.Get("launch_id"); raw != "" {
+		launchID, err := uuid.Parse(raw)
+		if err != nil {
+			writeError(w, r, errors.ErrInvalidID.WithMessage("invalid launch ID"))
+			return filter, false
+		}
+		filter.LaunchID = &launchID
+	}
+
+	return filter, true
+}
+
+// writeCategoryError отвечает на ошибку категорий; занятый адрес показывается как ошибка поля slug
+func (h *Handlers) writeCategoryError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	if stderrors.Is(err, errors.ErrCategorySlugExists) {
+		writeFieldErrors(w, r, validation.Errors{{
+			Field:   "slug",
+			Code:    validation.CodeTaken,
+			Message: "a category with this slug already exists",
+		}})
+		return
+	}
+
+	h.writeError(w, r, err, message)
+}
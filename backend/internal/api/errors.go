@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/validation"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// errorStatuses HTTP-статусы видов доменных ошибок
+var errorStatuses = map[errors.Kind]int{
+	errors.KindInternal:        http.StatusInternalServerError,
+	errors.KindInvalid:         http.StatusBadRequest,
+	errors.KindUnauthorized:    http.StatusUnauthorized,
+	errors.KindForbidden:       http.StatusForbidden,
+	errors.KindNotFound:        http.StatusNotFound,
+	errors.KindConflict:        http.StatusConflict,
+	errors.KindGone:            http.StatusGone,
+	errors.KindTooLarge:        http.StatusRequestEntityTooLarge,
+	errors.KindUnsupported:     http.StatusUnsupportedMediaType,
+	errors.KindTooManyRequests: http.StatusTooManyRequests,
+}
+
+// errorStatus возвращает HTTP-статус доменной ошибки
+func errorStatus(err *errors.Error) int {
+	if status, ok := errorStatuses[err.Kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError отвечает на ошибку JSON-конвертом:
+//
+//	{"error": "<код>", "message": "<сообщение>", "request_id": "<id>"}
+//
+// Ошибки валидации отвечают 422 со списком fields, доменные ошибки - статусом
+// своего вида, остальные скрываются за internal_error.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	writeErrorDetails(w, r, err, nil)
+}
+
+// writeErrorDetails как writeError, но добавляет в ответ подробности, например об исчерпанном лимите
+func writeErrorDetails(w http.ResponseWriter, r *http.Request, err error, details map[string]interface{}) {
+	var fieldErrors validation.Errors
+	if stderrors.As(err, &fieldErrors) {
+		writeFieldErrors(w, r, fieldErrors)
+		return
+	}
+
+	var lockoutErr *errors.LockoutError
+	if stderrors.As(err, &lockoutErr) {
+		writeRetryAfter(w, lockoutErr.RetryAfter)
+	}
+
+	domainErr := errors.ErrInternal
+	stderrors.As(err, &domainErr)
+
+	writeErrorResponse(w, r, errorStatus(domainErr), domainErr, details)
+}
+
+// writeFieldErrors отвечает 422 со списком ошибок по полям
+func writeFieldErrors(w http.ResponseWriter, r *http.Request, fieldErrors validation.Errors) {
+	writeErrorResponse(w, r, http.StatusUnprocessableEntity, errValidationFailed, map[string]interface{}{
+		"fields": fieldErrors,
+	})
+}
+
+// errValidationFailed код ответа с ошибками полей
+var errValidationFailed = errors.New(errors.KindInvalid, "validation_failed", "validation failed")
+
+// writeErrorResponse записывает конверт ошибки с заданным статусом
+func writeErrorResponse(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	err *errors.Error,
+	details map[string]interface{},
+) {
+	body := make(map[string]interface{}, len(details)+3)
+	for key, value := range details {
+		body[key] = value
+	}
+	body["error"] = err.Code
+	body["message"] = err.Message
+	if requestID := middleware.GetReqID(r.Context()); requestID != "" {
+		body["request_id"] = requestID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError отвечает на ошибку сервиса. Ошибки, не относящиеся к домену или
+// валидации, записываются в лог с message, fields и идентификатором запроса.
+func (h *Handlers) writeError(w http.ResponseWriter, r *http.Request, err error, message string, fields ...zap.Field) {
+	h.writeErrorDetails(w, r, err, message, nil, fields...)
+}
+
+// writeErrorDetails как writeError, но с подробностями в ответе
+func (h *Handlers) writeErrorDetails(
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+	message string,
+	details map[string]interface{},
+	fields ...zap.Field,
+) {
+	var domainErr *errors.Error
+	var fieldErrors validation.Errors
+	if !stderrors.As(err, &domainErr) && !stderrors.As(err, &fieldErrors) {
+		fields = append(fields, zap.Error(err), zap.String("request_id", middleware.GetReqID(r.Context())))
+		h.logger.Error(message, fields...)
+	}
+
+	writeErrorDetails(w, r, err, details)
+}
+
+// requestIDHeader возвращает идентификатор запроса в заголовке X-Request-Id,
+// чтобы его можно было сопоставить с логами. Используется после middleware.RequestID.
+func requestIDHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestID := middleware.GetReqID(r.Context()); requestID != "" {
+			w.Header().Set(middleware.RequestIDHeader, requestID)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// notFoundHandler отвечает на неизвестные маршруты
+func notFoundHandler(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, errors.ErrRouteNotFound)
+}
+
+// methodNotAllowedHandler отвечает на неподдерживаемые методы известных маршрутов
+func methodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	writeErrorResponse(w, r, http.StatusMethodNotAllowed, errMethodNotAllowed, nil)
+}
+
+// errMethodNotAllowed код ответа 405
+var errMethodNotAllowed = errors.New(errors.KindInvalid, "method_not_allowed", "method not allowed")
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// etagMiddleware добавляет ETag к успешным ответам GET и HEAD и отвечает 304,
+// если клиент прислал совпадающий If-None-Match. Ответ буферизуется, поэтому
+// middleware подходит только для небольших JSON-списков.
+func etagMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status != http.StatusOK {
+			w.WriteHeader(recorder.status)
+			w.Write(recorder.body.Bytes())
+			return
+		}
+
+		hash := sha256.Sum256(recorder.body.Bytes())
+		etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+
+		// Клиент может хранить ответ, но должен каждый раз сверять ETag
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "no-cache")
+
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(recorder.body.Bytes())
+	})
+}
+
+// etagMatches проверяет заголовок If-None-Match: список ETag через запятую или "*".
+// Слабые ETag (W/"...") сравниваются по значению.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bufferedResponse накапливает статус и тело ответа; заголовки пишутся в исходный ResponseWriter
+type bufferedResponse struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.wroteHeader {
+		return
+	}
+	b.status = status
+	b.wroteHeader = true
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(data)
+}
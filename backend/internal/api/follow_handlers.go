@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"startup-scout/internal/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FollowUser подписывает текущего пользователя на мейкера
+func (h *Handlers) FollowUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+	username := chi.URLParam(r, "username")
+
+	if err := h.followService.FollowUser(r.Context(), userID, username); err != nil {
+		h.writeError(w, r, err, "failed to follow user")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// UnfollowUser отменяет подписку на мейкера
+func (h *Handlers) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+	username := chi.URLParam(r, "username")
+
+	if err := h.followService.UnfollowUser(r.Context(), userID, username); err != nil {
+		h.writeError(w, r, err, "failed to unfollow user")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// FollowProject подписывает текущего пользователя на обсуждение проекта
+func (h *Handlers) FollowProject(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+	projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
+		return
+	}
+
+	if err := h.followService.FollowProject(r.Context(), userID, projectID); err != nil {
+		h.writeError(w, r, err, "failed to follow project")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// UnfollowProject отменяет подписку на проект
+func (h *Handlers) UnfollowProject(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+	projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
+		return
+	}
+
+	if err := h.followService.UnfollowProject(r.Context(), userID, projectID); err != nil {
+		h.writeError(w, r, err, "failed to unfollow project")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// GetFeed возвращает персональную ленту: ?cursor=...&limit=...
+func (h *Handlers) GetFeed(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	params, ok := parsePageParams(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.followService.GetFeed(r.Context(), userID, params)
+	if err != nil {
+		h.writeError(w, r, err, "failed to get feed", zap.String("user_id", userID.String()))
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
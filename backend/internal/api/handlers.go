@@ -3,14 +3,16 @@ package api
 import (
 	"database/sql"
 	"encoding/json"
+	stderrors "errors"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
 	"startup-scout/internal/entities"
 	"startup-scout/internal/errors"
 	"startup-scout/internal/repository"
 	"startup-scout/internal/services"
+	"startup-scout/internal/validation"
+	"startup-scout/pkg/storage"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -20,68 +22,102 @@ import (
 )
 
 type Handlers struct {
-	projectService *services.ProjectService
-	authService    *services.AuthService
-	commentService *services.CommentService
-	imageService   *services.ImageService
-	launchService  *services.LaunchService
-	userRepo       repository.UserRepository
-	logger         *zap.Logger
-	jwtAuth        *jwtauth.JWTAuth
+	projectService    *services.ProjectService
+	authService       *services.AuthService
+	twoFactor         *services.TwoFactorService
+	commentService    *services.CommentService
+	imageService      *services.ImageService
+	launchService     *services.LaunchService
+	userService       *services.UserService
+	accountService    *services.AccountService
+	followService     *services.FollowService
+	notifications     *services.NotificationService
+	newsletterService *services.NewsletterService
+	webhooks          *services.WebhookService
+	searchService     *services.SearchService
+	categoryService   *services.CategoryService
+	teamService       *services.TeamService
+	userRepo          repository.UserRepository
+	logger            *zap.Logger
+	jwtAuth           *jwtauth.JWTAuth
 }
 
 func NewHandlers(
 	projectService *services.ProjectService,
 	authService *services.AuthService,
+	twoFactor *services.TwoFactorService,
 	commentService *services.CommentService,
 	imageService *services.ImageService,
 	launchService *services.LaunchService,
+	userService *services.UserService,
+	accountService *services.AccountService,
+	followService *services.FollowService,
+	notifications *services.NotificationService,
+	newsletterService *services.NewsletterService,
+	webhooks *services.WebhookService,
+	searchService *services.SearchService,
+	categoryService *services.CategoryService,
+	teamService *services.TeamService,
 	userRepo repository.UserRepository,
 	logger *zap.Logger,
 	jwtAuth *jwtauth.JWTAuth,
 ) *Handlers {
 	return &Handlers{
-		projectService: projectService,
-		authService:    authService,
-		commentService: commentService,
-		imageService:   imageService,
-		launchService:  launchService,
-		userRepo:       userRepo,
-		logger:         logger,
-		jwtAuth:        jwtAuth,
+		projectService:    projectService,
+		authService:       authService,
+		twoFactor:         twoFactor,
+		commentService:    commentService,
+		imageService:      imageService,
+		launchService:     launchService,
+		userService:       userService,
+		accountService:    accountService,
+		followService:     followService,
+		notifications:     notifications,
+		newsletterService: newsletterService,
+		webhooks:          webhooks,
+		searchService:     searchService,
+		categoryService:   categoryService,
+		teamService:       teamService,
+		userRepo:          userRepo,
+		logger:            logger,
+		jwtAuth:           jwtAuth,
 	}
 }
 
 // Projects handlers
+// GetProjects возвращает страницу проектов текущего запуска или архивного (?launch_id=...)
+// в порядке рейтинга, с фильтрами (см. parseProjectListFilter) и пагинацией ?cursor=...&limit=...
 func (h *Handlers) GetProjects(w http.ResponseWriter, r *http.Request) {
-	projects, err := h.projectService.GetActiveLaunchProjects(r.Context())
-	if err != nil {
-		h.logger.Error("failed to get projects", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	filter, ok := parseProjectListFilter(w, r)
+	if !ok {
 		return
 	}
 
-	if projects == nil {
-		projects = []*entities.Project{}
+	params, ok := parsePageParams(w, r)
+	if !ok {
+		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"projects": projects,
-	})
+	page, err := h.projectService.GetProjects(r.Context(), filter, params)
+	if err != nil {
+		h.writeCategoryError(w, r, err, "failed to get projects")
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
 }
 
 func (h *Handlers) GetProject(w http.ResponseWriter, r *http.Request) {
 	projectIDStr := chi.URLParam(r, "id")
 	projectID, err := uuid.Parse(projectIDStr)
 	if err != nil {
-		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
 		return
 	}
 
 	project, err := h.projectService.GetProject(r.Context(), projectID)
 	if err != nil {
-		h.logger.Error("failed to get project", zap.Error(err), zap.String("project_id", projectIDStr))
-		http.Error(w, "Project not found", http.StatusNotFound)
+		h.writeError(w, r, err, "failed to get project", zap.String("project_id", projectIDStr))
 		return
 	}
 
@@ -92,32 +128,22 @@ func (h *Handlers) CreateProject(w http.ResponseWriter, r *http.Request) {
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		h.logger.Error("failed to read request body", zap.Error(err))
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidBody)
 		return
 	}
-	
-	h.logger.Info("received project creation request", 
+
+	h.logger.Info("received project creation request",
 		zap.String("content_type", r.Header.Get("Content-Type")),
 		zap.String("body", string(bodyBytes)))
 
-	var requestData struct {
-		Name            string   `json:"name"`
-		Description     string   `json:"description"`
-		FullDescription string   `json:"full_description"`
-		Logo            *string  `json:"logo"`
-		Images          []string `json:"images"`
-		Creators        []string `json:"creators"`
-		TelegramContact string   `json:"telegram_contact"`
-		Website         string   `json:"website"`
-	}
-
+	var requestData projectRequest
 	if err := json.Unmarshal(bodyBytes, &requestData); err != nil {
 		h.logger.Error("failed to decode request body", zap.Error(err), zap.String("body", string(bodyBytes)))
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidBody)
 		return
 	}
 
-	h.logger.Info("successfully decoded project request", 
+	h.logger.Info("successfully decoded project request",
 		zap.String("name", requestData.Name),
 		zap.String("description", requestData.Description),
 		zap.String("telegram_contact", requestData.TelegramContact),
@@ -128,22 +154,11 @@ func (h *Handlers) CreateProject(w http.ResponseWriter, r *http.Request) {
 	// Получаем пользователя из контекста (после аутентификации)
 	userID := r.Context().Value("user_id").(uuid.UUID)
 
-	// Создаем проект с правильными типами данных
-	project := entities.Project{
-		Name:            requestData.Name,
-		Description:     requestData.Description,
-		FullDescription: requestData.FullDescription,
-		Logo:            requestData.Logo,
-		Images:          entities.StringArray(requestData.Images),
-		Creators:        entities.StringArray(requestData.Creators),
-		TelegramContact: sql.NullString{String: requestData.TelegramContact, Valid: requestData.TelegramContact != ""},
-		Website:         sql.NullString{String: requestData.Website, Valid: requestData.Website != ""},
-		UserID:          userID,
-	}
+	project := requestData.project()
+	project.UserID = userID
 
-	if err := h.projectService.CreateProject(r.Context(), &project); err != nil {
-		h.logger.Error("failed to create project", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	if err := h.projectService.CreateProject(r.Context(), project); err != nil {
+		h.writeError(w, r, err, "failed to create project")
 		return
 	}
 
@@ -151,12 +166,81 @@ func (h *Handlers) CreateProject(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(project)
 }
 
+// UpdateProject заменяет поля проекта (доступно владельцу и соавторам)
+func (h *Handlers) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
+		return
+	}
+
+	var requestData projectRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	project := requestData.project()
+	project.ID = projectID
+
+	if err := h.projectService.UpdateProject(r.Context(), userID, project); err != nil {
+		h.writeError(w, r, err, "failed to update project")
+		return
+	}
+
+	json.NewEncoder(w).Encode(project)
+}
+
+// projectRequest тело запросов создания и изменения проекта
+type projectRequest struct {
+	Name            string                 `json:"name"`
+	Tagline         string                 `json:"tagline"`
+	Description     string                 `json:"description"`
+	FullDescription string                 `json:"full_description"`
+	Logo            *string                `json:"logo"`
+	Images          []string               `json:"images"`
+	Gallery         []entities.GalleryItem `json:"gallery"`
+	Creators        []string               `json:"creators"`
+	TelegramContact string                 `json:"telegram_contact"`
+	Website         string                 `json:"website"`
+	CategoryID      *uuid.UUID             `json:"category_id"`
+	Tags            []string               `json:"tags"`
+	Links           []entities.ProjectLink `json:"links"`
+	PricingModel    string                 `json:"pricing_model"`
+	Platforms       []string               `json:"platforms"`
+	Stage           string                 `json:"stage"`
+}
+
+// project создает проект с правильными типами данных
+func (req *projectRequest) project() *entities.Project {
+	return &entities.Project{
+		Name:            req.Name,
+		Tagline:         req.Tagline,
+		Description:     req.Description,
+		FullDescription: req.FullDescription,
+		Logo:            req.Logo,
+		Images:          entities.StringArray(req.Images),
+		Gallery:         entities.ProjectGallery(req.Gallery),
+		Creators:        entities.StringArray(req.Creators),
+		TelegramContact: sql.NullString{String: req.TelegramContact, Valid: req.TelegramContact != ""},
+		Website:         sql.NullString{String: req.Website, Valid: req.Website != ""},
+		CategoryID:      req.CategoryID,
+		Tags:            entities.StringArray(req.Tags),
+		Links:           entities.ProjectLinks(req.Links),
+		PricingModel:    entities.PricingModel(req.PricingModel),
+		Platforms:       entities.StringArray(req.Platforms),
+		Stage:           entities.ProjectStage(req.Stage),
+	}
+}
+
 func (h *Handlers) Vote(w http.ResponseWriter, r *http.Request) {
 	projectIDStr := chi.URLParam(r, "id")
 	projectID, err := uuid.Parse(projectIDStr)
 	if err != nil {
 		h.logger.Error("failed to parse project ID", zap.Error(err), zap.String("project_id", projectIDStr))
-		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
 		return
 	}
 
@@ -170,10 +254,9 @@ func (h *Handlers) Vote(w http.ResponseWriter, r *http.Request) {
 		zap.String("project_id", projectIDStr))
 
 	if err := h.projectService.Vote(r.Context(), userID, projectID); err != nil {
-		h.logger.Error("failed to vote", zap.Error(err),
+		h.writeError(w, r, err, "failed to vote",
 			zap.String("user_id", userID.String()),
 			zap.String("project_id", projectIDStr))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -191,7 +274,7 @@ func (h *Handlers) RemoveVote(w http.ResponseWriter, r *http.Request) {
 	projectID, err := uuid.Parse(projectIDStr)
 	if err != nil {
 		h.logger.Error("failed to parse project ID for remove vote", zap.Error(err), zap.String("project_id", projectIDStr))
-		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
 		return
 	}
 
@@ -203,10 +286,9 @@ func (h *Handlers) RemoveVote(w http.ResponseWriter, r *http.Request) {
 		zap.String("project_id", projectIDStr))
 
 	if err := h.projectService.RemoveVote(r.Context(), userID, projectID); err != nil {
-		h.logger.Error("failed to remove vote", zap.Error(err),
+		h.writeError(w, r, err, "failed to remove vote",
 			zap.String("user_id", userID.String()),
 			zap.String("project_id", projectIDStr))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -227,62 +309,21 @@ func (h *Handlers) RegisterEmail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidBody)
 		return
 	}
 
 	user, err := h.authService.RegisterEmail(r.Context(), request.Email, request.Username, request.Password)
 	if err != nil {
-		h.logger.Error("failed to register user", zap.Error(err))
-
-		var errorMessage string
-		var statusCode int
-
-		switch err {
-		case errors.ErrEmailExists:
-			errorMessage = "Пользователь с таким email уже существует"
-			statusCode = http.StatusConflict
-		case errors.ErrUsernameExists:
-			errorMessage = "Пользователь с таким именем уже существует"
-			statusCode = http.StatusConflict
-		default:
-			errorMessage = "Ошибка регистрации"
-			statusCode = http.StatusInternalServerError
-		}
-
-		http.Error(w, errorMessage, statusCode)
+		h.writeError(w, r, err, "failed to register user")
 		return
 	}
 
-	// Создаем JWT с расширенным payload (включая данные пользователя)
-	claims := map[string]interface{}{
-		"user_id":   user.ID,
-		"email":     user.Email,
-		"username":  user.Username,
-		"avatar":    user.Avatar,
-		"first_name": user.FirstName,
-		"last_name":  user.LastName,
-		"exp":       time.Now().Add(24 * time.Hour).Unix(),
-	}
-	
-	_, tokenString, err := h.jwtAuth.Encode(claims)
-	if err != nil {
-		h.logger.Error("failed to create JWT token", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	if err := h.setSessionCookie(w, r, user); err != nil {
+		h.writeError(w, r, err, "failed to create JWT token")
 		return
 	}
 
-	// Устанавливаем HTTP-only cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     "auth_token",
-		Value:    tokenString,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   true, // только для HTTPS
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   24 * 60 * 60, // 24 часа
-	})
-
 	// Возвращаем только данные пользователя (без токена)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"user": user,
@@ -297,52 +338,64 @@ func (h *Handlers) AuthEmail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidBody)
 		return
 	}
 
 	user, err := h.authService.AuthenticateEmail(r.Context(), request.Email, request.Password)
 	if err != nil {
-		h.logger.Error("failed to authenticate email", zap.Error(err))
-
-		var errorMessage string
-		var statusCode int
-
-		switch err {
-		case errors.ErrUserNotFound:
-			errorMessage = "Пользователь с таким email или именем пользователя не найден"
-			statusCode = http.StatusNotFound
-		case errors.ErrInvalidPassword:
-			errorMessage = "Неверный пароль"
-			statusCode = http.StatusUnauthorized
-		default:
-			errorMessage = "Ошибка аутентификации"
-			statusCode = http.StatusInternalServerError
+		h.writeError(w, r, err, "failed to authenticate email")
+		return
+	}
+
+	// При включенной 2FA сессия выдается только после проверки второго фактора
+	if user.TOTPEnabled {
+		pendingToken, err := h.issuePendingTwoFactorToken(r, user)
+		if err != nil {
+			h.writeError(w, r, err, "failed to create pending 2FA token")
+			return
 		}
 
-		http.Error(w, errorMessage, statusCode)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"two_factor_required": true,
+			"pending_token":       pendingToken,
+		})
+		return
+	}
+
+	if err := h.setSessionCookie(w, r, user); err != nil {
+		h.writeError(w, r, err, "failed to create JWT token")
 		return
 	}
 
-	// Создаем JWT с расширенным payload (включая данные пользователя)
+	// Возвращаем только данные пользователя (без токена)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user": user,
+	})
+}
+
+// setSessionCookie сохраняет сессию, выпускает JWT и устанавливает его в HTTP-only cookie.
+// В токене хранятся только идентификаторы пользователя и сессии, профиль
+// загружается из базы, а сессия проверяется при каждом запросе.
+func (h *Handlers) setSessionCookie(w http.ResponseWriter, r *http.Request, user *entities.User) error {
+	sessionID, expiresAt, err := h.authService.StartSession(r.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+
 	claims := map[string]interface{}{
-		"user_id":   user.ID,
-		"email":     user.Email,
-		"username":  user.Username,
-		"avatar":    user.Avatar,
-		"first_name": user.FirstName,
-		"last_name":  user.LastName,
-		"exp":       time.Now().Add(24 * time.Hour).Unix(),
-	}
-	
+		"user_id": user.ID.String(),
+		"typ":     tokenTypeSession,
+		"sid":     sessionID.String(),
+		"iat":     time.Now().Unix(),
+		"exp":     expiresAt.Unix(),
+	}
+
 	_, tokenString, err := h.jwtAuth.Encode(claims)
 	if err != nil {
-		h.logger.Error("failed to create JWT token", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		return err
 	}
 
-	// Устанавливаем HTTP-only cookie
 	http.SetCookie(w, &http.Cookie{
 		Name:     "auth_token",
 		Value:    tokenString,
@@ -350,13 +403,10 @@ func (h *Handlers) AuthEmail(w http.ResponseWriter, r *http.Request) {
 		HttpOnly: true,
 		Secure:   true, // только для HTTPS
 		SameSite: http.SameSiteLaxMode,
-		MaxAge:   24 * 60 * 60, // 24 часа
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
 	})
 
-	// Возвращаем только данные пользователя (без токена)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"user": user,
-	})
+	return nil
 }
 
 // Связывание Telegram с пользователем
@@ -367,13 +417,12 @@ func (h *Handlers) LinkTelegram(w http.ResponseWriter, r *http.Request) {
 	var data map[string]string
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		h.logger.Error("failed to decode telegram link data", zap.Error(err))
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidBody)
 		return
 	}
 
 	if err := h.authService.LinkTelegramToUser(r.Context(), userID, data); err != nil {
-		h.logger.Error("failed to link telegram", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		h.writeError(w, r, err, "failed to link telegram")
 		return
 	}
 
@@ -381,16 +430,29 @@ func (h *Handlers) LinkTelegram(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handlers) GetProfile(w http.ResponseWriter, r *http.Request) {
-	// Получаем пользователя из JWT claims (кеш в JWT)
+	// Пользователь загружен из базы в userContextMiddleware
 	user := r.Context().Value("user").(*entities.User)
-	
+
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"user": user,
 	})
 }
 
-// Logout очищает cookie аутентификации
+// Logout отзывает сессию и очищает cookie аутентификации. Маршрут публичный,
+// поэтому недействительный или истекший токен просто игнорируется.
 func (h *Handlers) Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie("auth_token"); err == nil && cookie.Value != "" {
+		if token, err := jwtauth.VerifyToken(h.jwtAuth, cookie.Value); err == nil {
+			sid, _ := token.PrivateClaims()["sid"].(string)
+			if sessionID, err := uuid.Parse(sid); err == nil {
+				if err := h.authService.EndSession(r.Context(), sessionID); err != nil {
+					h.writeError(w, r, err, "failed to revoke session")
+					return
+				}
+			}
+		}
+	}
+
 	// Очищаем cookie
 	http.SetCookie(w, &http.Cookie{
 		Name:     "auth_token",
@@ -401,7 +463,7 @@ func (h *Handlers) Logout(w http.ResponseWriter, r *http.Request) {
 		SameSite: http.SameSiteLaxMode,
 		MaxAge:   -1, // Удаляем cookie
 	})
-	
+
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "Logged out successfully",
 	})
@@ -410,43 +472,83 @@ func (h *Handlers) Logout(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) GetUserVotes(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value("user_id").(uuid.UUID)
 
-	votes, err := h.projectService.GetUserVotes(r.Context(), userID)
+	params, ok := parsePageParams(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.projectService.GetUserVotes(r.Context(), userID, params)
 	if err != nil {
-		h.logger.Error("failed to get user votes", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, err, "failed to get user votes")
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"votes": votes,
-	})
+	json.NewEncoder(w).Encode(page)
 }
 
 func (h *Handlers) GetUserProjects(w http.ResponseWriter, r *http.Request) {
 	userIDStr := chi.URLParam(r, "id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid user ID"))
 		return
 	}
 
 	// Проверяем, что пользователь запрашивает свои проекты
 	requestingUserID := r.Context().Value("user_id").(uuid.UUID)
 	if requestingUserID != userID {
-		http.Error(w, "Forbidden", http.StatusForbidden)
+		writeError(w, r, errors.ErrForbidden)
+		return
+	}
+
+	params, ok := parsePageParams(w, r)
+	if !ok {
 		return
 	}
 
-	projects, err := h.projectService.GetUserProjects(r.Context(), userID)
+	page, err := h.projectService.GetUserProjects(r.Context(), userID, params)
 	if err != nil {
-		h.logger.Error("failed to get user projects", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, err, "failed to get user projects")
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"projects": projects,
-	})
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetPublicProfile возвращает публичный профиль пользователя по username
+func (h *Handlers) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+
+	profile, err := h.userService.GetPublicProfile(r.Context(), username)
+	if err != nil {
+		h.writeError(w, r, err, "failed to get public profile", zap.String("username", username))
+		return
+	}
+
+	json.NewEncoder(w).Encode(profile)
+}
+
+// UpdatePrivacy обновляет настройки приватности профиля
+func (h *Handlers) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value("user").(*entities.User)
+
+	// Незаданные в запросе поля сохраняют текущие значения
+	settings := entities.PrivacySettings{
+		ProfilePublic: user.ProfilePublic,
+		ShowTelegram:  user.ShowTelegram,
+		ShowActivity:  user.ShowActivity,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+
+	if err := h.userService.UpdatePrivacy(r.Context(), user.ID, settings); err != nil {
+		h.writeError(w, r, err, "failed to update privacy settings")
+		return
+	}
+
+	json.NewEncoder(w).Encode(settings)
 }
 
 // Comment handlers
@@ -454,31 +556,29 @@ func (h *Handlers) GetProjectComments(w http.ResponseWriter, r *http.Request) {
 	projectIDStr := chi.URLParam(r, "id")
 	projectID, err := uuid.Parse(projectIDStr)
 	if err != nil {
-		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
 		return
 	}
 
-	comments, err := h.commentService.GetProjectCommentsWithUsers(r.Context(), projectID)
-	if err != nil {
-		h.logger.Error("failed to get project comments", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	params, ok := parsePageParams(w, r)
+	if !ok {
 		return
 	}
 
-	if comments == nil {
-		comments = []*entities.CommentWithUser{}
+	page, err := h.commentService.GetProjectCommentsWithUsers(r.Context(), projectID, params)
+	if err != nil {
+		h.writeError(w, r, err, "failed to get project comments")
+		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"comments": comments,
-	})
+	json.NewEncoder(w).Encode(page)
 }
 
 func (h *Handlers) CreateComment(w http.ResponseWriter, r *http.Request) {
 	projectIDStr := chi.URLParam(r, "id")
 	projectID, err := uuid.Parse(projectIDStr)
 	if err != nil {
-		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
 		return
 	}
 
@@ -486,12 +586,7 @@ func (h *Handlers) CreateComment(w http.ResponseWriter, r *http.Request) {
 		Content string `json:"content"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if request.Content == "" {
-		http.Error(w, "Comment content is required", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidBody)
 		return
 	}
 
@@ -499,8 +594,7 @@ func (h *Handlers) CreateComment(w http.ResponseWriter, r *http.Request) {
 
 	comment, err := h.commentService.CreateComment(r.Context(), userID, projectID, request.Content)
 	if err != nil {
-		h.logger.Error("failed to create comment", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, err, "failed to create comment")
 		return
 	}
 
@@ -512,7 +606,7 @@ func (h *Handlers) UpdateComment(w http.ResponseWriter, r *http.Request) {
 	commentIDStr := chi.URLParam(r, "commentId")
 	commentID, err := uuid.Parse(commentIDStr)
 	if err != nil {
-		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid comment ID"))
 		return
 	}
 
@@ -520,20 +614,14 @@ func (h *Handlers) UpdateComment(w http.ResponseWriter, r *http.Request) {
 		Content string `json:"content"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if request.Content == "" {
-		http.Error(w, "Comment content is required", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidBody)
 		return
 	}
 
 	userID := r.Context().Value("user_id").(uuid.UUID)
 
 	if err := h.commentService.UpdateComment(r.Context(), commentID, userID, request.Content); err != nil {
-		h.logger.Error("failed to update comment", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, err, "failed to update comment")
 		return
 	}
 
@@ -545,7 +633,7 @@ func (h *Handlers) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	commentIDStr := chi.URLParam(r, "commentId")
 	commentID, err := uuid.Parse(commentIDStr)
 	if err != nil {
-		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid comment ID"))
 		return
 	}
 
@@ -553,8 +641,7 @@ func (h *Handlers) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value("user_id").(uuid.UUID)
 
 	if err := h.commentService.DeleteComment(r.Context(), commentID, userID); err != nil {
-		h.logger.Error("failed to delete comment", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, err, "failed to delete comment")
 		return
 	}
 
@@ -564,27 +651,17 @@ func (h *Handlers) DeleteComment(w http.ResponseWriter, r *http.Request) {
 
 // GetStats возвращает общую статистику сайта
 func (h *Handlers) GetStats(w http.ResponseWriter, r *http.Request) {
-	// Убеждаемся, что есть активный запуск
-	_, err := h.launchService.EnsureActiveLaunch(r.Context())
-	if err != nil {
-		h.logger.Error("failed to ensure active launch", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	// Получаем количество активных пользователей
+	// Количество активных пользователей и проекты запуска кешируются на короткое время
 	userCount, err := h.userRepo.GetTotalCount(r.Context())
 	if err != nil {
-		h.logger.Error("failed to get user count", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, err, "failed to get user count")
 		return
 	}
 
 	// Получаем количество активных проектов
 	projects, err := h.projectService.GetActiveLaunchProjects(r.Context())
 	if err != nil {
-		h.logger.Error("failed to get projects for stats", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, err, "failed to get projects for stats")
 		return
 	}
 
@@ -601,49 +678,40 @@ func (h *Handlers) GetStats(w http.ResponseWriter, r *http.Request) {
 
 // Image handlers
 func (h *Handlers) UploadImage(w http.ResponseWriter, r *http.Request) {
-	// Проверяем аутентификацию
-	_, claims, err := jwtauth.FromContext(r.Context())
-	if err != nil {
-		h.logger.Error("failed to get JWT claims", zap.Error(err))
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	userIDStr, ok := claims["user_id"].(string)
-	if !ok {
-		h.logger.Error("user_id not found in JWT claims")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		h.logger.Error("invalid user_id in JWT claims", zap.Error(err))
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	// Парсим multipart form
-	err = r.ParseMultipartForm(32 << 20) // 32 MB max
-	if err != nil {
+	// Получаем пользователя из контекста (после аутентификации)
+	userID := r.Context().Value("user_id").(uuid.UUID)
+	maxFileSize := h.imageService.GetConfig().MaxFileSize
+
+	// Тело ограничивается заранее, чтобы не принимать на диск файлы сверх лимита
+	r.Body = http.MaxBytesReader(w, r.Body, maxFileSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if stderrors.As(err, &maxBytesErr) {
+			h.writeUploadError(w, r, errors.ErrFileTooLarge, userID, nil, maxFileSize)
+			return
+		}
 		h.logger.Error("failed to parse multipart form", zap.Error(err))
-		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidRequest.WithMessage("failed to parse multipart form"))
 		return
 	}
 
 	file, fileHeader, err := r.FormFile("image")
 	if err != nil {
 		h.logger.Error("failed to get uploaded file", zap.Error(err))
-		http.Error(w, "No image file provided", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidRequest.WithMessage("no image file provided"))
 		return
 	}
 	defer file.Close()
 
+	projectImages, ok := h.checkProjectGalleryQuota(w, r, userID, maxFileSize)
+	if !ok {
+		return
+	}
+
 	// Загружаем изображение
-	fileName, err := h.imageService.UploadImage(fileHeader)
+	fileName, err := h.imageService.UploadImage(r.Context(), userID, fileHeader)
 	if err != nil {
-		h.logger.Error("failed to upload image", zap.Error(err))
-		http.Error(w, "Failed to upload image", http.StatusInternalServerError)
+		h.writeUploadError(w, r, err, userID, projectImages, maxFileSize)
 		return
 	}
 
@@ -662,10 +730,16 @@ func (h *Handlers) UploadImage(w http.ResponseWriter, r *http.Request) {
 		"image_url": imageURL,
 	}
 
+	quota, err := h.imageService.GetUploadQuota(r.Context(), userID)
+	if err != nil {
+		h.logger.Warn("failed to get upload quota", zap.Error(err))
+	} else {
+		response["quota"] = quota
+	}
+
 	jsonData, err := json.Marshal(response)
 	if err != nil {
-		h.logger.Error("failed to marshal response", zap.Error(err))
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, err, "failed to marshal response")
 		return
 	}
 
@@ -673,54 +747,238 @@ func (h *Handlers) UploadImage(w http.ResponseWriter, r *http.Request) {
 	w.Write(jsonData)
 }
 
-func (h *Handlers) GetImage(w http.ResponseWriter, r *http.Request) {
-	fileName := chi.URLParam(r, "filename")
-	h.logger.Info("GetImage called", zap.String("filename", fileName), zap.String("method", r.Method))
+// UploadVideo загружает ролик mp4/webm для галереи проекта
+func (h *Handlers) UploadVideo(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+	maxFileSize := h.imageService.GetConfig().MaxVideoSize
+
+	// Тело ограничивается заранее, чтобы не принимать на диск файлы сверх лимита
+	r.Body = http.MaxBytesReader(w, r.Body, maxFileSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if stderrors.As(err, &maxBytesErr) {
+			h.writeUploadError(w, r, errors.ErrFileTooLarge, userID, nil, maxFileSize)
+			return
+		}
+		writeError(w, r, errors.ErrInvalidRequest.WithMessage("failed to parse multipart form"))
+		return
+	}
 
-	if fileName == "" {
-		h.logger.Error("Filename is empty")
-		http.Error(w, "Filename is required", http.StatusBadRequest)
+	file, fileHeader, err := r.FormFile("video")
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidRequest.WithMessage("no video file provided"))
 		return
 	}
+	defer file.Close()
 
-	// Проверяем, что файл существует (для безопасности)
-	filePath := filepath.Join(h.imageService.GetConfig().LocalPath, fileName)
-	h.logger.Info("Looking for file", zap.String("file_path", filePath))
+	projectImages, ok := h.checkProjectGalleryQuota(w, r, userID, maxFileSize)
+	if !ok {
+		return
+	}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		h.logger.Error("File not found", zap.String("file_path", filePath), zap.Error(err))
-		http.Error(w, "Image not found", http.StatusNotFound)
+	upload, err := h.imageService.UploadVideo(r.Context(), userID, fileHeader)
+	if err != nil {
+		h.writeUploadError(w, r, err, userID, projectImages, maxFileSize)
 		return
 	}
 
-	h.logger.Info("Serving file", zap.String("file_path", filePath))
-	// Отдаем файл
-	http.ServeFile(w, r, filePath)
+	response := map[string]interface{}{
+		"success":   true,
+		"file_name": upload.FileName,
+		"video_url": h.imageService.GetImageURL(upload.FileName),
+		"duration":  upload.Duration.Seconds(),
+	}
+
+	quota, err := h.imageService.GetUploadQuota(r.Context(), userID)
+	if err != nil {
+		h.logger.Warn("failed to get upload quota", zap.Error(err))
+	} else {
+		response["quota"] = quota
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
-// UpdateAvatar обновляет аватарку пользователя
-func (h *Handlers) UpdateAvatar(w http.ResponseWriter, r *http.Request) {
-	// Проверяем аутентификацию
-	_, claims, err := jwtauth.FromContext(r.Context())
+// checkProjectGalleryQuota проверяет необязательный project_id формы: загрузка в
+// галерею существующего проекта ограничена тем же лимитом, что и при создании.
+// При ошибке ответ уже записан и возвращается false.
+func (h *Handlers) checkProjectGalleryQuota(
+	w http.ResponseWriter,
+	r *http.Request,
+	userID uuid.UUID,
+	maxFileSize int64,
+) (*entities.QuotaUsage, bool) {
+	value := r.FormValue("project_id")
+	if value == "" {
+		return nil, true
+	}
+
+	projectID, err := uuid.Parse(value)
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
+		return nil, false
+	}
+
+	projectImages, err := h.projectService.ProjectImageQuota(r.Context(), userID, projectID)
+	if err != nil {
+		h.writeUploadError(w, r, err, userID, projectImages, maxFileSize)
+		return nil, false
+	}
+
+	return projectImages, true
+}
+
+// GetUploadQuota возвращает квоты загрузок текущего пользователя и их остаток
+func (h *Handlers) GetUploadQuota(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	quota, err := h.imageService.GetUploadQuota(r.Context(), userID)
 	if err != nil {
-		h.logger.Error("failed to get JWT claims", zap.Error(err))
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		h.writeError(w, r, err, "failed to get upload quota")
 		return
 	}
 
-	userIDStr, ok := claims["user_id"].(string)
-	if !ok {
-		h.logger.Error("user_id not found in JWT claims")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quota)
+}
+
+// writeUploadError отвечает на ошибку загрузки. Превышение размера файла, общей
+// квоты и лимита галереи - 413, суточной квоты - 429 с Retry-After; в ответ
+// добавляются лимиты и остаток квот.
+func (h *Handlers) writeUploadError(
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+	userID uuid.UUID,
+	projectImages *entities.QuotaUsage,
+	maxFileSize int64,
+) {
+	storageConfig := h.imageService.GetConfig()
+
+	var details map[string]interface{}
+	switch {
+	case stderrors.Is(err, errors.ErrFileTooLarge):
+		details = map[string]interface{}{"max_file_size": maxFileSize}
+	case stderrors.Is(err, errors.ErrVideoTooLong):
+		details = map[string]interface{}{"max_duration": storageConfig.MaxVideoDuration.Seconds()}
+	case stderrors.Is(err, errors.ErrInvalidVideo):
+		h.logger.Info("rejected invalid video upload", zap.Error(err))
+		details = map[string]interface{}{"allowed_types": storageConfig.AllowedVideoTypes}
+	case stderrors.Is(err, errors.ErrImageDimensionsTooLarge):
+		details = map[string]interface{}{
+			"max_image_side":   storageConfig.MaxImageSide,
+			"max_image_pixels": storageConfig.MaxImagePixels,
+		}
+	case stderrors.Is(err, errors.ErrInvalidImage):
+		h.logger.Info("rejected invalid image upload", zap.Error(err))
+		details = map[string]interface{}{"allowed_types": storageConfig.AllowedTypes}
+	case stderrors.Is(err, errors.ErrProjectImageLimit):
+		details = map[string]interface{}{"project_images": projectImages}
+	case stderrors.Is(err, errors.ErrUploadQuotaExceeded), stderrors.Is(err, errors.ErrDailyUploadQuotaExceeded):
+		quota, quotaErr := h.imageService.GetUploadQuota(r.Context(), userID)
+		if quotaErr != nil {
+			h.logger.Warn("failed to get upload quota", zap.Error(quotaErr))
+		}
+
+		if stderrors.Is(err, errors.ErrDailyUploadQuotaExceeded) && quota != nil && quota.DailyResetAt != nil {
+			writeRetryAfter(w, time.Until(*quota.DailyResetAt))
+		}
+		details = map[string]interface{}{"quota": quota}
+	}
+
+	h.writeErrorDetails(w, r, err, "failed to upload file", details)
+}
+
+func (h *Handlers) GetImage(w http.ResponseWriter, r *http.Request) {
+	// Браузер не должен угадывать тип по содержимому: файл отдается только как картинка
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	fileName := chi.URLParam(r, "filename")
+	if fileName == "" {
+		writeError(w, r, errors.ErrInvalidFileName)
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	// ?size= выбирает уменьшенный вариант из StorageConfig.ImageSizes
+	key, err := h.imageService.ResolveImage(r.Context(), fileName, r.URL.Query().Get("size"))
+	if err != nil {
+		h.writeImageError(w, r, err, fileName)
+		return
+	}
+
+	// Файлы, названные по хешу содержимого, не меняются: кешируем навсегда и
+	// отвечаем 304 без обращения к хранилищу
+	if services.IsContentAddressed(key) {
+		etag := `"` + key + `"`
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("ETag", etag)
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+	}
+
+	// Файл можно отдать напрямую из хранилища по временной ссылке
+	if h.imageService.GetConfig().PresignRedirect {
+		presignedURL, err := h.imageService.PresignImageURL(r.Context(), key)
+		if err != nil {
+			h.writeImageError(w, r, err, key)
+			return
+		}
+		// Ссылка действует ограниченное время, поэтому редирект не кешируется
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Del("ETag")
+		http.Redirect(w, r, presignedURL, http.StatusFound)
+		return
+	}
+
+	src, object, err := h.imageService.OpenImage(r.Context(), key)
 	if err != nil {
-		h.logger.Error("invalid user_id in JWT claims", zap.Error(err))
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		h.writeImageError(w, r, err, key)
+		return
+	}
+	defer src.Close()
+
+	// Тип определяется по расширению сохраненного файла, а не по данным хранилища
+	w.Header().Set("Content-Type", services.UploadContentType(key))
+
+	// Локальные файлы поддерживают Range-запросы, объекты S3 отдаются потоком
+	if seeker, ok := src.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, key, object.ModTime, seeker)
+		return
+	}
+
+	if !object.ModTime.IsZero() {
+		w.Header().Set("Last-Modified", object.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if object.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	}
+	if r.Method == http.MethodHead {
 		return
 	}
+	io.Copy(w, src)
+}
+
+// writeImageError отвечает на ошибку выдачи файла; ошибки хранилища переводятся в доменные
+func (h *Handlers) writeImageError(w http.ResponseWriter, r *http.Request, err error, fileName string) {
+	switch {
+	case stderrors.Is(err, storage.ErrNotFound):
+		err = errors.ErrImageNotFound
+	case stderrors.Is(err, storage.ErrInvalidKey):
+		err = errors.ErrInvalidFileName
+	}
+
+	h.writeError(w, r, err, "failed to open image", zap.String("file_name", fileName))
+}
+
+// UpdateAvatar обновляет аватарку пользователя
+func (h *Handlers) UpdateAvatar(w http.ResponseWriter, r *http.Request) {
+	// Получаем пользователя из контекста (после аутентификации)
+	userID := r.Context().Value("user_id").(uuid.UUID)
 
 	// Парсим JSON запрос
 	var request struct {
@@ -729,20 +987,14 @@ func (h *Handlers) UpdateAvatar(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		h.logger.Error("failed to decode request", zap.Error(err))
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if request.Avatar == "" {
-		http.Error(w, "Avatar URL is required", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidBody)
 		return
 	}
 
 	// Обновляем аватарку пользователя
-	err = h.userRepo.UpdateAvatar(r.Context(), userID, request.Avatar)
+	err := h.userService.UpdateAvatar(r.Context(), userID, request.Avatar)
 	if err != nil {
-		h.logger.Error("failed to update avatar", zap.Error(err))
-		http.Error(w, "Failed to update avatar", http.StatusInternalServerError)
+		h.writeError(w, r, err, "failed to update avatar")
 		return
 	}
 
@@ -761,27 +1013,8 @@ func (h *Handlers) UpdateAvatar(w http.ResponseWriter, r *http.Request) {
 
 // UpdateProfile обновляет профиль пользователя
 func (h *Handlers) UpdateProfile(w http.ResponseWriter, r *http.Request) {
-	// Проверяем аутентификацию
-	_, claims, err := jwtauth.FromContext(r.Context())
-	if err != nil {
-		h.logger.Error("failed to get JWT claims", zap.Error(err))
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	userIDStr, ok := claims["user_id"].(string)
-	if !ok {
-		h.logger.Error("user_id not found in JWT claims")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		h.logger.Error("invalid user_id in JWT claims", zap.Error(err))
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
+	// Получаем пользователя из контекста (после аутентификации)
+	userID := r.Context().Value("user_id").(uuid.UUID)
 
 	// Парсим JSON запрос
 	var request struct {
@@ -792,34 +1025,21 @@ func (h *Handlers) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		h.logger.Error("failed to decode request", zap.Error(err))
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, errors.ErrInvalidBody)
 		return
 	}
 
-	// Получаем текущего пользователя
-	user, err := h.userRepo.GetByID(r.Context(), userID)
+	user, err := h.userService.UpdateProfile(r.Context(), userID, request.FirstName, request.LastName, request.Username)
 	if err != nil {
-		h.logger.Error("failed to get user", zap.Error(err))
-		http.Error(w, "User not found", http.StatusNotFound)
-		return
-	}
-
-	// Обновляем поля
-	if request.FirstName != "" {
-		user.FirstName = request.FirstName
-	}
-	if request.LastName != "" {
-		user.LastName = request.LastName
-	}
-	if request.Username != "" {
-		user.Username = request.Username
-	}
-
-	// Сохраняем изменения
-	err = h.userRepo.Update(r.Context(), user)
-	if err != nil {
-		h.logger.Error("failed to update profile", zap.Error(err))
-		http.Error(w, "Failed to update profile", http.StatusInternalServerError)
+		if stderrors.Is(err, errors.ErrUsernameExists) {
+			writeFieldErrors(w, r, validation.Errors{{
+				Field:   "username",
+				Code:    validation.CodeTaken,
+				Message: "username is already taken",
+			}})
+			return
+		}
+		h.writeError(w, r, err, "failed to update profile")
 		return
 	}
 
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/services"
+
+	"go.uber.org/zap"
+)
+
+// SubscribeNewsletter подписывает email на дайджест и отправляет письмо подтверждения.
+// Ответ не зависит от того, был ли адрес подписан раньше.
+func (h *Handlers) SubscribeNewsletter(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+
+	if err := h.newsletterService.Subscribe(r.Context(), req.Email); err != nil {
+		h.writeError(w, r, err, "failed to subscribe to newsletter")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"status": "confirmation_sent"})
+}
+
+// ConfirmNewsletter по ссылке из письма (GET) показывает страницу подтверждения,
+// а подписку подтверждает отправка формы с нее (POST) с возвратом на сайт
+func (h *Handlers) ConfirmNewsletter(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		h.renderNewsletterPage(w, r, services.NewsletterActionConfirm)
+		return
+	}
+
+	status := "confirmed"
+	if err := h.newsletterService.Confirm(r.Context(), r.FormValue("token")); err != nil {
+		if !stderrors.Is(err, errors.ErrSubscriptionNotFound) {
+			h.logger.Error("failed to confirm newsletter subscription", zap.Error(err))
+		}
+		status = "invalid"
+	}
+
+	http.Redirect(w, r, h.newsletterService.SiteURL()+"/?newsletter="+status, http.StatusSeeOther)
+}
+
+// UnsubscribeNewsletter по ссылке из письма (GET) показывает страницу отписки.
+// POST отписывает: форма со страницы возвращает на сайт, а one-click отписка
+// почтовых клиентов (RFC 8058, тело List-Unsubscribe=One-Click) получает JSON.
+func (h *Handlers) UnsubscribeNewsletter(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		h.renderNewsletterPage(w, r, services.NewsletterActionUnsubscribe)
+		return
+	}
+
+	err := h.newsletterService.Unsubscribe(r.Context(), r.FormValue("token"))
+	if err != nil && !stderrors.Is(err, errors.ErrSubscriptionNotFound) {
+		h.writeError(w, r, err, "failed to unsubscribe from newsletter")
+		return
+	}
+
+	if r.PostFormValue("List-Unsubscribe") == "One-Click" {
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"status": "unsubscribed"})
+		return
+	}
+
+	status := "unsubscribed"
+	if err != nil {
+		status = "invalid"
+	}
+	http.Redirect(w, r, h.newsletterService.SiteURL()+"/?newsletter="+status, http.StatusSeeOther)
+}
+
+// renderNewsletterPage отдает HTML-страницу подтверждения действия по ссылке из письма
+func (h *Handlers) renderNewsletterPage(w http.ResponseWriter, r *http.Request, action string) {
+	var page bytes.Buffer
+	if err := h.newsletterService.RenderPage(&page, action, r.URL.Query().Get("token")); err != nil {
+		h.writeError(w, r, err, "failed to render newsletter page")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Write(page.Bytes())
+}
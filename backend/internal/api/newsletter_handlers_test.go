@@ -0,0 +1,412 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	netmail "net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"startup-scout/internal/services"
+	"startup-scout/pkg/mail"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memoryNewsletter хранит подписки в памяти с той же семантикой, что и SQL-реализация
+type memoryNewsletter struct {
+	mu            sync.Mutex
+	subscriptions map[string]*entities.NewsletterSubscription
+	deliveries    map[string]map[uuid.UUID]bool
+}
+
+func newMemoryNewsletter() *memoryNewsletter {
+	return &memoryNewsletter{
+		subscriptions: make(map[string]*entities.NewsletterSubscription),
+		deliveries:    make(map[string]map[uuid.UUID]bool),
+	}
+}
+
+func (m *memoryNewsletter) Upsert(ctx context.Context, subscription *entities.NewsletterSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.subscriptions[subscription.Email]; ok {
+		existing.Status = subscription.Status
+		existing.ConfirmToken = subscription.ConfirmToken
+		existing.UnsubscribedAt = nil
+		existing.UpdatedAt = subscription.UpdatedAt
+		subscription.ID = existing.ID
+		subscription.UnsubscribeToken = existing.UnsubscribeToken
+		return nil
+	}
+
+	subscription.ID = uuid.New()
+	copied := *subscription
+	m.subscriptions[subscription.Email] = &copied
+	return nil
+}
+
+func (m *memoryNewsletter) find(match func(*entities.NewsletterSubscription) bool) (*entities.NewsletterSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, subscription := range m.subscriptions {
+		if match(subscription) {
+			copied := *subscription
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memoryNewsletter) GetByEmail(ctx context.Context, email string) (*entities.NewsletterSubscription, error) {
+	return m.find(func(s *entities.NewsletterSubscription) bool { return s.Email == email })
+}
+
+func (m *memoryNewsletter) GetByConfirmToken(ctx context.Context, token string) (*entities.NewsletterSubscription, error) {
+	return m.find(func(s *entities.NewsletterSubscription) bool {
+		return s.ConfirmToken.Valid && s.ConfirmToken.String == token
+	})
+}
+
+func (m *memoryNewsletter) GetByUnsubscribeToken(ctx context.Context, token string) (*entities.NewsletterSubscription, error) {
+	return m.find(func(s *entities.NewsletterSubscription) bool { return s.UnsubscribeToken == token })
+}
+
+func (m *memoryNewsletter) update(id uuid.UUID, change func(*entities.NewsletterSubscription)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, subscription := range m.subscriptions {
+		if subscription.ID == id {
+			change(subscription)
+		}
+	}
+}
+
+func (m *memoryNewsletter) Confirm(ctx context.Context, id uuid.UUID, at time.Time) error {
+	m.update(id, func(s *entities.NewsletterSubscription) {
+		s.Status = entities.SubscriptionConfirmed
+		s.ConfirmToken = sql.NullString{}
+		s.ConfirmedAt = &at
+	})
+	return nil
+}
+
+func (m *memoryNewsletter) Unsubscribe(ctx context.Context, id uuid.UUID, at time.Time) error {
+	m.update(id, func(s *entities.NewsletterSubscription) {
+		s.Status = entities.SubscriptionUnsubscribed
+		s.ConfirmToken = sql.NullString{}
+		s.UnsubscribedAt = &at
+	})
+	return nil
+}
+
+func (m *memoryNewsletter) GetUndelivered(ctx context.Context, digestKey string, afterID uuid.UUID, limit int) ([]*entities.NewsletterSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []*entities.NewsletterSubscription
+	for _, subscription := range m.subscriptions {
+		if subscription.Status == entities.SubscriptionConfirmed &&
+			bytes.Compare(subscription.ID[:], afterID[:]) > 0 &&
+			!m.deliveries[digestKey][subscription.ID] {
+			copied := *subscription
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return bytes.Compare(result[i].ID[:], result[j].ID[:]) < 0 })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *memoryNewsletter) RecordDelivery(ctx context.Context, digestKey string, subscriptionID uuid.UUID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.deliveries[digestKey] == nil {
+		m.deliveries[digestKey] = make(map[uuid.UUID]bool)
+	}
+	m.deliveries[digestKey][subscriptionID] = true
+	return nil
+}
+
+func (m *memoryNewsletter) DeleteByEmail(ctx context.Context, email string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.subscriptions, email)
+	return nil
+}
+
+func (m *memoryNewsletter) status(t *testing.T, email string) entities.SubscriptionStatus {
+	t.Helper()
+
+	subscription, err := m.GetByEmail(context.Background(), email)
+	if err != nil {
+		t.Fatalf("subscription %s not found", email)
+	}
+	return subscription.Status
+}
+
+// staticLaunches отдает заданный список запусков; остальные методы не используются
+type staticLaunches struct {
+	repository.LaunchRepository
+	launches []*entities.Launch
+}
+
+func (l *staticLaunches) GetAll(ctx context.Context) ([]*entities.Launch, error) {
+	return l.launches, nil
+}
+
+// staticProjects отдает заданные проекты запусков; остальные методы не используются
+type staticProjects struct {
+	repository.ProjectRepository
+	byLaunch map[uuid.UUID][]*entities.Project
+}
+
+func (p *staticProjects) GetByLaunchIDOrderedByRating(ctx context.Context, launchID uuid.UUID) ([]*entities.Project, error) {
+	return p.byLaunch[launchID], nil
+}
+
+// sentMail письмо, сохраненное FileSender
+type sentMail struct {
+	header netmail.Header
+	text   string
+}
+
+// readMails разбирает .eml файлы каталога в порядке отправки
+func readMails(t *testing.T, dir string) []sentMail {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read mail dir: %v", err)
+	}
+
+	var mails []sentMail
+	for _, entry := range entries {
+		file, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("open mail: %v", err)
+		}
+		message, err := netmail.ReadMessage(file)
+		if err != nil {
+			t.Fatalf("parse mail %s: %v", entry.Name(), err)
+		}
+
+		_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+		if err != nil {
+			t.Fatalf("parse content type: %v", err)
+		}
+
+		sent := sentMail{header: message.Header}
+		parts := multipart.NewReader(message.Body, params["boundary"])
+		for {
+			part, err := parts.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("read mail part: %v", err)
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain") {
+				text, _ := io.ReadAll(part)
+				sent.text = string(text)
+			}
+		}
+		file.Close()
+
+		mails = append(mails, sent)
+	}
+	return mails
+}
+
+var confirmURLPattern = regexp.MustCompile(`https://api\.test/newsletter/confirm\?token=\S+`)
+
+func TestNewsletterSubscriptionFlow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	previous := &entities.Launch{ID: uuid.New(), Name: "Weekly Launch #9", StartDate: now.Add(-8 * 24 * time.Hour), EndDate: now.Add(-24 * time.Hour)}
+	current := &entities.Launch{ID: uuid.New(), Name: "Weekly Launch #10", StartDate: now.Add(-12 * time.Hour), EndDate: now.Add(6 * 24 * time.Hour), IsActive: true}
+	projects := &staticProjects{byLaunch: map[uuid.UUID][]*entities.Project{
+		previous.ID: {
+			{ID: uuid.New(), Name: "Rocket", Description: "Fast deploys", Rating: 10, Upvotes: 10},
+			{ID: uuid.New(), Name: "Compass", Description: "Team maps", Rating: 4, Upvotes: 4},
+		},
+	}}
+
+	mailDir := t.TempDir()
+	sender, err := mail.NewFileSender(mailDir)
+	if err != nil {
+		t.Fatalf("NewFileSender: %v", err)
+	}
+
+	repo := newMemoryNewsletter()
+	service := services.NewNewsletterService(
+		repo,
+		&staticLaunches{launches: []*entities.Launch{previous, current}},
+		projects,
+		sender,
+		services.NewsletterConfig{
+			From:    "Startup Scout <noreply@startup-scout.test>",
+			SiteURL: "https://site.test",
+			APIURL:  "https://api.test",
+		},
+		zap.NewNop(),
+	)
+	h := &Handlers{newsletterService: service, logger: zap.NewNop()}
+
+	// Подписка отправляет письмо со ссылкой подтверждения
+	recorder := httptest.NewRecorder()
+	h.SubscribeNewsletter(recorder, httptest.NewRequest(http.MethodPost, "/newsletter/subscribe",
+		strings.NewReader(`{"email":" Reader@Example.com "}`)))
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("subscribe: status %d, body %s", recorder.Code, recorder.Body)
+	}
+
+	mails := readMails(t, mailDir)
+	if len(mails) != 1 || mails[0].header.Get("To") != "reader@example.com" {
+		t.Fatalf("confirmation mails = %+v", mails)
+	}
+	confirmURL := confirmURLPattern.FindString(mails[0].text)
+	if confirmURL == "" {
+		t.Fatalf("confirmation link not found in:\n%s", mails[0].text)
+	}
+	confirm, _ := url.Parse(confirmURL)
+
+	// Переход по ссылке только показывает страницу с формой
+	recorder = httptest.NewRecorder()
+	h.ConfirmNewsletter(recorder, httptest.NewRequest(http.MethodGet, confirm.RequestURI(), nil))
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("confirm page: status %d, content type %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(recorder.Body.String(), `method="post"`) ||
+		!strings.Contains(recorder.Body.String(), confirm.Query().Get("token")) {
+		t.Errorf("confirm page has no form with the token:\n%s", recorder.Body)
+	}
+	if status := repo.status(t, "reader@example.com"); status != entities.SubscriptionPending {
+		t.Fatalf("GET changed the subscription: status %s", status)
+	}
+
+	// Отправка формы подтверждает подписку и возвращает на сайт
+	recorder = httptest.NewRecorder()
+	h.ConfirmNewsletter(recorder, formRequest("/newsletter/confirm", url.Values{"token": {confirm.Query().Get("token")}}))
+	if recorder.Code != http.StatusSeeOther || recorder.Header().Get("Location") != "https://site.test/?newsletter=confirmed" {
+		t.Fatalf("confirm: status %d, location %q", recorder.Code, recorder.Header().Get("Location"))
+	}
+	if status := repo.status(t, "reader@example.com"); status != entities.SubscriptionConfirmed {
+		t.Fatalf("status after confirm = %s", status)
+	}
+
+	// Дайджесты о старте текущего запуска и итогах прошлого уходят один раз
+	sent, err := service.SendLaunchDigests(ctx, now)
+	if err != nil || sent != 2 {
+		t.Fatalf("SendLaunchDigests = %d, %v; want 2 digests", sent, err)
+	}
+	if sent, err := service.SendLaunchDigests(ctx, now); err != nil || sent != 0 {
+		t.Fatalf("repeated SendLaunchDigests = %d, %v; want 0", sent, err)
+	}
+
+	digests := readMails(t, mailDir)[1:]
+	if len(digests) != 2 {
+		t.Fatalf("digest mails = %d, want 2", len(digests))
+	}
+	for _, digest := range digests {
+		if !strings.Contains(digest.text, "Rocket") || !strings.Contains(digest.text, "https://site.test/project/") {
+			t.Errorf("digest does not list the top projects:\n%s", digest.text)
+		}
+		if digest.header.Get("List-Unsubscribe-Post") != "List-Unsubscribe=One-Click" {
+			t.Errorf("digest has no one-click unsubscribe header")
+		}
+	}
+
+	unsubscribeURL := strings.Trim(digests[0].header.Get("List-Unsubscribe"), "<>")
+	unsubscribe, err := url.Parse(unsubscribeURL)
+	if err != nil || unsubscribe.Query().Get("token") == "" {
+		t.Fatalf("List-Unsubscribe = %q", unsubscribeURL)
+	}
+
+	// Ссылка отписки из письма тоже только показывает страницу
+	recorder = httptest.NewRecorder()
+	h.UnsubscribeNewsletter(recorder, httptest.NewRequest(http.MethodGet, unsubscribe.RequestURI(), nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unsubscribe page: status %d", recorder.Code)
+	}
+	if status := repo.status(t, "reader@example.com"); status != entities.SubscriptionConfirmed {
+		t.Fatalf("GET unsubscribed the address: status %s", status)
+	}
+
+	// One-click отписка почтового клиента (RFC 8058)
+	recorder = httptest.NewRecorder()
+	h.UnsubscribeNewsletter(recorder, formRequest(unsubscribe.RequestURI(), url.Values{"List-Unsubscribe": {"One-Click"}}))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("one-click unsubscribe: status %d, body %s", recorder.Code, recorder.Body)
+	}
+	var response map[string]string
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if response["status"] != "unsubscribed" {
+		t.Errorf("one-click response = %v", response)
+	}
+	if status := repo.status(t, "reader@example.com"); status != entities.SubscriptionUnsubscribed {
+		t.Fatalf("status after unsubscribe = %s", status)
+	}
+
+	// Отписанный адрес не получает следующий дайджест
+	later := now.Add(24 * time.Hour)
+	next := &entities.Launch{ID: uuid.New(), Name: "Weekly Launch #11", StartDate: later.Add(-time.Hour), EndDate: later.Add(6 * 24 * time.Hour)}
+	service = services.NewNewsletterService(repo, &staticLaunches{launches: []*entities.Launch{next}}, projects, sender,
+		services.NewsletterConfig{From: "Startup Scout <noreply@startup-scout.test>", APIURL: "https://api.test"}, zap.NewNop())
+	if sent, err := service.SendLaunchDigests(ctx, later); err != nil || sent != 0 {
+		t.Errorf("digest after unsubscribe = %d, %v; want 0", sent, err)
+	}
+}
+
+func TestNewsletterConfirmWithInvalidToken(t *testing.T) {
+	sender, err := mail.NewFileSender(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSender: %v", err)
+	}
+	service := services.NewNewsletterService(newMemoryNewsletter(), &staticLaunches{}, &staticProjects{}, sender,
+		services.NewsletterConfig{SiteURL: "https://site.test", APIURL: "https://api.test"}, zap.NewNop())
+	h := &Handlers{newsletterService: service, logger: zap.NewNop()}
+
+	recorder := httptest.NewRecorder()
+	h.ConfirmNewsletter(recorder, formRequest("/newsletter/confirm", url.Values{"token": {"unknown"}}))
+	if recorder.Header().Get("Location") != "https://site.test/?newsletter=invalid" {
+		t.Errorf("location = %q", recorder.Header().Get("Location"))
+	}
+
+	recorder = httptest.NewRecorder()
+	h.UnsubscribeNewsletter(recorder, formRequest("/newsletter/unsubscribe?token=unknown", url.Values{"List-Unsubscribe": {"One-Click"}}))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("one-click with unknown token: status %d", recorder.Code)
+	}
+}
+
+func formRequest(target string, values url.Values) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, target, strings.NewReader(values.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return request
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GetNotifications возвращает уведомления пользователя: ?cursor=...&limit=...&unread=true
+func (h *Handlers) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+	query := r.URL.Query()
+
+	params, ok := parsePageParams(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.notifications.List(r.Context(), userID, query.Get("unread") == "true", params)
+	if err != nil {
+		h.writeError(w, r, err, "failed to get notifications", zap.String("user_id", userID.String()))
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetUnreadNotificationsCount возвращает число непрочитанных уведомлений
+func (h *Handlers) GetUnreadNotificationsCount(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	count, err := h.notifications.UnreadCount(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to count unread notifications", zap.String("user_id", userID.String()))
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]int{"unread_count": count})
+}
+
+// MarkNotificationRead помечает уведомление прочитанным
+func (h *Handlers) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	notificationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid notification ID"))
+		return
+	}
+
+	found, err := h.notifications.MarkRead(r.Context(), userID, notificationID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to mark notification as read", zap.String("notification_id", notificationID.String()))
+		return
+	}
+	if !found {
+		writeError(w, r, errors.ErrNotificationNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// MarkAllNotificationsRead помечает все уведомления пользователя прочитанными
+func (h *Handlers) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	if err := h.notifications.MarkAllRead(r.Context(), userID); err != nil {
+		h.writeError(w, r, err, "failed to mark notifications as read", zap.String("user_id", userID.String()))
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// GetNotificationPreferences возвращает включенные типы уведомлений
+func (h *Handlers) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value("user").(*entities.User)
+
+	json.NewEncoder(w).Encode(user.NotificationPreferences)
+}
+
+// UpdateNotificationPreferences включает и отключает типы уведомлений
+func (h *Handlers) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value("user").(*entities.User)
+
+	// Незаданные в запросе поля сохраняют текущие значения
+	preferences := user.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&preferences); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+
+	if err := h.notifications.UpdatePreferences(r.Context(), user.ID, preferences); err != nil {
+		h.writeError(w, r, err, "failed to update notification preferences", zap.String("user_id", user.ID.String()))
+		return
+	}
+
+	json.NewEncoder(w).Encode(preferences)
+}
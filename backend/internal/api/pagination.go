@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/pagination"
+)
+
+// parsePageParams разбирает ?cursor=...&limit=...; при поврежденном курсоре отвечает 400
+func parsePageParams(w http.ResponseWriter, r *http.Request) (pagination.Params, bool) {
+	query := r.URL.Query()
+
+	cursor, err := pagination.Decode(query.Get("cursor"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidRequest.WithMessage("invalid cursor"))
+		return pagination.Params{}, false
+	}
+
+	return pagination.Params{
+		Cursor: cursor,
+		Limit:  pagination.ParseLimit(query.Get("limit")),
+	}, true
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"startup-scout/config"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateLimitKeyFunc возвращает ключ, по которому считаются запросы.
+// Пустой ключ означает, что запрос не ограничивается этим правилом.
+type rateLimitKeyFunc func(r *http.Request) string
+
+// rateLimitMiddleware ограничивает количество запросов по ключу в заданном окне
+func rateLimitMiddleware(store repository.RateLimitStore, name string, limit config.RateLimit, keyFunc rateLimitKeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit.Requests <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := keyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			counter, err := store.Increment(r.Context(), "rate:"+name+":"+key, limit.Window)
+			if err != nil {
+				// При недоступности хранилища не блокируем пользователей
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			remaining := limit.Requests - counter.Count
+			if remaining < 0 {
+				remaining = 0
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(counter.ResetAt.Unix(), 10))
+
+			if counter.Count > limit.Requests {
+				writeRetryAfter(w, time.Until(counter.ResetAt))
+				writeError(w, r, errors.ErrRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// realIP подставляет в RemoteAddr адрес из X-Real-IP, но только для запросов от
+// доверенных прокси. nginx перезаписывает этот заголовок, а прочие (True-Client-IP,
+// X-Forwarded-For) задает клиент, поэтому им не доверяем: иначе клиент получал бы
+// новый счетчик ограничений на каждый запрос.
+func realIP(trustedProxies []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := net.ParseIP(r.Header.Get("X-Real-IP")); ip != nil && isTrustedProxy(clientIPKey(r), trustedProxies) {
+				r.RemoteAddr = ip.String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isTrustedProxy(addr string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIPKey использует IP клиента (после realIP)
+func clientIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// jsonFieldKey использует значение поля из JSON тела запроса, например логин.
+// Тело запроса восстанавливается для следующего обработчика.
+func jsonFieldKey(field string) rateLimitKeyFunc {
+	return func(r *http.Request) string {
+		if r.Body == nil {
+			return ""
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return ""
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return ""
+		}
+
+		value, _ := payload[field].(string)
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+}
+
+// writeRetryAfter устанавливает заголовок Retry-After в секундах
+func writeRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(retryAfter.Seconds())
+	if retryAfter > time.Duration(seconds)*time.Second {
+		seconds++
+	}
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
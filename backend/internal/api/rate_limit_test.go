@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIPTrustsOnlyConfiguredProxies(t *testing.T) {
+	_, dockerNetwork, _ := net.ParseCIDR("172.16.0.0/12")
+	trusted := []*net.IPNet{dockerNetwork}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "nginx passes the client address",
+			remoteAddr: "172.18.0.5:41000",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.7"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "direct client cannot set X-Real-IP",
+			remoteAddr: "198.51.100.20:52000",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.7"},
+			want:       "198.51.100.20",
+		},
+		{
+			name:       "client headers behind nginx are ignored",
+			remoteAddr: "172.18.0.5:41000",
+			headers: map[string]string{
+				"X-Real-IP":       "203.0.113.7",
+				"True-Client-IP":  "192.0.2.1",
+				"X-Forwarded-For": "192.0.2.2",
+			},
+			want: "203.0.113.7",
+		},
+		{
+			name:       "True-Client-IP alone is ignored",
+			remoteAddr: "172.18.0.5:41000",
+			headers:    map[string]string{"True-Client-IP": "192.0.2.1"},
+			want:       "172.18.0.5",
+		},
+		{
+			name:       "invalid X-Real-IP is ignored",
+			remoteAddr: "172.18.0.5:41000",
+			headers:    map[string]string{"X-Real-IP": "not-an-ip"},
+			want:       "172.18.0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := realIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = clientIPKey(r)
+			}))
+
+			request := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+			request.RemoteAddr = tt.remoteAddr
+			for name, value := range tt.headers {
+				request.Header.Set(name, value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			if got != tt.want {
+				t.Errorf("client IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
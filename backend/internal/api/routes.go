@@ -2,9 +2,14 @@ package api
 
 import (
 	"context"
+	stderrors "errors"
+	"net"
 	"net/http"
+	"startup-scout/config"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
 	"startup-scout/internal/repository"
+	"startup-scout/internal/services"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -13,46 +18,88 @@ import (
 	"github.com/google/uuid"
 )
 
-func SetupRoutes(handlers *Handlers, jwtAuth *jwtauth.JWTAuth, userRepo repository.UserRepository) http.Handler {
+func SetupRoutes(
+	handlers *Handlers,
+	jwtAuth *jwtauth.JWTAuth,
+	userRepo repository.UserRepository,
+	rateLimitStore repository.RateLimitStore,
+	rateLimits config.RateLimitConfig,
+	trustedProxies []*net.IPNet,
+) http.Handler {
 	r := chi.NewRouter()
 
 	// Middleware
+	r.Use(middleware.RequestID)
+	r.Use(requestIDHeader)
+	r.Use(realIP(trustedProxies))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://startup-scout.ru", "https://www.startup-scout.ru", "http://localhost:3000"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", middleware.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
-	// Public routes
+	r.NotFound(notFoundHandler)
+	r.MethodNotAllowed(methodNotAllowedHandler)
+
+	// Public routes. Списки проверяются клиентами по ETag.
 	r.Group(func(r chi.Router) {
-		r.Get("/projects", handlers.GetProjects)
+		r.With(etagMiddleware).Get("/projects", handlers.GetProjects)
 		r.Get("/projects/{id}", handlers.GetProject)
 		r.Get("/projects/{id}/comments", handlers.GetProjectComments)
-		r.Get("/stats", handlers.GetStats)
+		r.Get("/projects/{id}/team", handlers.GetProjectTeam)
+		r.With(etagMiddleware).Get("/stats", handlers.GetStats)
+		r.Get("/search", handlers.Search)
+		r.With(etagMiddleware).Get("/categories", handlers.GetCategories)
+		r.With(etagMiddleware).Get("/categories/{slug}/projects", handlers.GetCategoryProjects)
+		r.Get("/users/{username}", handlers.GetPublicProfile)
 
 		// Image routes (public access to view images)
 		r.Get("/images/{filename}", handlers.GetImage)
 		r.Head("/images/{filename}", handlers.GetImage)
 
-		// Auth routes
+		r.Post("/auth/logout", handlers.Logout)
+
+		// Newsletter links from emails: GET renders a confirmation page, POST changes the subscription
+		r.Get("/newsletter/confirm", handlers.ConfirmNewsletter)
+		r.Post("/newsletter/confirm", handlers.ConfirmNewsletter)
+		r.Get("/newsletter/unsubscribe", handlers.UnsubscribeNewsletter)
+		r.Post("/newsletter/unsubscribe", handlers.UnsubscribeNewsletter)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(rateLimitMiddleware(rateLimitStore, "newsletter", rateLimits.Newsletter, clientIPKey))
+
+		r.Post("/newsletter/subscribe", handlers.SubscribeNewsletter)
+	})
+
+	// Auth routes (rate limited)
+	r.Group(func(r chi.Router) {
+		r.Use(rateLimitMiddleware(rateLimitStore, "register", rateLimits.Register, clientIPKey))
+
 		r.Post("/auth/email/register", handlers.RegisterEmail)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(rateLimitMiddleware(rateLimitStore, "login", rateLimits.Login, clientIPKey))
+		r.Use(rateLimitMiddleware(rateLimitStore, "login_account", rateLimits.LoginAccount, jsonFieldKey("email")))
+
 		r.Post("/auth/email/login", handlers.AuthEmail)
-		r.Post("/auth/logout", handlers.Logout)
+		r.Post("/auth/2fa/verify", handlers.VerifyTwoFactor)
 	})
 
 	// Protected routes
 	r.Group(func(r chi.Router) {
 		r.Use(cookieJWTVerifier(jwtAuth))
 		r.Use(jwtauth.Verifier(jwtAuth))
-		r.Use(jwtauth.Authenticator)
-		r.Use(userContextMiddleware(userRepo))
+		r.Use(userContextMiddleware(userRepo, handlers.authService))
 
 		r.Post("/projects", handlers.CreateProject)
+		r.Put("/projects/{id}", handlers.UpdateProject)
 		r.Get("/users/{id}/projects", handlers.GetUserProjects)
 		r.Post("/projects/{id}/vote", handlers.Vote)
 		r.Delete("/projects/{id}/vote", handlers.RemoveVote)
@@ -62,46 +109,127 @@ func SetupRoutes(handlers *Handlers, jwtAuth *jwtauth.JWTAuth, userRepo reposito
 		r.Get("/profile", handlers.GetProfile)
 		r.Put("/profile", handlers.UpdateProfile)
 		r.Put("/profile/avatar", handlers.UpdateAvatar)
+		r.Delete("/profile", handlers.DeleteProfile)
+		r.Post("/profile/restore", handlers.RestoreProfile)
+		r.Get("/profile/export", handlers.ExportProfile)
+		r.Put("/profile/privacy", handlers.UpdatePrivacy)
 		r.Get("/votes", handlers.GetUserVotes)
 		r.Post("/auth/telegram/link", handlers.LinkTelegram)
 
-		// Image upload (protected)
+		// Follows and personal feed
+		r.Post("/users/{username}/follow", handlers.FollowUser)
+		r.Delete("/users/{username}/follow", handlers.UnfollowUser)
+		r.Post("/projects/{id}/follow", handlers.FollowProject)
+		r.Delete("/projects/{id}/follow", handlers.UnfollowProject)
+		r.Get("/feed", handlers.GetFeed)
+
+		// Project teams and invitations
+		r.Post("/projects/{id}/invitations", handlers.InviteProjectMember)
+		r.Get("/projects/{id}/invitations", handlers.GetProjectInvitations)
+		r.Delete("/projects/{id}/invitations/{invitationId}", handlers.RevokeProjectInvitation)
+		r.Delete("/projects/{id}/members/{userId}", handlers.RemoveProjectMember)
+		r.Get("/invitations", handlers.GetInvitations)
+		r.Post("/invitations/accept", handlers.AcceptInvitationByToken)
+		r.Post("/invitations/{id}/accept", handlers.AcceptInvitation)
+		r.Post("/invitations/{id}/decline", handlers.DeclineInvitation)
+
+		// Notifications
+		r.Get("/notifications", handlers.GetNotifications)
+		r.Get("/notifications/unread-count", handlers.GetUnreadNotificationsCount)
+		r.Post("/notifications/read-all", handlers.MarkAllNotificationsRead)
+		r.Post("/notifications/{id}/read", handlers.MarkNotificationRead)
+		r.Get("/profile/notifications", handlers.GetNotificationPreferences)
+		r.Put("/profile/notifications", handlers.UpdateNotificationPreferences)
+
+		// Two-factor authentication
+		r.Get("/profile/2fa", handlers.GetTwoFactorStatus)
+		r.Post("/profile/2fa/setup", handlers.SetupTwoFactor)
+		r.Post("/profile/2fa/enable", handlers.EnableTwoFactor)
+		r.Post("/profile/2fa/disable", handlers.DisableTwoFactor)
+		r.Post("/profile/2fa/recovery-codes", handlers.RegenerateRecoveryCodes)
+
+		// Image and video upload (protected)
 		r.Post("/images/upload", handlers.UploadImage)
+		r.Post("/videos/upload", handlers.UploadVideo)
+		r.Get("/images/quota", handlers.GetUploadQuota)
+
+		// Administration
+		r.Group(func(r chi.Router) {
+			r.Use(adminOnlyMiddleware)
+
+			r.Post("/admin/categories", handlers.CreateCategory)
+			r.Put("/admin/categories/{id}", handlers.UpdateCategory)
+			r.Delete("/admin/categories/{id}", handlers.DeleteCategory)
+
+			r.Get("/admin/webhooks", handlers.GetWebhooks)
+			r.Post("/admin/webhooks", handlers.CreateWebhook)
+			r.Put("/admin/webhooks/{id}", handlers.UpdateWebhook)
+			r.Delete("/admin/webhooks/{id}", handlers.DeleteWebhook)
+			r.Post("/admin/webhooks/{id}/rotate-secret", handlers.RotateWebhookSecret)
+			r.Get("/admin/webhooks/{id}/deliveries", handlers.GetWebhookDeliveries)
+			r.Post("/admin/webhooks/{id}/deliveries/{deliveryId}/retry", handlers.RetryWebhookDelivery)
+		})
 	})
 
 	return r
 }
 
-func userContextMiddleware(userRepo repository.UserRepository) func(http.Handler) http.Handler {
+// userContextMiddleware проверяет JWT и сохраненную сессию и загружает пользователя
+// в контекст; используется после jwtauth.Verifier
+func userContextMiddleware(userRepo repository.UserRepository, authService *services.AuthService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			_, claims, err := jwtauth.FromContext(r.Context())
-			if err != nil {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			token, claims, err := jwtauth.FromContext(r.Context())
+			if stderrors.Is(err, jwtauth.ErrNoTokenFound) {
+				writeError(w, r, errors.ErrUnauthorized)
+				return
+			}
+			if err != nil || token == nil {
+				writeError(w, r, errors.ErrInvalidToken)
+				return
+			}
+
+			// Промежуточные токены (например, ожидающие 2FA) не дают доступа к API
+			if tokenType, ok := claims["typ"].(string); ok && tokenType != tokenTypeSession {
+				writeError(w, r, errors.ErrInvalidToken)
 				return
 			}
 
 			// Извлекаем данные пользователя из JWT claims
 			userIDString, ok := claims["user_id"].(string)
 			if !ok {
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				writeError(w, r, errors.ErrInvalidToken)
 				return
 			}
 
 			userID, err := uuid.Parse(userIDString)
 			if err != nil {
-				http.Error(w, "Invalid user ID format", http.StatusUnauthorized)
+				writeError(w, r, errors.ErrInvalidToken)
 				return
 			}
 
-			// Создаем объект пользователя из claims (кеш в JWT)
-			user := &entities.User{
-				ID:        userID,
-				Email:     getStringFromClaims(claims, "email"),
-				Username:  getStringFromClaims(claims, "username"),
-				Avatar:    getStringFromClaims(claims, "avatar"),
-				FirstName: getStringFromClaims(claims, "first_name"),
-				LastName:  getStringFromClaims(claims, "last_name"),
+			// Сессия могла быть отозвана выходом или удалением аккаунта
+			sid, _ := claims["sid"].(string)
+			sessionID, err := uuid.Parse(sid)
+			if err != nil {
+				writeError(w, r, errors.ErrInvalidToken)
+				return
+			}
+			if err := authService.CheckSession(r.Context(), sessionID, userID); err != nil {
+				writeError(w, r, err)
+				return
+			}
+
+			// Загружаем актуальный профиль (через кеш репозитория)
+			user, err := userRepo.GetByID(r.Context(), userID)
+			if err != nil {
+				writeError(w, r, errors.ErrUnauthorized)
+				return
+			}
+
+			if !user.IsActive {
+				writeError(w, r, errors.ErrAccountInactive)
+				return
 			}
 
 			// Добавляем пользователя в контекст
@@ -112,6 +240,19 @@ func userContextMiddleware(userRepo repository.UserRepository) func(http.Handler
 	}
 }
 
+// adminOnlyMiddleware пропускает только администраторов; используется после userContextMiddleware
+func adminOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := r.Context().Value("user").(*entities.User)
+		if !ok || !user.IsAdmin {
+			writeError(w, r, errors.ErrForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // cookieJWTVerifier извлекает JWT токен из cookies и добавляет в контекст
 func cookieJWTVerifier(jwtAuth *jwtauth.JWTAuth) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -125,17 +266,8 @@ func cookieJWTVerifier(jwtAuth *jwtauth.JWTAuth) func(http.Handler) http.Handler
 
 			// Добавляем токен в заголовок Authorization для jwtauth
 			r.Header.Set("Authorization", "Bearer "+cookie.Value)
-			
+
 			next.ServeHTTP(w, r)
 		})
 	}
 }
-
-// getStringFromClaims безопасно извлекает строку из claims
-func getStringFromClaims(claims map[string]interface{}, key string) string {
-	if value, ok := claims[key].(string); ok {
-		return value
-	}
-	return ""
-}
-
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/pagination"
+	"startup-scout/internal/validation"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Search ищет по проектам, мейкерам и комментариям:
+// ?q=...&type=projects,makers,comments&launch_id=...&from=...&to=...&limit=...
+// Даты принимаются в RFC3339 или YYYY-MM-DD; дата без времени в to включается целиком.
+func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	v := validation.New()
+
+	filter := entities.SearchFilter{
+		Query: query.Get("q"),
+		Limit: pagination.ParseLimit(query.Get("limit")),
+	}
+
+	var scopes []entities.SearchScope
+	if raw := query.Get("type"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			scope := entities.SearchScope(strings.TrimSpace(part))
+			switch scope {
+			case entities.SearchProjects, entities.SearchMakers, entities.SearchComments:
+				scopes = append(scopes, scope)
+			default:
+				v.Add("type", validation.CodeInvalidFormat, "unknown search section: "+part)
+			}
+		}
+	}
+
+	if raw := query.Get("launch_id"); raw != "" {
+		launchID, err := uuid.Parse(raw)
+		if err != nil {
+			v.Add("launch_id", validation.CodeInvalidFormat, "invalid launch id")
+		} else {
+			filter.LaunchID = &launchID
+		}
+	}
+
+	filter.From = parseSearchDate(v, "from", query.Get("from"), false)
+	filter.To = parseSearchDate(v, "to", query.Get("to"), true)
+
+	if err := v.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	results, err := h.searchService.Search(r.Context(), filter, scopes)
+	if err != nil {
+		h.writeError(w, r, err, "failed to search")
+		return
+	}
+
+	json.NewEncoder(w).Encode(results)
+}
+
+// parseSearchDate разбирает границу периода. Для конца периода дата без времени
+// сдвигается на сутки, чтобы день включался целиком.
+func parseSearchDate(v *validation.Validator, field, value string, end bool) *time.Time {
+	if value == "" {
+		return nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		v.Add(field, validation.CodeInvalidFormat, "expected a date in RFC3339 or YYYY-MM-DD format")
+		return nil
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t
+}
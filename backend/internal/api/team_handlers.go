@@ -0,0 +1,208 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"startup-scout/internal/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// GetProjectTeam возвращает команду проекта: владельца и соавторов с профилями
+func (h *Handlers) GetProjectTeam(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.teamService.GetMembers(r.Context(), projectID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to get project team")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"makers": members,
+	})
+}
+
+// InviteProjectMember приглашает соавтора по username или email (только владелец)
+func (h *Handlers) InviteProjectMember(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+
+	var requestData struct {
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	invitation, err := h.teamService.Invite(r.Context(), userID, projectID, requestData.Username, requestData.Email)
+	if err != nil {
+		h.writeError(w, r, err, "failed to invite project member")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// GetProjectInvitations возвращает ожидающие ответа приглашения проекта (только владелец)
+func (h *Handlers) GetProjectInvitations(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	invitations, err := h.teamService.ProjectInvitations(r.Context(), userID, projectID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to get project invitations")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"invitations": invitations,
+	})
+}
+
+// RevokeProjectInvitation отзывает приглашение (только владелец)
+func (h *Handlers) RevokeProjectInvitation(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+
+	invitationID, err := uuid.Parse(chi.URLParam(r, "invitationId"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid invitation ID"))
+		return
+	}
+
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	if err := h.teamService.RevokeInvitation(r.Context(), userID, projectID, invitationID); err != nil {
+		h.writeError(w, r, err, "failed to revoke invitation")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// RemoveProjectMember исключает соавтора (владелец) или выводит пользователя из команды (сам соавтор)
+func (h *Handlers) RemoveProjectMember(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseProjectID(w, r)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid user ID"))
+		return
+	}
+
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	if err := h.teamService.RemoveMember(r.Context(), userID, projectID, memberID); err != nil {
+		h.writeError(w, r, err, "failed to remove project member")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// GetInvitations возвращает действующие приглашения текущего пользователя
+func (h *Handlers) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	invitations, err := h.teamService.UserInvitations(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to get invitations")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"invitations": invitations,
+	})
+}
+
+func (h *Handlers) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	invitationID, ok := parseInvitationID(w, r)
+	if !ok {
+		return
+	}
+
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	if err := h.teamService.Accept(r.Context(), userID, invitationID); err != nil {
+		h.writeError(w, r, err, "failed to accept invitation")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+func (h *Handlers) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	invitationID, ok := parseInvitationID(w, r)
+	if !ok {
+		return
+	}
+
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	if err := h.teamService.Decline(r.Context(), userID, invitationID); err != nil {
+		h.writeError(w, r, err, "failed to decline invitation")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// AcceptInvitationByToken принимает приглашение по токену из ссылки в письме
+func (h *Handlers) AcceptInvitationByToken(w http.ResponseWriter, r *http.Request) {
+	var requestData struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	invitation, err := h.teamService.AcceptByToken(r.Context(), userID, requestData.Token)
+	if err != nil {
+		h.writeError(w, r, err, "failed to accept invitation")
+		return
+	}
+
+	json.NewEncoder(w).Encode(invitation)
+}
+
+func parseProjectID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid project ID"))
+		return uuid.Nil, false
+	}
+	return projectID, true
+}
+
+func parseInvitationID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	invitationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid invitation ID"))
+		return uuid.Nil, false
+	}
+	return invitationID, true
+}
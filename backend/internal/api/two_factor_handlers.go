@@ -0,0 +1,200 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/validation"
+	"time"
+
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	tokenTypeSession          = "session"
+	tokenTypePendingTwoFactor = "2fa_pending"
+)
+
+// issuePendingTwoFactorToken выпускает короткоживущий одноразовый токен, который
+// можно обменять на сессию только через /auth/2fa/verify. jti токена сохраняется
+// и расходуется при успешной проверке кода.
+func (h *Handlers) issuePendingTwoFactorToken(r *http.Request, user *entities.User) (string, error) {
+	challengeID, expiresAt, err := h.twoFactor.StartChallenge(r.Context(), user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	claims := map[string]interface{}{
+		"user_id": user.ID.String(),
+		"typ":     tokenTypePendingTwoFactor,
+		"jti":     challengeID.String(),
+		"iat":     time.Now().Unix(),
+		"exp":     expiresAt.Unix(),
+	}
+
+	_, tokenString, err := h.jwtAuth.Encode(claims)
+	return tokenString, err
+}
+
+// VerifyTwoFactor завершает вход: проверяет код и устанавливает cookie сессии
+func (h *Handlers) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		PendingToken string `json:"pending_token"`
+		Code         string `json:"code"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+
+	token, err := jwtauth.VerifyToken(h.jwtAuth, request.PendingToken)
+	if err != nil {
+		writeError(w, r, errors.ErrSessionExpired)
+		return
+	}
+
+	tokenType, _ := token.PrivateClaims()["typ"].(string)
+	userIDString, _ := token.PrivateClaims()["user_id"].(string)
+	userID, userErr := uuid.Parse(userIDString)
+	challengeID, challengeErr := uuid.Parse(token.JwtID())
+	if tokenType != tokenTypePendingTwoFactor || userErr != nil || challengeErr != nil {
+		writeError(w, r, errors.ErrInvalidToken)
+		return
+	}
+
+	if err := h.twoFactor.VerifyChallenge(r.Context(), challengeID, userID, request.Code); err != nil {
+		h.writeError(w, r, err, "failed to verify two-factor code")
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || !user.IsActive {
+		writeError(w, r, errors.ErrUnauthorized)
+		return
+	}
+
+	if err := h.setSessionCookie(w, r, user); err != nil {
+		h.writeError(w, r, err, "failed to create JWT token")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user": user,
+	})
+}
+
+// GetTwoFactorStatus возвращает состояние 2FA текущего пользователя
+func (h *Handlers) GetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value("user").(*entities.User)
+
+	remaining := 0
+	if user.TOTPEnabled {
+		count, err := h.twoFactor.RemainingRecoveryCodes(r.Context(), user.ID)
+		if err != nil {
+			h.writeError(w, r, err, "failed to count recovery codes")
+			return
+		}
+		remaining = count
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled":                  user.TOTPEnabled,
+		"recovery_codes_remaining": remaining,
+	})
+}
+
+// SetupTwoFactor начинает подключение 2FA: возвращает секрет и URI для QR-кода
+func (h *Handlers) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value("user").(*entities.User)
+
+	setup, err := h.twoFactor.BeginEnrollment(r.Context(), user)
+	if err != nil {
+		h.writeError(w, r, err, "failed to begin two-factor enrollment")
+		return
+	}
+
+	json.NewEncoder(w).Encode(setup)
+}
+
+// EnableTwoFactor подтверждает подключение 2FA первым кодом и выдает коды восстановления
+func (h *Handlers) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	code, ok := decodeTwoFactorCode(w, r)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, err := h.twoFactor.ConfirmEnrollment(r.Context(), userID, code)
+	if err != nil {
+		h.writeError(w, r, err, "failed to enable two-factor")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled":        true,
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// DisableTwoFactor отключает 2FA (нужен действующий код или код восстановления)
+func (h *Handlers) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	code, ok := decodeTwoFactorCode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.twoFactor.Disable(r.Context(), userID, code); err != nil {
+		h.writeError(w, r, err, "failed to disable two-factor")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled": false,
+	})
+}
+
+// RegenerateRecoveryCodes выдает новый набор кодов восстановления
+func (h *Handlers) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	code, ok := decodeTwoFactorCode(w, r)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, err := h.twoFactor.RegenerateRecoveryCodes(r.Context(), userID, code)
+	if err != nil {
+		h.writeError(w, r, err, "failed to regenerate recovery codes")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+func decodeTwoFactorCode(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var request struct {
+		Code string `json:"code"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return "", false
+	}
+
+	v := validation.New()
+	v.Required("code", request.Code)
+	if err := v.Err(); err != nil {
+		writeError(w, r, err)
+		return "", false
+	}
+
+	return request.Code, true
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GetWebhooks возвращает все подписки на события (только для администраторов)
+func (h *Handlers) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.webhooks.GetAll(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "failed to get webhooks")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"webhooks":    webhooks,
+		"event_types": entities.WebhookEventTypes,
+	})
+}
+
+// CreateWebhook создает подписку. Секрет для проверки подписи возвращается только здесь
+// и при его замене.
+func (h *Handlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(uuid.UUID)
+
+	webhook := &entities.Webhook{IsActive: true}
+	if err := json.NewDecoder(r.Body).Decode(webhook); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+
+	if err := h.webhooks.Create(r.Context(), userID, webhook); err != nil {
+		h.writeError(w, r, err, "failed to create webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"webhook": webhook,
+		"secret":  webhook.Secret,
+	})
+}
+
+// UpdateWebhook изменяет подписку; незаданные в запросе поля сохраняют текущие значения
+func (h *Handlers) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhooks.GetByID(r.Context(), webhookID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to get webhook")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(webhook); err != nil {
+		writeError(w, r, errors.ErrInvalidBody)
+		return
+	}
+	webhook.ID = webhookID
+
+	if err := h.webhooks.Update(r.Context(), webhook); err != nil {
+		h.writeError(w, r, err, "failed to update webhook")
+		return
+	}
+
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом доставок
+func (h *Handlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhooks.Delete(r.Context(), webhookID); err != nil {
+		h.writeError(w, r, err, "failed to delete webhook")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// RotateWebhookSecret выдает подписке новый секрет
+func (h *Handlers) RotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhooks.RotateSecret(r.Context(), webhookID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to rotate webhook secret")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"webhook": webhook,
+		"secret":  webhook.Secret,
+	})
+}
+
+// GetWebhookDeliveries возвращает журнал доставок подписки: ?cursor=...&limit=...
+func (h *Handlers) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	params, ok := parsePageParams(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.webhooks.Deliveries(r.Context(), webhookID, params)
+	if err != nil {
+		h.writeError(w, r, err, "failed to get webhook deliveries")
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+// RetryWebhookDelivery ставит доставку в очередь повторно
+func (h *Handlers) RetryWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "deliveryId"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid delivery ID"))
+		return
+	}
+
+	found, err := h.webhooks.RetryDelivery(r.Context(), webhookID, deliveryID)
+	if err != nil {
+		h.writeError(w, r, err, "failed to retry webhook delivery", zap.String("delivery_id", deliveryID.String()))
+		return
+	}
+	if !found {
+		writeError(w, r, errors.ErrDeliveryNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	webhookID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errors.ErrInvalidID.WithMessage("invalid webhook ID"))
+		return uuid.Nil, false
+	}
+	return webhookID, true
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category категория проектов; список ведут администраторы
+type Category struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Slug        string    `json:"slug" db:"slug"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Position    int       `json:"position" db:"position"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectFilter отбор проектов запуска; пустые поля не ограничивают выборку
+type ProjectFilter struct {
+	LaunchID     uuid.UUID
+	CategoryID   *uuid.UUID
+	Tag          string
+	Platform     string
+	Stage        ProjectStage
+	PricingModel PricingModel
+}
+
+// LeaderboardEntry проект и его место в рейтинге
+type LeaderboardEntry struct {
+	Place   int      `json:"place"`
+	Project *Project `json:"project"`
+}
+
+// CategoryLeaderboard рейтинг проектов категории в одном запуске
+type CategoryLeaderboard struct {
+	Category *Category          `json:"category"`
+	Launch   *Launch            `json:"launch"`
+	Projects []LeaderboardEntry `json:"projects"`
+}
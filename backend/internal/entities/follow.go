@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FeedItemType string
+
+const (
+	FeedItemProjectLaunched FeedItemType = "project_launched"
+	FeedItemCommentCreated  FeedItemType = "comment_created"
+)
+
+// FeedItem событие персональной ленты: новый проект мейкера или комментарий к проекту
+type FeedItem struct {
+	Type        FeedItemType `json:"type" db:"type"`
+	ID          uuid.UUID    `json:"id" db:"id"`
+	ProjectID   uuid.UUID    `json:"project_id" db:"project_id"`
+	ProjectName string       `json:"project_name" db:"project_name"`
+	ProjectLogo *string      `json:"project_logo" db:"project_logo"`
+	Content     *string      `json:"content,omitempty" db:"content"`
+	// Автор события: мейкер проекта или автор комментария
+	ActorID       uuid.UUID `json:"actor_id" db:"actor_id"`
+	ActorUsername string    `json:"actor_username" db:"actor_username"`
+	ActorAvatar   string    `json:"actor_avatar" db:"actor_avatar"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// FollowStats счетчики подписок пользователя
+type FollowStats struct {
+	Followers int `json:"followers"`
+	Following int `json:"following"`
+}
@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type GalleryItemType string
+
+const (
+	// GalleryImage изображение из нашего хранилища
+	GalleryImage GalleryItemType = "image"
+	// GalleryVideo ролик mp4/webm из нашего хранилища
+	GalleryVideo GalleryItemType = "video"
+	// GalleryEmbed ролик YouTube или Vimeo, встраиваемый плеером сервиса
+	GalleryEmbed GalleryItemType = "embed"
+)
+
+var GalleryItemTypes = []GalleryItemType{
+	GalleryImage,
+	GalleryVideo,
+	GalleryEmbed,
+}
+
+func (t GalleryItemType) Valid() bool {
+	for _, itemType := range GalleryItemTypes {
+		if t == itemType {
+			return true
+		}
+	}
+	return false
+}
+
+// GalleryItem элемент галереи проекта. URL - файл нашего хранилища для image и
+// video или страница ролика для embed. Provider и EmbedURL заполняются сервером
+// для встраиваемых роликов.
+type GalleryItem struct {
+	Type      GalleryItemType `json:"type"`
+	URL       string          `json:"url"`
+	Caption   string          `json:"caption,omitempty"`
+	Thumbnail string          `json:"thumbnail,omitempty"`
+	Provider  string          `json:"provider,omitempty"`
+	EmbedURL  string          `json:"embed_url,omitempty"`
+}
+
+// ProjectGallery элементы галереи в порядке показа; хранятся в колонке JSONB
+type ProjectGallery []GalleryItem
+
+// GalleryFromImages собирает галерею из прежнего списка изображений
+func GalleryFromImages(images []string) ProjectGallery {
+	gallery := make(ProjectGallery, 0, len(images))
+	for _, image := range images {
+		gallery = append(gallery, GalleryItem{Type: GalleryImage, URL: image})
+	}
+	return gallery
+}
+
+// Images возвращает URL изображений галереи; из них состоит поле images
+// проекта для клиентов, не знающих о галерее
+func (g ProjectGallery) Images() StringArray {
+	images := StringArray{}
+	for _, item := range g {
+		if item.Type == GalleryImage {
+			images = append(images, item.URL)
+		}
+	}
+	return images
+}
+
+// Value implements the driver.Valuer interface
+func (g ProjectGallery) Value() (driver.Value, error) {
+	if g == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]GalleryItem(g))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (g *ProjectGallery) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*g = ProjectGallery{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]GalleryItem)(g))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]GalleryItem)(g))
+	default:
+		return fmt.Errorf("cannot scan %T into ProjectGallery", value)
+	}
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Image загрузка изображения пользователем. Проекты и профили ссылаются на
+// загрузки по URL файла, связи хранятся отдельно и обновляются при сохранении.
+// Файл адресуется хешем содержимого и может быть общим для нескольких загрузок.
+type Image struct {
+	ID       uuid.UUID  `json:"id" db:"id"`
+	FileName string     `json:"file_name" db:"file_name"`
+	OwnerID  *uuid.UUID `json:"owner_id" db:"owner_id"`
+	Size     int64      `json:"size" db:"size"`
+	MimeType string     `json:"mime_type" db:"mime_type"`
+	// Hash SHA-256 сохраненного файла в hex; пустой для файлов, загруженных до учета
+	Hash      string    `json:"hash" db:"hash"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ImageUsage загрузки пользователя: всего и за последний период (Recent)
+type ImageUsage struct {
+	TotalFiles  int64 `db:"total_files"`
+	TotalBytes  int64 `db:"total_bytes"`
+	RecentFiles int64 `db:"recent_files"`
+	RecentBytes int64 `db:"recent_bytes"`
+	// OldestRecent время самой ранней загрузки за период
+	OldestRecent *time.Time `db:"oldest_recent"`
+}
+
+// QuotaUsage использование одного лимита; Limit и Remaining равны null, если
+// лимит не ограничен
+type QuotaUsage struct {
+	Limit     *int64 `json:"limit"`
+	Used      int64  `json:"used"`
+	Remaining *int64 `json:"remaining"`
+}
+
+// UploadQuota квоты загрузок пользователя за последние сутки и за все время
+type UploadQuota struct {
+	DailyFiles QuotaUsage `json:"daily_files"`
+	DailyBytes QuotaUsage `json:"daily_bytes"`
+	TotalFiles QuotaUsage `json:"total_files"`
+	TotalBytes QuotaUsage `json:"total_bytes"`
+	// DailyResetAt время, когда самая ранняя загрузка выйдет из суточного окна
+	DailyResetAt *time.Time `json:"daily_reset_at,omitempty"`
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionPending      SubscriptionStatus = "pending"
+	SubscriptionConfirmed    SubscriptionStatus = "confirmed"
+	SubscriptionUnsubscribed SubscriptionStatus = "unsubscribed"
+)
+
+// NewsletterSubscription подписка email на дайджесты запусков
+type NewsletterSubscription struct {
+	ID               uuid.UUID          `json:"id" db:"id"`
+	Email            string             `json:"email" db:"email"`
+	Status           SubscriptionStatus `json:"status" db:"status"`
+	ConfirmToken     sql.NullString     `json:"-" db:"confirm_token"`
+	UnsubscribeToken string             `json:"-" db:"unsubscribe_token"`
+	ConfirmedAt      *time.Time         `json:"confirmed_at" db:"confirmed_at"`
+	UnsubscribedAt   *time.Time         `json:"unsubscribed_at" db:"unsubscribed_at"`
+	CreatedAt        time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	// NotificationComment новый комментарий к проекту пользователя
+	NotificationComment NotificationType = "comment"
+	// NotificationVoteMilestone проект набрал очередную отметку лайков (1, 10, 25, ...)
+	NotificationVoteMilestone NotificationType = "vote_milestone"
+	// NotificationLaunchResult итоговое место проекта после завершения запуска
+	NotificationLaunchResult NotificationType = "launch_result"
+	// NotificationLaunchStarted начался новый запуск
+	NotificationLaunchStarted NotificationType = "launch_started"
+	// NotificationProjectInvitation приглашение в команду проекта
+	NotificationProjectInvitation NotificationType = "project_invitation"
+)
+
+type Notification struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	UserID    uuid.UUID        `json:"-" db:"user_id"`
+	Type      NotificationType `json:"type" db:"type"`
+	ProjectID *uuid.UUID       `json:"project_id,omitempty" db:"project_id"`
+	ActorID   *uuid.UUID       `json:"actor_id,omitempty" db:"actor_id"`
+	CommentID *uuid.UUID       `json:"comment_id,omitempty" db:"comment_id"`
+	LaunchID  *uuid.UUID       `json:"launch_id,omitempty" db:"launch_id"`
+	Milestone *int             `json:"milestone,omitempty" db:"milestone"`
+	Place     *int             `json:"place,omitempty" db:"place"`
+	DedupKey  sql.NullString   `json:"-" db:"dedup_key"`
+	ReadAt    *time.Time       `json:"read_at" db:"read_at"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	// Данные для отображения, подтягиваются при чтении списка
+	ProjectName   *string `json:"project_name,omitempty" db:"project_name"`
+	ActorUsername *string `json:"actor_username,omitempty" db:"actor_username"`
+	LaunchName    *string `json:"launch_name,omitempty" db:"launch_name"`
+	Content       *string `json:"content,omitempty" db:"content"`
+}
+
+// NotificationPreferences включенные типы уведомлений и каналы доставки
+type NotificationPreferences struct {
+	Comments       bool `json:"comments" db:"notify_comments"`
+	VoteMilestones bool `json:"vote_milestones" db:"notify_vote_milestones"`
+	LaunchResults  bool `json:"launch_results" db:"notify_launch_results"`
+	LaunchStarted  bool `json:"launch_started" db:"notify_launch_started"`
+	// Telegram дублирует уведомления в бота, если аккаунт привязан к Telegram
+	Telegram bool `json:"telegram" db:"notify_telegram"`
+}
+
+// Allows сообщает, хочет ли пользователь получать уведомления данного типа
+func (p NotificationPreferences) Allows(notificationType NotificationType) bool {
+	switch notificationType {
+	case NotificationComment:
+		return p.Comments
+	case NotificationVoteMilestone:
+		return p.VoteMilestones
+	case NotificationLaunchResult:
+		return p.LaunchResults
+	case NotificationLaunchStarted:
+		return p.LaunchStarted
+	default:
+		return true
+	}
+}
@@ -11,19 +11,30 @@ import (
 type Project struct {
 	ID              uuid.UUID   `json:"id" db:"id"`
 	Name            string      `json:"name" db:"name"`
+	Tagline         string      `json:"tagline" db:"tagline"`
 	Description     string      `json:"description" db:"description"`
 	FullDescription string      `json:"full_description" db:"full_description"`
 	Logo            *string     `json:"logo" db:"logo"`
+	// Images изображения галереи (только type=image) для прежних клиентов
 	Images          StringArray `json:"images" db:"images"`
+	Gallery         ProjectGallery `json:"gallery" db:"gallery"`
 	Creators        StringArray `json:"creators" db:"creators"`
+	Tags            StringArray `json:"tags" db:"tags"`
 	TelegramContact sql.NullString `json:"telegram_contact" db:"telegram_contact"`
 	Website         sql.NullString `json:"website" db:"website"`
+	Links           ProjectLinks `json:"links" db:"links"`
+	PricingModel    PricingModel `json:"pricing_model" db:"pricing_model"`
+	Platforms       StringArray `json:"platforms" db:"platforms"`
+	Stage           ProjectStage `json:"stage" db:"stage"`
 	Upvotes         int         `json:"upvotes" db:"upvotes"`
 	Rating          int         `json:"rating" db:"rating"` // equals upvotes
+	CategoryID      *uuid.UUID  `json:"category_id" db:"category_id"`
 	LaunchID        uuid.UUID   `json:"launch_id" db:"launch_id"`
 	UserID          uuid.UUID   `json:"user_id" db:"user_id"`
 	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time   `json:"updated_at" db:"updated_at"`
+	// Makers команда проекта; заполняется только при получении одного проекта
+	Makers []*ProjectMember `json:"makers,omitempty" db:"-"`
 }
 
 // MarshalJSON кастомная сериализация для Project
@@ -49,3 +60,17 @@ type Launch struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// ProjectPlacement проект пользователя с местом в своем запуске
+type ProjectPlacement struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Logo        *string   `json:"logo" db:"logo"`
+	Upvotes     int       `json:"upvotes" db:"upvotes"`
+	LaunchID    uuid.UUID `json:"launch_id" db:"launch_id"`
+	LaunchName  string    `json:"launch_name" db:"launch_name"`
+	LaunchEnded bool      `json:"launch_ended" db:"launch_ended"`
+	Placement   int       `json:"placement" db:"placement"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,163 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type ProjectLinkType string
+
+const (
+	LinkAppStore   ProjectLinkType = "app_store"
+	LinkGooglePlay ProjectLinkType = "google_play"
+	LinkGitHub     ProjectLinkType = "github"
+	LinkDemoVideo  ProjectLinkType = "demo_video"
+	LinkTwitter    ProjectLinkType = "twitter"
+)
+
+// ProjectLinkTypes допустимые типы ссылок проекта
+var ProjectLinkTypes = []ProjectLinkType{
+	LinkAppStore,
+	LinkGooglePlay,
+	LinkGitHub,
+	LinkDemoVideo,
+	LinkTwitter,
+}
+
+func (t ProjectLinkType) Valid() bool {
+	for _, linkType := range ProjectLinkTypes {
+		if t == linkType {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectLink типизированная ссылка проекта
+type ProjectLink struct {
+	Type ProjectLinkType `json:"type"`
+	URL  string          `json:"url"`
+}
+
+// ProjectLinks ссылки проекта; хранятся в колонке JSONB
+type ProjectLinks []ProjectLink
+
+// Value implements the driver.Valuer interface
+func (pl ProjectLinks) Value() (driver.Value, error) {
+	if pl == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]ProjectLink(pl))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (pl *ProjectLinks) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*pl = ProjectLinks{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]ProjectLink)(pl))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]ProjectLink)(pl))
+	default:
+		return fmt.Errorf("cannot scan %T into ProjectLinks", value)
+	}
+}
+
+// PricingModel модель монетизации проекта; пустая строка - не указана
+type PricingModel string
+
+const (
+	PricingFree         PricingModel = "free"
+	PricingFreemium     PricingModel = "freemium"
+	PricingPaid         PricingModel = "paid"
+	PricingSubscription PricingModel = "subscription"
+	PricingOpenSource   PricingModel = "open_source"
+)
+
+var PricingModels = []PricingModel{
+	PricingFree,
+	PricingFreemium,
+	PricingPaid,
+	PricingSubscription,
+	PricingOpenSource,
+}
+
+// Valid проверяет модель монетизации; пустое значение допустимо
+func (m PricingModel) Valid() bool {
+	if m == "" {
+		return true
+	}
+	for _, model := range PricingModels {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectStage стадия проекта; пустая строка - не указана
+type ProjectStage string
+
+const (
+	StageIdea    ProjectStage = "idea"
+	StageMVP     ProjectStage = "mvp"
+	StageRevenue ProjectStage = "revenue"
+)
+
+var ProjectStages = []ProjectStage{
+	StageIdea,
+	StageMVP,
+	StageRevenue,
+}
+
+// Valid проверяет стадию; пустое значение допустимо
+func (s ProjectStage) Valid() bool {
+	if s == "" {
+		return true
+	}
+	for _, stage := range ProjectStages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Платформы, на которых доступен проект
+const (
+	PlatformWeb      = "web"
+	PlatformIOS      = "ios"
+	PlatformAndroid  = "android"
+	PlatformMacOS    = "macos"
+	PlatformWindows  = "windows"
+	PlatformLinux    = "linux"
+	PlatformTelegram = "telegram"
+	PlatformAPI      = "api"
+)
+
+var ProjectPlatforms = []string{
+	PlatformWeb,
+	PlatformIOS,
+	PlatformAndroid,
+	PlatformMacOS,
+	PlatformWindows,
+	PlatformLinux,
+	PlatformTelegram,
+	PlatformAPI,
+}
+
+func IsProjectPlatform(platform string) bool {
+	for _, known := range ProjectPlatforms {
+		if platform == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// RateLimitCounter состояние счетчика запросов в текущем окне
+type RateLimitCounter struct {
+	Key          string     `json:"key" db:"bucket_key"`
+	Count        int        `json:"count" db:"count"`
+	ResetAt      time.Time  `json:"reset_at" db:"reset_at"`
+	BlockedUntil *time.Time `json:"blocked_until" db:"blocked_until"`
+}
+
+// IsBlocked проверяет, заблокирован ли ключ на момент now
+func (c *RateLimitCounter) IsBlocked(now time.Time) bool {
+	return c.BlockedUntil != nil && now.Before(*c.BlockedUntil)
+}
@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SearchScope string
+
+const (
+	SearchProjects SearchScope = "projects"
+	SearchMakers   SearchScope = "makers"
+	SearchComments SearchScope = "comments"
+)
+
+// SearchFilter параметры поиска. Запуск и период ограничивают все разделы:
+// проекты и комментарии по дате создания, мейкеров - по проектам их команд.
+type SearchFilter struct {
+	Query    string
+	LaunchID *uuid.UUID
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+}
+
+// ProjectSearchResult найденный проект и его релевантность
+type ProjectSearchResult struct {
+	Project *Project `json:"project"`
+	Rank    float64  `json:"rank"`
+}
+
+// MakerSearchResult найденный мейкер (только пользователи с публичным профилем)
+type MakerSearchResult struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Username    string    `json:"username" db:"username"`
+	FirstName   string    `json:"-" db:"first_name"`
+	LastName    string    `json:"-" db:"last_name"`
+	DisplayName string    `json:"display_name" db:"-"`
+	Avatar      string    `json:"avatar" db:"avatar"`
+	Rank        float64   `json:"rank" db:"rank"`
+}
+
+// CommentSearchResult найденный комментарий вместе с проектом и автором
+type CommentSearchResult struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	ProjectID   uuid.UUID `json:"project_id" db:"project_id"`
+	ProjectName string    `json:"project_name" db:"project_name"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	Username    string    `json:"username" db:"username"`
+	Content     string    `json:"content" db:"content"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	Rank        float64   `json:"rank" db:"rank"`
+}
+
+// SearchResults результаты поиска по разделам; незапрошенные разделы равны null
+type SearchResults struct {
+	Query    string                 `json:"query"`
+	Projects []*ProjectSearchResult `json:"projects"`
+	Makers   []*MakerSearchResult   `json:"makers"`
+	Comments []*CommentSearchResult `json:"comments"`
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProjectRole string
+
+const (
+	// RoleOwner автор проекта: управляет командой
+	RoleOwner ProjectRole = "owner"
+	// RoleMaker соавтор: может редактировать проект
+	RoleMaker ProjectRole = "maker"
+)
+
+// ProjectMember участник команды проекта с данными профиля для отображения
+type ProjectMember struct {
+	ProjectID     uuid.UUID   `json:"-" db:"project_id"`
+	UserID        uuid.UUID   `json:"user_id" db:"user_id"`
+	Role          ProjectRole `json:"role" db:"role"`
+	Username      string      `json:"username" db:"username"`
+	FirstName     string      `json:"-" db:"first_name"`
+	LastName      string      `json:"-" db:"last_name"`
+	DisplayName   string      `json:"display_name" db:"-"`
+	Avatar        string      `json:"avatar" db:"avatar"`
+	ProfilePublic bool        `json:"profile_public" db:"profile_public"`
+	JoinedAt      time.Time   `json:"joined_at" db:"created_at"`
+}
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// ProjectInvitation приглашение в команду проекта
+type ProjectInvitation struct {
+	ID          uuid.UUID        `json:"id" db:"id"`
+	ProjectID   uuid.UUID        `json:"project_id" db:"project_id"`
+	InviterID   *uuid.UUID       `json:"inviter_id" db:"inviter_id"`
+	InviteeID   *uuid.UUID       `json:"invitee_id,omitempty" db:"invitee_id"`
+	Email       *string          `json:"email,omitempty" db:"email"`
+	TokenHash   sql.NullString   `json:"-" db:"token_hash"`
+	Role        ProjectRole      `json:"role" db:"role"`
+	Status      InvitationStatus `json:"status" db:"status"`
+	ExpiresAt   time.Time        `json:"expires_at" db:"expires_at"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	RespondedAt *time.Time       `json:"responded_at,omitempty" db:"responded_at"`
+	// Данные для отображения, подтягиваются при чтении
+	ProjectName     string  `json:"project_name" db:"project_name"`
+	InviterUsername *string `json:"inviter_username,omitempty" db:"inviter_username"`
+	InviteeUsername *string `json:"invitee_username,omitempty" db:"invitee_username"`
+}
+
+// Expired проверяет, истек ли срок приглашения
+func (i *ProjectInvitation) Expired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
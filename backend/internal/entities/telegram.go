@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusFailed  OutboxStatus = "failed"
+)
+
+// TelegramMessage сообщение в очереди на отправку через бота
+type TelegramMessage struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	UserID         uuid.UUID      `json:"user_id" db:"user_id"`
+	ChatID         int64          `json:"chat_id" db:"chat_id"`
+	Text           string         `json:"text" db:"text"`
+	NotificationID *uuid.UUID     `json:"notification_id" db:"notification_id"`
+	Status         OutboxStatus   `json:"status" db:"status"`
+	Attempts       int            `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError      sql.NullString `json:"-" db:"last_error"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	SentAt         *time.Time     `json:"sent_at" db:"sent_at"`
+}
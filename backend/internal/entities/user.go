@@ -8,21 +8,65 @@ import (
 )
 
 type User struct {
-	ID                uuid.UUID      `json:"id" db:"id"`
-	Username          string         `json:"username" db:"username"`
-	Email             string         `json:"email" db:"email"`
-	FirstName         string         `json:"first_name" db:"first_name"`
-	LastName          string         `json:"last_name" db:"last_name"`
-	PasswordHash      string         `json:"-" db:"password_hash"`
-	Avatar            string         `json:"avatar" db:"avatar"`
-	AuthType          AuthType       `json:"auth_type" db:"auth_type"`
-	AuthID            string         `json:"auth_id" db:"auth_id"`
-	TelegramID        *int64         `json:"telegram_id" db:"telegram_id"`
-	IsActive          bool           `json:"is_active" db:"is_active"`
-	EmailVerified     bool           `json:"email_verified" db:"email_verified"`
-	VerificationToken sql.NullString `json:"-" db:"verification_token"`
-	CreatedAt         time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time      `json:"updated_at" db:"updated_at"`
+	ID                  uuid.UUID      `json:"id" db:"id"`
+	Username            string         `json:"username" db:"username"`
+	Email               string         `json:"email" db:"email"`
+	FirstName           string         `json:"first_name" db:"first_name"`
+	LastName            string         `json:"last_name" db:"last_name"`
+	PasswordHash        string         `json:"-" db:"password_hash"`
+	Avatar              string         `json:"avatar" db:"avatar"`
+	AuthType            AuthType       `json:"auth_type" db:"auth_type"`
+	AuthID              string         `json:"auth_id" db:"auth_id"`
+	TelegramID          *int64         `json:"telegram_id" db:"telegram_id"`
+	TelegramUsername    sql.NullString `json:"-" db:"telegram_username"`
+	IsActive            bool           `json:"is_active" db:"is_active"`
+	IsAdmin             bool           `json:"is_admin" db:"is_admin"`
+	EmailVerified       bool           `json:"email_verified" db:"email_verified"`
+	VerificationToken   sql.NullString `json:"-" db:"verification_token"`
+	TOTPSecret          sql.NullString `json:"-" db:"totp_secret"`
+	TOTPEnabled         bool           `json:"totp_enabled" db:"totp_enabled"`
+	ProfilePublic       bool           `json:"profile_public" db:"profile_public"`
+	ShowTelegram        bool           `json:"show_telegram" db:"show_telegram"`
+	ShowActivity        bool           `json:"show_activity" db:"show_activity"`
+	DeletionScheduledAt *time.Time     `json:"deletion_scheduled_at" db:"deletion_scheduled_at"`
+	DeletedAt           *time.Time     `json:"-" db:"deleted_at"`
+	CreatedAt           time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at" db:"updated_at"`
+
+	NotificationPreferences `json:"notification_preferences"`
+}
+
+// PrivacySettings настройки видимости публичного профиля
+type PrivacySettings struct {
+	ProfilePublic bool `json:"profile_public" db:"profile_public"`
+	ShowTelegram  bool `json:"show_telegram" db:"show_telegram"`
+	ShowActivity  bool `json:"show_activity" db:"show_activity"`
+}
+
+// PublicUser данные пользователя, безопасные для показа другим (без email и auth_id)
+type PublicUser struct {
+	ID               uuid.UUID      `db:"id"`
+	Username         string         `db:"username"`
+	FirstName        string         `db:"first_name"`
+	LastName         string         `db:"last_name"`
+	Avatar           string         `db:"avatar"`
+	TelegramUsername sql.NullString `db:"telegram_username"`
+	CreatedAt        time.Time      `db:"created_at"`
+	PrivacySettings
+}
+
+// PublicProfile публичный профиль пользователя с историей активности
+type PublicProfile struct {
+	ID           uuid.UUID           `json:"id"`
+	Username     string              `json:"username"`
+	DisplayName  string              `json:"display_name"`
+	Avatar       string              `json:"avatar"`
+	Telegram     *string             `json:"telegram,omitempty"`
+	JoinedAt     time.Time           `json:"joined_at"`
+	Followers    int                 `json:"followers"`
+	Following    int                 `json:"following"`
+	CommentCount *int                `json:"comment_count,omitempty"`
+	Projects     []*ProjectPlacement `json:"projects"`
 }
 
 type AuthType string
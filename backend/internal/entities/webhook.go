@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookProjectCreated WebhookEventType = "project.created"
+	// WebhookProjectVoted изменение числа лайков проекта (в том числе снятие лайка)
+	WebhookProjectVoted   WebhookEventType = "project.voted"
+	WebhookCommentCreated WebhookEventType = "comment.created"
+	WebhookLaunchStarted  WebhookEventType = "launch.started"
+	WebhookLaunchFinished WebhookEventType = "launch.finished"
+)
+
+// WebhookEventTypes все события, на которые можно подписаться
+var WebhookEventTypes = []WebhookEventType{
+	WebhookProjectCreated,
+	WebhookProjectVoted,
+	WebhookCommentCreated,
+	WebhookLaunchStarted,
+	WebhookLaunchFinished,
+}
+
+// Webhook подписка внешней системы на события платформы
+type Webhook struct {
+	ID          uuid.UUID   `json:"id" db:"id"`
+	URL         string      `json:"url" db:"url"`
+	Secret      string      `json:"-" db:"secret"`
+	EventTypes  StringArray `json:"event_types" db:"event_types"`
+	Description string      `json:"description" db:"description"`
+	IsActive    bool        `json:"is_active" db:"is_active"`
+	CreatedBy   *uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookEvent тело запроса, которое получает подписчик
+type WebhookEvent struct {
+	ID        uuid.UUID        `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      interface{}      `json:"data"`
+}
+
+// WebhookDelivery попытки доставки одного события одной подписке
+type WebhookDelivery struct {
+	ID             uuid.UUID        `json:"id" db:"id"`
+	WebhookID      uuid.UUID        `json:"webhook_id" db:"webhook_id"`
+	EventID        uuid.UUID        `json:"event_id" db:"event_id"`
+	EventType      WebhookEventType `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage  `json:"payload" db:"payload"`
+	Status         OutboxStatus     `json:"status" db:"status"`
+	Attempts       int              `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time        `json:"next_attempt_at" db:"next_attempt_at"`
+	ResponseStatus *int             `json:"response_status" db:"response_status"`
+	ResponseBody   *string          `json:"response_body" db:"response_body"`
+	DurationMs     *int             `json:"duration_ms" db:"duration_ms"`
+	LastError      *string          `json:"last_error" db:"last_error"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time       `json:"delivered_at" db:"delivered_at"`
+}
+
+// WebhookAttempt результат одной попытки доставки
+type WebhookAttempt struct {
+	ResponseStatus int
+	ResponseBody   string
+	Duration       time.Duration
+	Error          string
+}
@@ -1,12 +1,43 @@
 package errors
 
-import "errors"
+import (
+	"fmt"
+	"time"
+)
 
 // Authentication errors
 var (
-	ErrUserNotFound        = errors.New("user not found")
-	ErrInvalidPassword     = errors.New("invalid password")
-	ErrEmailExists         = errors.New("user with this email already exists")
-	ErrUsernameExists      = errors.New("user with this username already exists")
-	ErrInvalidTelegramHash = errors.New("invalid telegram hash")
+	ErrUserNotFound        = New(KindNotFound, "user_not_found", "user not found")
+	ErrInvalidPassword     = New(KindUnauthorized, "invalid_password", "invalid password")
+	ErrInvalidCredentials  = New(KindUnauthorized, "invalid_credentials", "invalid credentials")
+	ErrTooManyAttempts     = New(KindTooManyRequests, "too_many_attempts", "too many attempts")
+	ErrEmailExists         = New(KindConflict, "email_taken", "user with this email already exists")
+	ErrUsernameExists      = New(KindConflict, "username_taken", "user with this username already exists")
+	ErrInvalidTelegramHash = New(KindInvalid, "invalid_telegram_hash", "invalid telegram hash")
+)
+
+// LockoutError возвращается, когда вход временно заблокирован после неудачных попыток
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s: retry after %s", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockoutError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// Two-factor authentication errors
+var (
+	ErrTwoFactorNotEnabled     = New(KindInvalid, "two_factor_not_enabled", "two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled = New(KindConflict, "two_factor_already_enabled", "two-factor authentication is already enabled")
+	ErrTwoFactorNotInitiated   = New(KindInvalid, "two_factor_not_initiated", "two-factor setup has not been started")
+	ErrInvalidTwoFactorCode    = New(KindUnauthorized, "invalid_two_factor_code", "invalid two-factor code")
+)
+
+// Account errors
+var (
+	ErrDeletionNotScheduled = New(KindConflict, "deletion_not_scheduled", "account deletion is not scheduled")
 )
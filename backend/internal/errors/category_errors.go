@@ -0,0 +1,7 @@
+package errors
+
+// Category errors
+var (
+	ErrCategoryNotFound   = New(KindNotFound, "category_not_found", "category not found")
+	ErrCategorySlugExists = New(KindConflict, "category_slug_taken", "category slug already exists")
+)
@@ -0,0 +1,7 @@
+package errors
+
+// Comment errors
+var (
+	ErrCommentNotFound  = New(KindNotFound, "comment_not_found", "comment not found")
+	ErrNotCommentAuthor = New(KindForbidden, "not_comment_author", "comment does not belong to user")
+)
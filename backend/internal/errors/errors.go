@@ -0,0 +1,46 @@
+// Package errors содержит доменные ошибки приложения. Каждая ошибка имеет вид,
+// по которому API выбирает HTTP-статус, и машиночитаемый код для клиентов.
+package errors
+
+// Kind вид доменной ошибки
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindGone
+	KindTooLarge
+	KindUnsupported
+	KindTooManyRequests
+)
+
+// Error доменная ошибка с кодом и сообщением для клиента
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+// New создает доменную ошибку
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is сравнивает ошибки по коду, поэтому копии из WithMessage совпадают с исходной ошибкой
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithMessage возвращает ту же ошибку с уточненным сообщением
+func (e *Error) WithMessage(message string) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: message}
+}
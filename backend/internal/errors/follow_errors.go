@@ -0,0 +1,6 @@
+package errors
+
+// Follow errors
+var (
+	ErrCannotFollowSelf = New(KindInvalid, "cannot_follow_self", "cannot follow yourself")
+)
@@ -0,0 +1,16 @@
+package errors
+
+// Image errors
+var (
+	ErrUnknownImageSize         = New(KindInvalid, "unknown_image_size", "unknown image size")
+	ErrImageNotFound            = New(KindNotFound, "image_not_found", "image not found")
+	ErrInvalidFileName          = New(KindInvalid, "invalid_file_name", "invalid file name")
+	ErrFileTooLarge             = New(KindTooLarge, "file_too_large", "file size exceeds the allowed maximum")
+	ErrInvalidImage             = New(KindUnsupported, "invalid_image", "invalid image file")
+	ErrImageDimensionsTooLarge  = New(KindTooLarge, "image_too_large", "image dimensions exceed the allowed maximum")
+	ErrInvalidVideo             = New(KindUnsupported, "invalid_video", "invalid video file")
+	ErrVideoTooLong             = New(KindTooLarge, "video_too_long", "video duration exceeds the allowed maximum")
+	ErrUploadQuotaExceeded      = New(KindTooLarge, "upload_quota_exceeded", "upload quota exceeded")
+	ErrDailyUploadQuotaExceeded = New(KindTooManyRequests, "daily_upload_quota_exceeded", "daily upload quota exceeded")
+	ErrProjectImageLimit        = New(KindTooLarge, "project_image_limit", "project image limit reached")
+)
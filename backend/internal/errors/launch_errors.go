@@ -0,0 +1,7 @@
+package errors
+
+// Launch errors
+var (
+	ErrLaunchNotFound = New(KindNotFound, "launch_not_found", "launch not found")
+	ErrNoActiveLaunch = New(KindConflict, "no_active_launch", "no active launch found")
+)
@@ -0,0 +1,6 @@
+package errors
+
+// Newsletter errors
+var (
+	ErrSubscriptionNotFound = New(KindNotFound, "subscription_not_found", "newsletter subscription not found")
+)
@@ -0,0 +1,6 @@
+package errors
+
+// Notification errors
+var (
+	ErrNotificationNotFound = New(KindNotFound, "notification_not_found", "notification not found")
+)
@@ -0,0 +1,6 @@
+package errors
+
+// Project errors
+var (
+	ErrProjectNotFound = New(KindNotFound, "project_not_found", "project not found")
+)
@@ -0,0 +1,17 @@
+package errors
+
+// Request errors. Возвращаются API до обращения к сервисам: некорректный запрос,
+// отсутствие сессии или прав, превышение лимитов.
+var (
+	ErrInvalidRequest  = New(KindInvalid, "invalid_request", "invalid request")
+	ErrInvalidBody     = New(KindInvalid, "invalid_body", "invalid request body")
+	ErrInvalidID       = New(KindInvalid, "invalid_id", "invalid identifier")
+	ErrUnauthorized    = New(KindUnauthorized, "unauthorized", "authentication required")
+	ErrInvalidToken    = New(KindUnauthorized, "invalid_token", "invalid token")
+	ErrSessionExpired  = New(KindUnauthorized, "session_expired", "login session expired, please sign in again")
+	ErrAccountInactive = New(KindForbidden, "account_inactive", "account is deactivated")
+	ErrForbidden       = New(KindForbidden, "forbidden", "access denied")
+	ErrRateLimited     = New(KindTooManyRequests, "rate_limited", "too many requests, try again later")
+	ErrRouteNotFound   = New(KindNotFound, "not_found", "resource not found")
+	ErrInternal        = New(KindInternal, "internal_error", "internal server error")
+)
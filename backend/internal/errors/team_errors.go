@@ -0,0 +1,12 @@
+package errors
+
+// Team errors
+var (
+	ErrNotProjectOwner     = New(KindForbidden, "not_project_owner", "only the project owner can manage the team")
+	ErrNotProjectMember    = New(KindForbidden, "not_project_member", "only project members can edit the project")
+	ErrAlreadyMember       = New(KindConflict, "already_member", "user is already a project member")
+	ErrCannotRemoveOwner   = New(KindConflict, "cannot_remove_owner", "project owner cannot be removed")
+	ErrInvitationNotFound  = New(KindNotFound, "invitation_not_found", "invitation not found")
+	ErrInvitationExists    = New(KindConflict, "invitation_exists", "invitation is already pending")
+	ErrInvitationNotActive = New(KindGone, "invitation_not_active", "invitation is no longer active")
+)
@@ -0,0 +1,7 @@
+package errors
+
+// Webhook errors
+var (
+	ErrWebhookNotFound  = New(KindNotFound, "webhook_not_found", "webhook not found")
+	ErrDeliveryNotFound = New(KindNotFound, "delivery_not_found", "webhook delivery not found")
+)
@@ -0,0 +1,144 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Category struct {
+	db *clients.PostgresClient
+}
+
+func NewCategoryRepository(db *clients.PostgresClient) repository.CategoryRepository {
+	return &Category{db: db}
+}
+
+func (r *Category) Create(ctx context.Context, category *entities.Category) error {
+	query := `
+		INSERT INTO categories (slug, name, description, position, created_at, updated_at)
+		VALUES (:slug, :name, :description, :position, :created_at, :updated_at)
+		RETURNING id
+	`
+	rows, err := r.db.GetDB().NamedQueryContext(ctx, query, category)
+	if err != nil {
+		if isCategorySlugViolation(err) {
+			return errors.ErrCategorySlugExists
+		}
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&category.ID); err != nil {
+			return fmt.Errorf("failed to get created category id: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Category) GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
+	query := `
+		SELECT * FROM categories WHERE id = $1
+	`
+	var category entities.Category
+	err := r.db.GetDB().GetContext(ctx, &category, query, id)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return &category, nil
+}
+
+func (r *Category) GetBySlug(ctx context.Context, slug string) (*entities.Category, error) {
+	query := `
+		SELECT * FROM categories WHERE slug = $1
+	`
+	var category entities.Category
+	err := r.db.GetDB().GetContext(ctx, &category, query, slug)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category by slug: %w", err)
+	}
+
+	return &category, nil
+}
+
+func (r *Category) GetAll(ctx context.Context) ([]*entities.Category, error) {
+	query := `
+		SELECT * FROM categories ORDER BY position, name
+	`
+	var categories []*entities.Category
+	err := r.db.GetDB().SelectContext(ctx, &categories, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r *Category) Update(ctx context.Context, category *entities.Category) error {
+	query := `
+		UPDATE categories SET
+			slug = :slug,
+			name = :name,
+			description = :description,
+			position = :position,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+	result, err := r.db.GetDB().NamedExecContext(ctx, query, category)
+	if err != nil {
+		if isCategorySlugViolation(err) {
+			return errors.ErrCategorySlugExists
+		}
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+
+	return categoryAffected(result)
+}
+
+func (r *Category) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM categories WHERE id = $1
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	return categoryAffected(result)
+}
+
+// isCategorySlugViolation проверяет нарушение уникальности slug категории
+func isCategorySlugViolation(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// categoryAffected возвращает ErrCategoryNotFound, если запрос не затронул ни одной категории
+func categoryAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errors.ErrCategoryNotFound
+	}
+
+	return nil
+}
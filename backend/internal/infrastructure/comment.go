@@ -2,14 +2,23 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	stderrors "errors"
 	"fmt"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/pagination"
 	"startup-scout/internal/repository"
 	"startup-scout/pkg/clients"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// commentColumns колонки comments для выборки в entities.Comment
+const commentColumns = "id, user_id, project_id, content, created_at, updated_at"
+
 type Comment struct {
 	db *clients.PostgresClient
 }
@@ -31,21 +40,35 @@ func (r *Comment) Create(
 			updated_at
 		)
 		VALUES (:user_id, :project_id, :content, :created_at, :updated_at)
+		RETURNING id
 	`
-	_, err := r.db.GetDB().NamedExecContext(ctx, query, comment)
+	rows, err := r.db.GetDB().NamedQueryContext(ctx, query, comment)
 	if err != nil {
+		if isProjectReferenceViolation(err) {
+			return errors.ErrProjectNotFound
+		}
 		return fmt.Errorf("failed to create comment: %w", err)
 	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&comment.ID); err != nil {
+			return fmt.Errorf("failed to get created comment id: %w", err)
+		}
+	}
 
 	return nil
 }
 
 func (r *Comment) GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error) {
 	query := `
-		SELECT * FROM comments WHERE id = $1
+		SELECT ` + commentColumns + ` FROM comments WHERE id = $1
 	`
 	var comment entities.Comment
 	err := r.db.GetDB().GetContext(ctx, &comment, query, id)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrCommentNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comment by id: %w", err)
 	}
@@ -58,7 +81,7 @@ func (r *Comment) GetByProjectID(
 	projectID uuid.UUID,
 ) ([]*entities.Comment, error) {
 	query := `
-		SELECT * FROM comments WHERE project_id = $1
+		SELECT ` + commentColumns + ` FROM comments WHERE project_id = $1
 	`
 	var comments []*entities.Comment
 	err := r.db.GetDB().SelectContext(ctx, &comments, query, projectID)
@@ -69,9 +92,40 @@ func (r *Comment) GetByProjectID(
 	return comments, nil
 }
 
+func (r *Comment) GetByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*entities.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + ` FROM comments WHERE user_id = $1 ORDER BY created_at DESC
+	`
+	var comments []*entities.Comment
+	err := r.db.GetDB().SelectContext(ctx, &comments, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments by user id: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (r *Comment) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM comments WHERE user_id = $1
+	`
+	var count int
+	err := r.db.GetDB().GetContext(ctx, &count, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comments by user id: %w", err)
+	}
+
+	return count, nil
+}
+
 func (r *Comment) GetByProjectIDWithUsers(
 	ctx context.Context,
 	projectID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
 ) ([]*entities.CommentWithUser, error) {
 	query := `
 		SELECT 
@@ -80,10 +134,20 @@ func (r *Comment) GetByProjectIDWithUsers(
 		FROM comments c
 		JOIN users u ON c.user_id = u.id
 		WHERE c.project_id = $1
-		ORDER BY c.created_at DESC
+			AND ($2::timestamp IS NULL OR (c.created_at, c.id) < ($2::timestamp, $3))
+		ORDER BY c.created_at DESC, c.id DESC
+		LIMIT $4
 	`
+
+	var before sql.NullTime
+	beforeID := uuid.Nil
+	if cursor != nil {
+		before = sql.NullTime{Time: cursor.Time, Valid: true}
+		beforeID = cursor.ID
+	}
+
 	var comments []*entities.CommentWithUser
-	err := r.db.GetDB().SelectContext(ctx, &comments, query, projectID)
+	err := r.db.GetDB().SelectContext(ctx, &comments, query, projectID, before, beforeID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments with users by project id: %w", err)
 	}
@@ -121,3 +185,9 @@ func (r *Comment) Delete(
 
 	return nil
 }
+
+// isProjectReferenceViolation проверяет, что запись ссылается на несуществующий проект
+func isProjectReferenceViolation(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == "23503" && strings.Contains(pqErr.Constraint, "project_id")
+}
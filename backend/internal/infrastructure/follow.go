@@ -0,0 +1,185 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/pagination"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+
+	"github.com/google/uuid"
+)
+
+type Follow struct {
+	db *clients.PostgresClient
+}
+
+func NewFollowRepository(db *clients.PostgresClient) repository.FollowRepository {
+	return &Follow{db: db}
+}
+
+func (r *Follow) FollowUser(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	query := `
+		INSERT INTO user_follows (follower_id, followee_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Follow) UnfollowUser(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	query := `
+		DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Follow) FollowProject(ctx context.Context, userID, projectID uuid.UUID) error {
+	query := `
+		INSERT INTO project_follows (user_id, project_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, project_id) DO NOTHING
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, userID, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to follow project: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Follow) UnfollowProject(ctx context.Context, userID, projectID uuid.UUID) error {
+	query := `
+		DELETE FROM project_follows WHERE user_id = $1 AND project_id = $2
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, userID, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow project: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Follow) GetStats(ctx context.Context, userID uuid.UUID) (*entities.FollowStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM user_follows WHERE followee_id = $1) AS followers,
+			(SELECT COUNT(*) FROM user_follows WHERE follower_id = $1) AS following
+	`
+	var stats entities.FollowStats
+	err := r.db.GetDB().QueryRowContext(ctx, query, userID).Scan(&stats.Followers, &stats.Following)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get follow stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// GetFeed объединяет новые проекты отслеживаемых мейкеров и новые комментарии
+// к отслеживаемым проектам. Каждая ветка ограничена курсором и limit отдельно,
+// чтобы Postgres читал только верхушку индексов (user_id|project_id, created_at, id),
+// а не всю историю подписок.
+func (r *Follow) GetFeed(
+	ctx context.Context,
+	userID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*entities.FeedItem, error) {
+	query := `
+		SELECT * FROM (
+			(
+				SELECT
+					'project_launched' AS type,
+					p.id,
+					p.id AS project_id,
+					p.name AS project_name,
+					p.logo AS project_logo,
+					NULL::text AS content,
+					p.user_id AS actor_id,
+					u.username AS actor_username,
+					COALESCE(u.avatar, '') AS actor_avatar,
+					p.created_at
+				FROM user_follows f
+				JOIN projects p ON p.user_id = f.followee_id
+				JOIN users u ON u.id = p.user_id
+				WHERE f.follower_id = $1
+					AND ($2::timestamp IS NULL OR (p.created_at, p.id) < ($2::timestamp, $3))
+				ORDER BY p.created_at DESC, p.id DESC
+				LIMIT $4
+			)
+			UNION ALL
+			(
+				SELECT
+					'comment_created' AS type,
+					c.id,
+					c.project_id,
+					p.name AS project_name,
+					p.logo AS project_logo,
+					c.content,
+					c.user_id AS actor_id,
+					u.username AS actor_username,
+					COALESCE(u.avatar, '') AS actor_avatar,
+					c.created_at
+				FROM project_follows f
+				JOIN comments c ON c.project_id = f.project_id
+				JOIN projects p ON p.id = c.project_id
+				JOIN users u ON u.id = c.user_id
+				WHERE f.user_id = $1
+					AND c.user_id <> $1
+					AND ($2::timestamp IS NULL OR (c.created_at, c.id) < ($2::timestamp, $3))
+				ORDER BY c.created_at DESC, c.id DESC
+				LIMIT $4
+			)
+		) feed
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+
+	var before sql.NullTime
+	afterID := uuid.Nil
+	if cursor != nil {
+		before = sql.NullTime{Time: cursor.Time, Valid: true}
+		afterID = cursor.ID
+	}
+
+	var items []*entities.FeedItem
+	err := r.db.GetDB().SelectContext(ctx, &items, query, userID, before, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *Follow) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_follows WHERE follower_id = $1 OR followee_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete user follows: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM project_follows WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete project follows: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
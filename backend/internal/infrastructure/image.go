@@ -0,0 +1,323 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// imageSelect выборка загрузок вместе с параметрами файла
+const imageSelect = `
+	SELECT i.id, i.file_name, i.owner_id, b.size, b.mime_type, b.hash, i.created_at
+	FROM images i
+	JOIN image_blobs b ON b.file_name = i.file_name
+`
+
+type Image struct {
+	db *clients.PostgresClient
+}
+
+func NewImageRepository(db *clients.PostgresClient) repository.ImageRepository {
+	return &Image{db: db}
+}
+
+func (r *Image) Create(
+	ctx context.Context,
+	image *entities.Image,
+	quotaSince time.Time,
+	checkQuota func(usage *entities.ImageUsage) error,
+) (bool, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокировка владельца выстраивает его загрузки в очередь, поэтому параллельные
+	// загрузки не пройдут проверку квоты по одному и тому же использованию
+	if checkQuota != nil {
+		_, err = tx.ExecContext(ctx, `
+			SELECT 1 FROM users WHERE id = $1 FOR NO KEY UPDATE
+		`, image.OwnerID)
+		if err != nil {
+			return false, fmt.Errorf("failed to lock image owner: %w", err)
+		}
+	}
+
+	// Upsert блокирует строку файла, поэтому параллельное удаление последней
+	// загрузки не удалит файл между проверкой и увеличением счетчика
+	var refCount int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO image_blobs (file_name, hash, size, mime_type, ref_count, created_at)
+		VALUES ($1, $2, $3, $4, 0, $5)
+		ON CONFLICT (file_name) DO UPDATE SET ref_count = image_blobs.ref_count
+		RETURNING ref_count
+	`, image.FileName, image.Hash, image.Size, image.MimeType, image.CreatedAt).Scan(&refCount)
+	if err != nil {
+		return false, fmt.Errorf("failed to create image blob: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE images SET created_at = $3
+		WHERE owner_id = $1 AND file_name = $2
+		RETURNING id
+	`, image.OwnerID, image.FileName, image.CreatedAt).Scan(&image.ID)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		if checkQuota != nil {
+			var usage entities.ImageUsage
+			err = tx.GetContext(ctx, &usage, imageUsageQuery, image.OwnerID, quotaSince)
+			if err != nil {
+				return false, fmt.Errorf("failed to get image usage: %w", err)
+			}
+			if err := checkQuota(&usage); err != nil {
+				return false, err
+			}
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO images (file_name, owner_id, created_at)
+			VALUES ($1, $2, $3)
+			RETURNING id
+		`, image.FileName, image.OwnerID, image.CreatedAt).Scan(&image.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to create image: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE image_blobs SET ref_count = ref_count + 1 WHERE file_name = $1
+		`, image.FileName)
+		if err != nil {
+			return false, fmt.Errorf("failed to increment image blob references: %w", err)
+		}
+	} else if err != nil {
+		return false, fmt.Errorf("failed to update image: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit image: %w", err)
+	}
+
+	return refCount == 0, nil
+}
+
+func (r *Image) GetByOwner(ctx context.Context, ownerID uuid.UUID, fileName string) (*entities.Image, error) {
+	query := imageSelect + `
+		WHERE i.owner_id = $1 AND i.file_name = $2
+	`
+	var image entities.Image
+	err := r.db.GetDB().GetContext(ctx, &image, query, ownerID, fileName)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrImageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image: %w", err)
+	}
+
+	return &image, nil
+}
+
+func (r *Image) GetAllByOwner(ctx context.Context, ownerID uuid.UUID) ([]*entities.Image, error) {
+	query := imageSelect + `
+		WHERE i.owner_id = $1
+		ORDER BY i.created_at, i.id
+	`
+	var images []*entities.Image
+	err := r.db.GetDB().SelectContext(ctx, &images, query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get images by owner: %w", err)
+	}
+
+	return images, nil
+}
+
+// imageUsageQuery использование загрузок владельца $1 всего и начиная с $2
+const imageUsageQuery = `
+	SELECT
+		COUNT(*) AS total_files,
+		COALESCE(SUM(b.size), 0) AS total_bytes,
+		COUNT(*) FILTER (WHERE i.created_at >= $2) AS recent_files,
+		COALESCE(SUM(b.size) FILTER (WHERE i.created_at >= $2), 0) AS recent_bytes,
+		MIN(i.created_at) FILTER (WHERE i.created_at >= $2) AS oldest_recent
+	FROM images i
+	JOIN image_blobs b ON b.file_name = i.file_name
+	WHERE i.owner_id = $1
+`
+
+func (r *Image) GetUsage(ctx context.Context, ownerID uuid.UUID, since time.Time) (*entities.ImageUsage, error) {
+	var usage entities.ImageUsage
+	err := r.db.GetDB().GetContext(ctx, &usage, imageUsageQuery, ownerID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image usage: %w", err)
+	}
+
+	return &usage, nil
+}
+
+func (r *Image) GetUsable(
+	ctx context.Context,
+	userID, projectID uuid.UUID,
+	fileNames []string,
+) ([]string, error) {
+	query := `
+		SELECT i.file_name FROM images i
+		WHERE i.file_name = ANY($1)
+			AND (
+				i.owner_id = $2
+				OR EXISTS (
+					SELECT 1 FROM image_references ref
+					WHERE ref.image_id = i.id AND ref.project_id = $3
+				)
+			)
+	`
+	var usable []string
+	err := r.db.GetDB().SelectContext(ctx, &usable, query, pq.Array(fileNames), userID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usable images: %w", err)
+	}
+
+	return usable, nil
+}
+
+// replaceProjectReferences заменяет ссылки проекта (логотип и галерея) на fileNames
+// в транзакции сохранения проекта. Уже прикрепленные загрузки сохраняются,
+// новые берутся из загрузок userID.
+func replaceProjectReferences(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	projectID, userID uuid.UUID,
+	fileNames []string,
+) error {
+	var previous []uuid.UUID
+	err := tx.SelectContext(ctx, &previous, `
+		DELETE FROM image_references WHERE project_id = $1 RETURNING image_id
+	`, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to delete project image references: %w", err)
+	}
+
+	// Один файл могут загрузить несколько пользователей: проект продолжает ссылаться
+	// на уже прикрепленную загрузку, для новых файлов берется загрузка userID
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO image_references (image_id, project_id, created_at)
+		SELECT DISTINCT ON (i.file_name) i.id, $1, NOW()
+		FROM images i
+		WHERE i.file_name = ANY($2) AND (i.id = ANY($3) OR i.owner_id = $4)
+		ORDER BY i.file_name, i.id = ANY($3) DESC
+	`, projectID, pq.Array(fileNames), pq.Array(previous), userID)
+	if err != nil {
+		return fmt.Errorf("failed to create project image references: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Image) ReplaceAvatarReference(ctx context.Context, userID uuid.UUID, fileName string) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM image_references WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete avatar reference: %w", err)
+	}
+
+	if fileName != "" {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO image_references (image_id, user_id, created_at)
+			SELECT id, $1, NOW() FROM images WHERE owner_id = $1 AND file_name = $2
+		`, userID, fileName)
+		if err != nil {
+			return fmt.Errorf("failed to create avatar reference: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit avatar reference: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Image) GetOrphans(ctx context.Context, before time.Time, limit int) ([]*entities.Image, error) {
+	query := imageSelect + `
+		WHERE i.created_at < $1
+			AND NOT EXISTS (SELECT 1 FROM image_references ref WHERE ref.image_id = i.id)
+		ORDER BY i.created_at
+		LIMIT $2
+	`
+	var images []*entities.Image
+	err := r.db.GetDB().SelectContext(ctx, &images, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orphan images: %w", err)
+	}
+
+	return images, nil
+}
+
+func (r *Image) DeleteOrphan(
+	ctx context.Context,
+	id uuid.UUID,
+	removeFiles func(fileName string) error,
+) (bool, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var fileName string
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM images i
+		WHERE i.id = $1
+			AND NOT EXISTS (SELECT 1 FROM image_references ref WHERE ref.image_id = i.id)
+		RETURNING i.file_name
+	`, id).Scan(&fileName)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to delete orphan image: %w", err)
+	}
+
+	var refCount int
+	err = tx.QueryRowContext(ctx, `
+		UPDATE image_blobs SET ref_count = GREATEST(ref_count - 1, 0)
+		WHERE file_name = $1
+		RETURNING ref_count
+	`, fileName).Scan(&refCount)
+	if err != nil {
+		return false, fmt.Errorf("failed to decrement image blob references: %w", err)
+	}
+
+	if refCount == 0 {
+		_, err = tx.ExecContext(ctx, `DELETE FROM image_blobs WHERE file_name = $1`, fileName)
+		if err != nil {
+			return false, fmt.Errorf("failed to delete image blob: %w", err)
+		}
+
+		// Файлы удаляются до фиксации: пока транзакция открыта, Create того же
+		// файла ждет на upsert строки image_blobs и не запишет файлы, которые
+		// тут же будут удалены
+		if err := removeFiles(fileName); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit orphan image deletion: %w", err)
+	}
+
+	return true, nil
+}
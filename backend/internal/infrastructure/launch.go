@@ -2,14 +2,21 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	stderrors "errors"
 	"fmt"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
 	"startup-scout/internal/repository"
 	"startup-scout/pkg/clients"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// launchRolloverLockKey ключ advisory lock смены запусков
+const launchRolloverLockKey = 7311001
+
 type Launch struct {
 	db *clients.PostgresClient
 }
@@ -44,6 +51,9 @@ func (r *Launch) GetByID(ctx context.Context, id uuid.UUID) (*entities.Launch, e
 	`
 	var launch entities.Launch
 	err := r.db.GetDB().GetContext(ctx, &launch, query, id)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrLaunchNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get launch by id: %w", err)
 	}
@@ -57,10 +67,10 @@ func (r *Launch) GetActive(ctx context.Context) (*entities.Launch, error) {
 	`
 	var launch entities.Launch
 	err := r.db.GetDB().GetContext(ctx, &launch, query)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrNoActiveLaunch
+	}
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			return nil, fmt.Errorf("no active launch found")
-		}
 		return nil, fmt.Errorf("failed to get active launch: %w", err)
 	}
 
@@ -80,6 +90,53 @@ func (r *Launch) GetAll(ctx context.Context) ([]*entities.Launch, error) {
 	return launches, nil
 }
 
+func (r *Launch) GetExpiredActive(ctx context.Context, now time.Time) ([]*entities.Launch, error) {
+	query := `
+		SELECT * FROM launches WHERE is_active = true AND end_date <= $1 ORDER BY end_date
+	`
+	var launches []*entities.Launch
+	err := r.db.GetDB().SelectContext(ctx, &launches, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired launches: %w", err)
+	}
+
+	return launches, nil
+}
+
+func (r *Launch) Deactivate(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE launches SET is_active = false, updated_at = NOW() WHERE id = $1 AND is_active = true
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to deactivate launch: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to deactivate launch: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// LockRollover берет транзакционный advisory lock. Блокировка принадлежит
+// транзакции и снимается ее откатом, поэтому работа под ней может идти через
+// любые соединения пула.
+func (r *Launch) LockRollover(ctx context.Context) (func() error, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, launchRolloverLockKey); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to lock launch rollover: %w", err)
+	}
+
+	return tx.Rollback, nil
+}
+
 func (r *Launch) Update(ctx context.Context, launch *entities.Launch) error {
 	query := `
 		UPDATE launches SET name = :name, start_date = :start_date, end_date = :end_date, is_active = :is_active, updated_at = :updated_at
@@ -107,7 +164,7 @@ func (r *Launch) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *Launch) GetProjectsByLaunchID(ctx context.Context, launchID uuid.UUID) ([]*entities.Project, error) {
 	query := `
-		SELECT * FROM projects WHERE launch_id = $1
+		SELECT ` + projectColumns + ` FROM projects WHERE launch_id = $1
 	`
 	var projects []*entities.Project
 	err := r.db.GetDB().SelectContext(ctx, &projects, query, launchID)
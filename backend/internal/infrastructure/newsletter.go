@@ -0,0 +1,172 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Newsletter struct {
+	db *clients.PostgresClient
+}
+
+func NewNewsletterRepository(db *clients.PostgresClient) repository.NewsletterRepository {
+	return &Newsletter{db: db}
+}
+
+func (r *Newsletter) Upsert(ctx context.Context, subscription *entities.NewsletterSubscription) error {
+	query := `
+		INSERT INTO newsletter_subscriptions (
+			email,
+			status,
+			confirm_token,
+			unsubscribe_token,
+			created_at,
+			updated_at
+		)
+		VALUES (:email, :status, :confirm_token, :unsubscribe_token, :created_at, :updated_at)
+		ON CONFLICT (email) DO UPDATE SET
+			status = EXCLUDED.status,
+			confirm_token = EXCLUDED.confirm_token,
+			unsubscribed_at = NULL,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, unsubscribe_token
+	`
+	rows, err := r.db.GetDB().NamedQueryContext(ctx, query, subscription)
+	if err != nil {
+		return fmt.Errorf("failed to save newsletter subscription: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&subscription.ID, &subscription.UnsubscribeToken); err != nil {
+			return fmt.Errorf("failed to get newsletter subscription id: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Newsletter) GetByEmail(ctx context.Context, email string) (*entities.NewsletterSubscription, error) {
+	query := `
+		SELECT * FROM newsletter_subscriptions WHERE email = $1
+	`
+	var subscription entities.NewsletterSubscription
+	err := r.db.GetDB().GetContext(ctx, &subscription, query, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newsletter subscription by email: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+func (r *Newsletter) GetByConfirmToken(ctx context.Context, token string) (*entities.NewsletterSubscription, error) {
+	query := `
+		SELECT * FROM newsletter_subscriptions WHERE confirm_token = $1
+	`
+	var subscription entities.NewsletterSubscription
+	err := r.db.GetDB().GetContext(ctx, &subscription, query, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newsletter subscription by confirm token: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+func (r *Newsletter) GetByUnsubscribeToken(ctx context.Context, token string) (*entities.NewsletterSubscription, error) {
+	query := `
+		SELECT * FROM newsletter_subscriptions WHERE unsubscribe_token = $1
+	`
+	var subscription entities.NewsletterSubscription
+	err := r.db.GetDB().GetContext(ctx, &subscription, query, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newsletter subscription by unsubscribe token: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+func (r *Newsletter) Confirm(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE newsletter_subscriptions
+		SET status = 'confirmed', confirm_token = NULL, confirmed_at = $2, updated_at = $2
+		WHERE id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to confirm newsletter subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Newsletter) Unsubscribe(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE newsletter_subscriptions
+		SET status = 'unsubscribed', confirm_token = NULL, unsubscribed_at = $2, updated_at = $2
+		WHERE id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe from newsletter: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Newsletter) GetUndelivered(
+	ctx context.Context,
+	digestKey string,
+	afterID uuid.UUID,
+	limit int,
+) ([]*entities.NewsletterSubscription, error) {
+	query := `
+		SELECT s.* FROM newsletter_subscriptions s
+		WHERE s.status = 'confirmed'
+			AND s.id > $3
+			AND NOT EXISTS (
+				SELECT 1 FROM newsletter_deliveries d
+				WHERE d.digest_key = $1 AND d.subscription_id = s.id
+			)
+		ORDER BY s.id
+		LIMIT $2
+	`
+	var subscriptions []*entities.NewsletterSubscription
+	err := r.db.GetDB().SelectContext(ctx, &subscriptions, query, digestKey, limit, afterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get undelivered newsletter subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *Newsletter) RecordDelivery(ctx context.Context, digestKey string, subscriptionID uuid.UUID, at time.Time) error {
+	query := `
+		INSERT INTO newsletter_deliveries (digest_key, subscription_id, sent_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (digest_key, subscription_id) DO NOTHING
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, digestKey, subscriptionID, at)
+	if err != nil {
+		return fmt.Errorf("failed to record newsletter delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Newsletter) DeleteByEmail(ctx context.Context, email string) error {
+	query := `
+		DELETE FROM newsletter_subscriptions WHERE email = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, email)
+	if err != nil {
+		return fmt.Errorf("failed to delete newsletter subscription: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/pagination"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+
+	"github.com/google/uuid"
+)
+
+type Notification struct {
+	db *clients.PostgresClient
+}
+
+func NewNotificationRepository(db *clients.PostgresClient) repository.NotificationRepository {
+	return &Notification{db: db}
+}
+
+func (r *Notification) Create(ctx context.Context, notification *entities.Notification) (bool, error) {
+	query := `
+		INSERT INTO notifications (
+			user_id,
+			type,
+			project_id,
+			actor_id,
+			comment_id,
+			launch_id,
+			milestone,
+			place,
+			dedup_key,
+			created_at
+		)
+		VALUES (:user_id, :type, :project_id, :actor_id, :comment_id, :launch_id, :milestone, :place, :dedup_key, :created_at)
+		ON CONFLICT (user_id, dedup_key) DO NOTHING
+		RETURNING id
+	`
+	rows, err := r.db.GetDB().NamedQueryContext(ctx, query, notification)
+	if err != nil {
+		return false, fmt.Errorf("failed to create notification: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	if err := rows.Scan(&notification.ID); err != nil {
+		return false, fmt.Errorf("failed to get created notification id: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *Notification) GetByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+	unreadOnly bool,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*entities.Notification, error) {
+	query := `
+		SELECT
+			n.id, n.user_id, n.type, n.project_id, n.actor_id, n.comment_id, n.launch_id,
+			n.milestone, n.place, n.dedup_key, n.read_at, n.created_at,
+			p.name AS project_name,
+			u.username AS actor_username,
+			l.name AS launch_name,
+			c.content
+		FROM notifications n
+		LEFT JOIN projects p ON p.id = n.project_id
+		LEFT JOIN users u ON u.id = n.actor_id
+		LEFT JOIN launches l ON l.id = n.launch_id
+		LEFT JOIN comments c ON c.id = n.comment_id
+		WHERE n.user_id = $1
+			AND (NOT $2 OR n.read_at IS NULL)
+			AND ($3::timestamp IS NULL OR (n.created_at, n.id) < ($3::timestamp, $4))
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT $5
+	`
+
+	var before sql.NullTime
+	afterID := uuid.Nil
+	if cursor != nil {
+		before = sql.NullTime{Time: cursor.Time, Valid: true}
+		afterID = cursor.ID
+	}
+
+	var notifications []*entities.Notification
+	err := r.db.GetDB().SelectContext(ctx, &notifications, query, userID, unreadOnly, before, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (r *Notification) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL
+	`
+	var count int
+	err := r.db.GetDB().GetContext(ctx, &count, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *Notification) MarkRead(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *Notification) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Notification) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		DELETE FROM notifications WHERE user_id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notifications: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Notification) Broadcast(ctx context.Context, notification *entities.Notification) ([]uuid.UUID, error) {
+	preferenceColumn, ok := notificationPreferenceColumns[notification.Type]
+	if !ok {
+		return nil, fmt.Errorf("notification type %q cannot be broadcast", notification.Type)
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, type, project_id, launch_id, dedup_key, created_at)
+		SELECT id, $1, $2, $3, $4, $5
+		FROM users
+		WHERE is_active = true AND deleted_at IS NULL AND ` + preferenceColumn + ` = true
+		ON CONFLICT (user_id, dedup_key) DO NOTHING
+		RETURNING user_id
+	`
+	var recipients []uuid.UUID
+	err := r.db.GetDB().SelectContext(
+		ctx,
+		&recipients,
+		query,
+		notification.Type,
+		notification.ProjectID,
+		notification.LaunchID,
+		notification.DedupKey,
+		notification.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast notification: %w", err)
+	}
+
+	return recipients, nil
+}
+
+// notificationPreferenceColumns колонки users с настройкой для каждого типа уведомлений
+var notificationPreferenceColumns = map[entities.NotificationType]string{
+	entities.NotificationComment:       "notify_comments",
+	entities.NotificationVoteMilestone: "notify_vote_milestones",
+	entities.NotificationLaunchResult:  "notify_launch_results",
+	entities.NotificationLaunchStarted: "notify_launch_started",
+}
@@ -2,14 +2,24 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	stderrors "errors"
 	"fmt"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/pagination"
 	"startup-scout/internal/repository"
 	"startup-scout/pkg/clients"
 
 	"github.com/google/uuid"
 )
 
+// projectColumns колонки projects для выборки в entities.Project (служебные
+// колонки вроде search_vector в сущность не попадают)
+const projectColumns = "id, name, tagline, description, full_description, logo, images, gallery, creators, tags, " +
+	"telegram_contact, website, links, pricing_model, platforms, stage, upvotes, rating, category_id, launch_id, " +
+	"user_id, created_at, updated_at"
+
 type Project struct {
 	db *clients.PostgresClient
 }
@@ -18,36 +28,58 @@ func NewProjectRepository(db *clients.PostgresClient) repository.ProjectReposito
 	return &Project{db: db}
 }
 
-func (r *Project) Create(ctx context.Context, project *entities.Project) error {
+func (r *Project) Create(ctx context.Context, project *entities.Project, imageFiles []string) error {
 	query := `
 		INSERT INTO projects (
 			name, 
+			tagline,
 			description, 
 			full_description, 
 			logo,
 			images, 
+			gallery,
 			creators, 
+			tags,
 			telegram_contact, 
 			website, 
+			links,
+			pricing_model,
+			platforms,
+			stage,
+			category_id,
 			launch_id,
 			user_id,
 			created_at, 
 			updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 		RETURNING id
 	`
 
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	var id uuid.UUID
-	err := r.db.GetDB().QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		project.Name,
+		project.Tagline,
 		project.Description,
 		project.FullDescription,
 		project.Logo,
 		project.Images,
+		project.Gallery,
 		project.Creators,
+		project.Tags,
 		project.TelegramContact,
 		project.Website,
+		project.Links,
+		project.PricingModel,
+		project.Platforms,
+		project.Stage,
+		project.CategoryID,
 		project.LaunchID,
 		project.UserID,
 		project.CreatedAt,
@@ -58,16 +90,35 @@ func (r *Project) Create(ctx context.Context, project *entities.Project) error {
 		return fmt.Errorf("failed to create project: %w", err)
 	}
 
+	// Автор проекта становится владельцем команды
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO project_members (project_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)
+	`, id, project.UserID, entities.RoleOwner, project.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add project owner: %w", err)
+	}
+
+	if err := replaceProjectReferences(ctx, tx, id, project.UserID, imageFiles); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project creation: %w", err)
+	}
+
 	project.ID = id
 	return nil
 }
 
 func (r *Project) GetByID(ctx context.Context, id uuid.UUID) (*entities.Project, error) {
 	query := `
-		SELECT * FROM projects WHERE id = $1
+		SELECT ` + projectColumns + ` FROM projects WHERE id = $1
 	`
 	var project entities.Project
 	err := r.db.GetDB().GetContext(ctx, &project, query, id)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrProjectNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project by id: %w", err)
 	}
@@ -77,7 +128,7 @@ func (r *Project) GetByID(ctx context.Context, id uuid.UUID) (*entities.Project,
 
 func (r *Project) GetByLaunchID(ctx context.Context, launchID uuid.UUID) ([]*entities.Project, error) {
 	query := `
-		SELECT * FROM projects WHERE launch_id = $1
+		SELECT ` + projectColumns + ` FROM projects WHERE launch_id = $1
 	`
 	var projects []*entities.Project
 	err := r.db.GetDB().SelectContext(ctx, &projects, query, launchID)
@@ -90,7 +141,7 @@ func (r *Project) GetByLaunchID(ctx context.Context, launchID uuid.UUID) ([]*ent
 
 func (r *Project) GetByLaunchIDOrderedByRating(ctx context.Context, launchID uuid.UUID) ([]*entities.Project, error) {
 	query := `
-		SELECT * FROM projects WHERE launch_id = $1 ORDER BY rating DESC
+		SELECT ` + projectColumns + ` FROM projects WHERE launch_id = $1 ORDER BY rating DESC
 	`
 	var projects []*entities.Project
 	err := r.db.GetDB().SelectContext(ctx, &projects, query, launchID)
@@ -101,26 +152,151 @@ func (r *Project) GetByLaunchIDOrderedByRating(ctx context.Context, launchID uui
 	return projects, nil
 }
 
+// projectFilterCondition условие отбора проектов по entities.ProjectFilter ($1-$6)
+const projectFilterCondition = `launch_id = $1
+	AND ($2::uuid IS NULL OR category_id = $2)
+	AND ($3 = '' OR tags @> ARRAY[$3]::text[])
+	AND ($4 = '' OR platforms @> ARRAY[$4]::text[])
+	AND ($5 = '' OR stage = $5)
+	AND ($6 = '' OR pricing_model = $6)`
+
+// projectFilterArgs аргументы projectFilterCondition
+func projectFilterArgs(filter entities.ProjectFilter) []interface{} {
+	return []interface{}{
+		filter.LaunchID, filter.CategoryID, filter.Tag, filter.Platform, string(filter.Stage), string(filter.PricingModel),
+	}
+}
+
+func (r *Project) GetByFilter(ctx context.Context, filter entities.ProjectFilter) ([]*entities.Project, error) {
+	query := `
+		SELECT ` + projectColumns + ` FROM projects
+		WHERE ` + projectFilterCondition + `
+		ORDER BY rating DESC, created_at, id
+	`
+	var projects []*entities.Project
+	err := r.db.GetDB().SelectContext(ctx, &projects, query, projectFilterArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects by filter: %w", err)
+	}
+
+	return projects, nil
+}
+
+func (r *Project) GetPageByFilter(
+	ctx context.Context,
+	filter entities.ProjectFilter,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*entities.Project, error) {
+	// Порядок (rating DESC, created_at, id): при равном рейтинге выше проект, добавленный раньше
+	query := `
+		SELECT ` + projectColumns + ` FROM projects
+		WHERE ` + projectFilterCondition + `
+			AND ($7::timestamp IS NULL OR rating < $8 OR (rating = $8 AND (created_at, id) > ($7::timestamp, $9)))
+		ORDER BY rating DESC, created_at, id
+		LIMIT $10
+	`
+
+	var after sql.NullTime
+	afterRating := 0
+	afterID := uuid.Nil
+	if cursor != nil {
+		after = sql.NullTime{Time: cursor.Time, Valid: true}
+		afterRating = cursor.Score
+		afterID = cursor.ID
+	}
+
+	var projects []*entities.Project
+	args := append(projectFilterArgs(filter), after, afterRating, afterID, limit)
+	err := r.db.GetDB().SelectContext(ctx, &projects, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects page by filter: %w", err)
+	}
+
+	return projects, nil
+}
+
+// projectUpdateQuery обновляет редактируемые поля проекта. Счетчики голосов
+// меняет только UpdateRating, чтобы правка и голос не затирали друг друга.
+const projectUpdateQuery = `
+	UPDATE projects SET 
+		name = :name, 
+		tagline = :tagline,
+		description = :description, 
+		full_description = :full_description, 
+		images = :images, 
+		gallery = :gallery,
+		creators = :creators, 
+		tags = :tags,
+		telegram_contact = :telegram_contact, 
+		website = :website, 
+		links = :links,
+		pricing_model = :pricing_model,
+		platforms = :platforms,
+		stage = :stage,
+		category_id = :category_id,
+		updated_at = :updated_at
+	WHERE id = :id
+`
+
 func (r *Project) Update(ctx context.Context, project *entities.Project) error {
+	_, err := r.db.GetDB().NamedExecContext(ctx, projectUpdateQuery, project)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Project) UpdateRating(ctx context.Context, id uuid.UUID) (int, error) {
 	query := `
-		UPDATE projects SET 
-			name = :name, 
-			description = :description, 
-			full_description = :full_description, 
-			images = :images, 
-			creators = :creators, 
-			telegram_contact = :telegram_contact, 
-			website = :website, 
-			upvotes = :upvotes,
-			rating = :rating,
-			updated_at = :updated_at
-		WHERE id = :id
+		WITH previous AS (
+			SELECT upvotes FROM projects WHERE id = $1 FOR UPDATE
+		)
+		UPDATE projects SET
+			upvotes = counted.total,
+			rating = counted.total,
+			updated_at = NOW()
+		FROM previous, (SELECT COUNT(*) AS total FROM votes WHERE project_id = $1) counted
+		WHERE projects.id = $1
+		RETURNING previous.upvotes
 	`
-	_, err := r.db.GetDB().NamedExecContext(ctx, query, project)
+	var previousUpvotes int
+	err := r.db.GetDB().GetContext(ctx, &previousUpvotes, query, id)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return 0, errors.ErrProjectNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to update project rating: %w", err)
+	}
+
+	return previousUpvotes, nil
+}
+
+func (r *Project) UpdateWithImages(
+	ctx context.Context,
+	project *entities.Project,
+	userID uuid.UUID,
+	imageFiles []string,
+) error {
+	tx, err := r.db.BeginTx(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.NamedExecContext(ctx, projectUpdateQuery, project); err != nil {
 		return fmt.Errorf("failed to update project: %w", err)
 	}
 
+	if err := replaceProjectReferences(ctx, tx, project.ID, userID, imageFiles); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project update: %w", err)
+	}
+
 	return nil
 }
 
@@ -138,7 +314,7 @@ func (r *Project) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *Project) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Project, error) {
 	query := `
-		SELECT * FROM projects WHERE user_id = $1 ORDER BY created_at DESC
+		SELECT ` + projectColumns + ` FROM projects WHERE user_id = $1 ORDER BY created_at DESC
 	`
 	var projects []*entities.Project
 	err := r.db.GetDB().SelectContext(ctx, &projects, query, userID)
@@ -148,3 +324,92 @@ func (r *Project) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entitie
 
 	return projects, nil
 }
+
+func (r *Project) GetPageByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*entities.Project, error) {
+	query := `
+		SELECT ` + projectColumns + ` FROM projects
+		WHERE (user_id = $1 OR id IN (SELECT project_id FROM project_members WHERE user_id = $1))
+			AND ($2::timestamp IS NULL OR (created_at, id) < ($2::timestamp, $3))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+
+	var before sql.NullTime
+	beforeID := uuid.Nil
+	if cursor != nil {
+		before = sql.NullTime{Time: cursor.Time, Valid: true}
+		beforeID = cursor.ID
+	}
+
+	var projects []*entities.Project
+	err := r.db.GetDB().SelectContext(ctx, &projects, query, userID, before, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects page by user id: %w", err)
+	}
+
+	return projects, nil
+}
+
+func (r *Project) GetPlacementsByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.ProjectPlacement, error) {
+	query := `
+		SELECT ranked.id, ranked.name, ranked.description, ranked.logo, ranked.upvotes, ranked.launch_id,
+			l.name AS launch_name, (l.end_date <= NOW()) AS launch_ended, ranked.placement, ranked.created_at
+		FROM (
+			SELECT p.*, RANK() OVER (PARTITION BY p.launch_id ORDER BY p.rating DESC) AS placement
+			FROM projects p
+			WHERE p.launch_id IN (SELECT launch_id FROM projects WHERE user_id = $1)
+		) ranked
+		JOIN launches l ON l.id = ranked.launch_id
+		WHERE ranked.user_id = $1
+		ORDER BY l.start_date DESC, ranked.placement
+	`
+	var placements []*entities.ProjectPlacement
+	err := r.db.GetDB().SelectContext(ctx, &placements, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project placements by user id: %w", err)
+	}
+
+	return placements, nil
+}
+
+func (r *Project) DetachFromUser(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE projects SET user_id = NULL, updated_at = NOW() WHERE user_id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to detach projects from user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Project) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := []string{
+		`DELETE FROM votes WHERE project_id IN (SELECT id FROM projects WHERE user_id = $1)`,
+		`DELETE FROM comments WHERE project_id IN (SELECT id FROM projects WHERE user_id = $1)`,
+		`DELETE FROM projects WHERE user_id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return fmt.Errorf("failed to delete projects by user id: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project deletion: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+	"time"
+)
+
+type RateLimit struct {
+	db *clients.PostgresClient
+}
+
+func NewRateLimitStore(db *clients.PostgresClient) repository.RateLimitStore {
+	return &RateLimit{db: db}
+}
+
+func (r *RateLimit) Increment(ctx context.Context, key string, window time.Duration) (*entities.RateLimitCounter, error) {
+	query := `
+		INSERT INTO rate_limits (bucket_key, count, reset_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (bucket_key) DO UPDATE SET
+			count = CASE WHEN rate_limits.reset_at <= NOW() THEN 1 ELSE rate_limits.count + 1 END,
+			reset_at = CASE WHEN rate_limits.reset_at <= NOW() THEN EXCLUDED.reset_at ELSE rate_limits.reset_at END
+		RETURNING bucket_key, count, reset_at, blocked_until
+	`
+	var counter entities.RateLimitCounter
+	err := r.db.GetDB().GetContext(ctx, &counter, query, key, time.Now().Add(window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+
+	return &counter, nil
+}
+
+func (r *RateLimit) Get(ctx context.Context, key string) (*entities.RateLimitCounter, error) {
+	query := `
+		SELECT bucket_key, count, reset_at, blocked_until FROM rate_limits WHERE bucket_key = $1
+	`
+	var counter entities.RateLimitCounter
+	err := r.db.GetDB().GetContext(ctx, &counter, query, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get rate limit counter: %w", err)
+	}
+
+	if counter.ResetAt.Before(time.Now()) {
+		counter.Count = 0
+	}
+
+	return &counter, nil
+}
+
+func (r *RateLimit) Block(ctx context.Context, key string, until time.Time) error {
+	query := `
+		INSERT INTO rate_limits (bucket_key, count, reset_at, blocked_until)
+		VALUES ($1, 0, $2, $2)
+		ON CONFLICT (bucket_key) DO UPDATE SET blocked_until = EXCLUDED.blocked_until
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, key, until)
+	if err != nil {
+		return fmt.Errorf("failed to block rate limit key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RateLimit) Reset(ctx context.Context, key string) error {
+	query := `
+		DELETE FROM rate_limits WHERE bucket_key = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, key)
+	if err != nil {
+		return fmt.Errorf("failed to reset rate limit key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RateLimit) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		DELETE FROM rate_limits
+		WHERE reset_at <= $1 AND (blocked_until IS NULL OR blocked_until <= $1)
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired rate limits: %w", err)
+	}
+
+	return result.RowsAffected()
+}
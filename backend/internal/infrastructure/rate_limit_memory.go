@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"context"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"sync"
+	"time"
+)
+
+// MemoryRateLimit хранит счетчики в памяти процесса. Подходит для одного инстанса.
+type MemoryRateLimit struct {
+	mu       sync.Mutex
+	counters map[string]*entities.RateLimitCounter
+}
+
+func NewMemoryRateLimitStore() repository.RateLimitStore {
+	return &MemoryRateLimit{
+		counters: make(map[string]*entities.RateLimitCounter),
+	}
+}
+
+func (r *MemoryRateLimit) Increment(ctx context.Context, key string, window time.Duration) (*entities.RateLimitCounter, error) {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counter, ok := r.counters[key]
+	if !ok {
+		counter = &entities.RateLimitCounter{Key: key}
+		r.counters[key] = counter
+	}
+
+	if !counter.ResetAt.After(now) {
+		counter.Count = 0
+		counter.ResetAt = now.Add(window)
+	}
+	counter.Count++
+
+	result := *counter
+	return &result, nil
+}
+
+func (r *MemoryRateLimit) Get(ctx context.Context, key string) (*entities.RateLimitCounter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counter, ok := r.counters[key]
+	if !ok {
+		return nil, nil
+	}
+
+	result := *counter
+	if result.ResetAt.Before(time.Now()) {
+		result.Count = 0
+	}
+
+	return &result, nil
+}
+
+func (r *MemoryRateLimit) Block(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counter, ok := r.counters[key]
+	if !ok {
+		counter = &entities.RateLimitCounter{Key: key, ResetAt: until}
+		r.counters[key] = counter
+	}
+	counter.BlockedUntil = &until
+
+	return nil
+}
+
+func (r *MemoryRateLimit) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	delete(r.counters, key)
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *MemoryRateLimit) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, counter := range r.counters {
+		if counter.ResetAt.After(now) || counter.IsBlocked(now) {
+			continue
+		}
+		delete(r.counters, key)
+		deleted++
+	}
+
+	return deleted, nil
+}
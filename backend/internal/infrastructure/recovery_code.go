@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+
+	"github.com/google/uuid"
+)
+
+type RecoveryCode struct {
+	db *clients.PostgresClient
+}
+
+func NewRecoveryCodeRepository(db *clients.PostgresClient) repository.RecoveryCodeRepository {
+	return &RecoveryCode{db: db}
+}
+
+func (r *RecoveryCode) ReplaceForUser(
+	ctx context.Context,
+	userID uuid.UUID,
+	codeHashes []string,
+) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, codeHash := range codeHashes {
+		query := `
+			INSERT INTO recovery_codes (user_id, code_hash, created_at)
+			VALUES ($1, $2, NOW())
+		`
+		if _, err := tx.ExecContext(ctx, query, userID, codeHash); err != nil {
+			return fmt.Errorf("failed to create recovery code: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RecoveryCode) Use(
+	ctx context.Context,
+	userID uuid.UUID,
+	codeHash string,
+) (bool, error) {
+	query := `
+		UPDATE recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *RecoveryCode) CountUnused(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL
+	`
+	var count int
+	err := r.db.GetDB().GetContext(ctx, &count, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *RecoveryCode) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		DELETE FROM recovery_codes WHERE user_id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	return nil
+}
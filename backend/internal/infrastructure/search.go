@@ -0,0 +1,115 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+)
+
+// searchQuery объединяет запрос в русской и английской конфигурациях;
+// websearch_to_tsquery понимает кавычки, OR и минус и не падает на произвольном вводе
+const searchQuery = "(websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1))"
+
+type Search struct {
+	db *clients.PostgresClient
+}
+
+func NewSearchRepository(db *clients.PostgresClient) repository.SearchRepository {
+	return &Search{db: db}
+}
+
+type projectSearchRow struct {
+	entities.Project
+	Rank float64 `db:"rank"`
+}
+
+func (r *Search) SearchProjects(ctx context.Context, filter entities.SearchFilter) ([]*entities.ProjectSearchResult, error) {
+	// Совпадение по тексту дополняется нечетким совпадением названия,
+	// чтобы находились проекты по части слова и с опечатками
+	query := `
+		SELECT ` + projectColumns + `, rank FROM (
+			SELECT p.*,
+				ts_rank_cd(p.search_vector, q.query) + word_similarity($1, p.name) AS rank
+			FROM projects p, ` + searchQuery + ` AS q(query)
+			WHERE (p.search_vector @@ q.query OR $1 <% p.name)
+				AND ($2::uuid IS NULL OR p.launch_id = $2)
+				AND ($3::timestamp IS NULL OR p.created_at >= $3)
+				AND ($4::timestamp IS NULL OR p.created_at < $4)
+		) ranked
+		ORDER BY rank DESC, created_at DESC, id DESC
+		LIMIT $5
+	`
+	var rows []*projectSearchRow
+	err := r.db.GetDB().SelectContext(ctx, &rows, query, filter.Query, filter.LaunchID, filter.From, filter.To, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search projects: %w", err)
+	}
+
+	results := make([]*entities.ProjectSearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, &entities.ProjectSearchResult{Project: &row.Project, Rank: row.Rank})
+	}
+
+	return results, nil
+}
+
+func (r *Search) SearchMakers(ctx context.Context, filter entities.SearchFilter) ([]*entities.MakerSearchResult, error) {
+	query := `
+		SELECT id, username, first_name, last_name, avatar, rank FROM (
+			SELECT u.id, u.username,
+				COALESCE(u.first_name, '') AS first_name,
+				COALESCE(u.last_name, '') AS last_name,
+				COALESCE(u.avatar, '') AS avatar,
+				GREATEST(
+					word_similarity($1, u.username),
+					word_similarity($1, COALESCE(u.first_name, '') || ' ' || COALESCE(u.last_name, ''))
+				) AS rank
+			FROM users u
+			WHERE u.is_active AND u.deleted_at IS NULL AND u.profile_public
+				AND ($1 <% u.username OR $1 <% (COALESCE(u.first_name, '') || ' ' || COALESCE(u.last_name, '')))
+				AND (($2::uuid IS NULL AND $3::timestamp IS NULL AND $4::timestamp IS NULL) OR EXISTS (
+					SELECT 1 FROM project_members m
+					JOIN projects p ON p.id = m.project_id
+					WHERE m.user_id = u.id
+						AND ($2::uuid IS NULL OR p.launch_id = $2)
+						AND ($3::timestamp IS NULL OR p.created_at >= $3)
+						AND ($4::timestamp IS NULL OR p.created_at < $4)
+				))
+		) ranked
+		ORDER BY rank DESC, username
+		LIMIT $5
+	`
+	var makers []*entities.MakerSearchResult
+	err := r.db.GetDB().SelectContext(ctx, &makers, query, filter.Query, filter.LaunchID, filter.From, filter.To, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search makers: %w", err)
+	}
+
+	return makers, nil
+}
+
+func (r *Search) SearchComments(ctx context.Context, filter entities.SearchFilter) ([]*entities.CommentSearchResult, error) {
+	query := `
+		SELECT c.id, c.project_id, p.name AS project_name, c.user_id, u.username, c.content, c.created_at,
+			ts_rank_cd(c.search_vector, q.query) AS rank
+		FROM comments c
+		JOIN projects p ON p.id = c.project_id
+		JOIN users u ON u.id = c.user_id,
+			` + searchQuery + ` AS q(query)
+		WHERE c.search_vector @@ q.query
+			AND ($2::uuid IS NULL OR p.launch_id = $2)
+			AND ($3::timestamp IS NULL OR c.created_at >= $3)
+			AND ($4::timestamp IS NULL OR c.created_at < $4)
+		ORDER BY rank DESC, c.created_at DESC, c.id DESC
+		LIMIT $5
+	`
+	var comments []*entities.CommentSearchResult
+	err := r.db.GetDB().SelectContext(ctx, &comments, query, filter.Query, filter.LaunchID, filter.From, filter.To, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search comments: %w", err)
+	}
+
+	return comments, nil
+}
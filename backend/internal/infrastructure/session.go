@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Session struct {
+	db *clients.PostgresClient
+}
+
+func NewSessionRepository(db *clients.PostgresClient) repository.SessionRepository {
+	return &Session{db: db}
+}
+
+func (r *Session) Create(ctx context.Context, sessionID, userID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		INSERT INTO sessions (id, user_id, created_at, expires_at)
+		VALUES ($1, $2, NOW(), $3)
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, sessionID, userID, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Session) IsActive(ctx context.Context, sessionID, userID uuid.UUID, now time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM sessions
+			WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > $3
+		)
+	`
+	var active bool
+	err := r.db.GetDB().GetContext(ctx, &active, query, sessionID, userID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+
+	return active, nil
+}
+
+func (r *Session) Revoke(ctx context.Context, sessionID uuid.UUID) error {
+	query := `
+		UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Session) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Session) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		DELETE FROM sessions WHERE expires_at <= $1 OR revoked_at IS NOT NULL
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
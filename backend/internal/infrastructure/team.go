@@ -0,0 +1,299 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// invitationSelect выборка приглашений вместе с названием проекта и участниками;
+// у приглашения по email invitee появляется только после принятия
+const invitationSelect = `
+	SELECT i.id, i.project_id, i.inviter_id, i.invitee_id, i.email, i.token_hash, i.role, i.status,
+		i.expires_at, i.created_at, i.responded_at,
+		p.name AS project_name, inviter.username AS inviter_username, invitee.username AS invitee_username
+	FROM project_invitations i
+	JOIN projects p ON p.id = i.project_id
+	LEFT JOIN users inviter ON inviter.id = i.inviter_id
+	LEFT JOIN users invitee ON invitee.id = i.invitee_id
+`
+
+type Team struct {
+	db *clients.PostgresClient
+}
+
+func NewTeamRepository(db *clients.PostgresClient) repository.TeamRepository {
+	return &Team{db: db}
+}
+
+func (r *Team) AddMember(ctx context.Context, member *entities.ProjectMember) error {
+	query := `
+		INSERT INTO project_members (project_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id, user_id) DO NOTHING
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, member.ProjectID, member.UserID, member.Role, member.JoinedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add project member: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Team) GetMembers(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectMember, error) {
+	query := `
+		SELECT m.project_id, m.user_id, m.role, m.created_at, u.username,
+			COALESCE(u.first_name, '') AS first_name,
+			COALESCE(u.last_name, '') AS last_name,
+			COALESCE(u.avatar, '') AS avatar,
+			u.profile_public
+		FROM project_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.project_id = $1 AND u.deleted_at IS NULL
+		ORDER BY m.role = 'owner' DESC, m.created_at, m.user_id
+	`
+	var members []*entities.ProjectMember
+	err := r.db.GetDB().SelectContext(ctx, &members, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project members: %w", err)
+	}
+
+	return members, nil
+}
+
+func (r *Team) GetRole(ctx context.Context, projectID, userID uuid.UUID) (entities.ProjectRole, error) {
+	query := `
+		SELECT role FROM project_members WHERE project_id = $1 AND user_id = $2
+	`
+	var role entities.ProjectRole
+	err := r.db.GetDB().GetContext(ctx, &role, query, projectID, userID)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get project role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *Team) RemoveMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error) {
+	query := `
+		DELETE FROM project_members WHERE project_id = $1 AND user_id = $2 AND role <> 'owner'
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, projectID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove project member: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *Team) CreateInvitation(ctx context.Context, invitation *entities.ProjectInvitation) error {
+	query := `
+		INSERT INTO project_invitations (project_id, inviter_id, invitee_id, email, token_hash, role, status, expires_at, created_at)
+		VALUES (:project_id, :inviter_id, :invitee_id, :email, :token_hash, :role, :status, :expires_at, :created_at)
+		RETURNING id
+	`
+	rows, err := r.db.GetDB().NamedQueryContext(ctx, query, invitation)
+	if err != nil {
+		var pqErr *pq.Error
+		if stderrors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return errors.ErrInvitationExists
+		}
+		return fmt.Errorf("failed to create project invitation: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&invitation.ID); err != nil {
+			return fmt.Errorf("failed to get created invitation id: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Team) GetInvitation(ctx context.Context, id uuid.UUID) (*entities.ProjectInvitation, error) {
+	return r.getInvitation(ctx, invitationSelect+`WHERE i.id = $1`, id)
+}
+
+func (r *Team) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*entities.ProjectInvitation, error) {
+	return r.getInvitation(ctx, invitationSelect+`WHERE i.token_hash = $1`, tokenHash)
+}
+
+func (r *Team) getInvitation(ctx context.Context, query string, arg interface{}) (*entities.ProjectInvitation, error) {
+	var invitation entities.ProjectInvitation
+	err := r.db.GetDB().GetContext(ctx, &invitation, query, arg)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+func (r *Team) GetPendingByProject(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectInvitation, error) {
+	query := invitationSelect + `
+		WHERE i.project_id = $1 AND i.status = 'pending'
+		ORDER BY i.created_at DESC, i.id DESC
+	`
+	var invitations []*entities.ProjectInvitation
+	err := r.db.GetDB().SelectContext(ctx, &invitations, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+func (r *Team) GetPendingByInvitee(ctx context.Context, userID uuid.UUID, now time.Time) ([]*entities.ProjectInvitation, error) {
+	query := invitationSelect + `
+		WHERE i.invitee_id = $1 AND i.status = 'pending' AND i.expires_at > $2
+		ORDER BY i.created_at DESC, i.id DESC
+	`
+	var invitations []*entities.ProjectInvitation
+	err := r.db.GetDB().SelectContext(ctx, &invitations, query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+func (r *Team) SetInvitationStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	status entities.InvitationStatus,
+	at time.Time,
+) (bool, error) {
+	query := `
+		UPDATE project_invitations SET status = $2, responded_at = $3
+		WHERE id = $1 AND status = 'pending'
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, id, status, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to update invitation status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *Team) AcceptInvitation(
+	ctx context.Context,
+	invitation *entities.ProjectInvitation,
+	userID uuid.UUID,
+	at time.Time,
+) (bool, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Приглашение по ссылке из письма закрепляется за принявшим его пользователем
+	result, err := tx.ExecContext(ctx, `
+		UPDATE project_invitations SET status = 'accepted', invitee_id = $2, responded_at = $3
+		WHERE id = $1 AND status = 'pending'
+	`, invitation.ID, userID, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO project_members (project_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id, user_id) DO NOTHING
+	`, invitation.ProjectID, userID, invitation.Role, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to add project member: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit invitation acceptance: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *Team) TransferOwnership(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		WITH successors AS (
+			SELECT DISTINCT ON (m.project_id) m.project_id, m.user_id
+			FROM project_members m
+			JOIN projects p ON p.id = m.project_id
+			JOIN users u ON u.id = m.user_id
+			WHERE p.user_id = $1 AND m.user_id <> $1 AND u.deleted_at IS NULL
+			ORDER BY m.project_id, m.created_at, m.user_id
+		), promoted AS (
+			UPDATE project_members m SET role = 'owner'
+			FROM successors s
+			WHERE m.project_id = s.project_id AND m.user_id = s.user_id
+		), demoted AS (
+			UPDATE project_members m SET role = 'maker'
+			FROM successors s
+			WHERE m.project_id = s.project_id AND m.user_id = $1
+		)
+		UPDATE projects p SET user_id = s.user_id, updated_at = NOW()
+		FROM successors s
+		WHERE p.id = s.project_id
+	`
+	if _, err := r.db.GetDB().ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to transfer project ownership: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Team) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := []string{
+		`DELETE FROM project_invitations WHERE invitee_id = $1`,
+		`DELETE FROM project_members WHERE user_id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return fmt.Errorf("failed to delete team memberships: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit team membership deletion: %w", err)
+	}
+
+	return nil
+}
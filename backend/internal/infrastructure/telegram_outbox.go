@@ -0,0 +1,107 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TelegramOutbox struct {
+	db *clients.PostgresClient
+}
+
+func NewTelegramOutboxRepository(db *clients.PostgresClient) repository.TelegramOutboxRepository {
+	return &TelegramOutbox{db: db}
+}
+
+func (r *TelegramOutbox) Enqueue(ctx context.Context, message *entities.TelegramMessage) error {
+	query := `
+		INSERT INTO telegram_outbox (user_id, chat_id, text, notification_id, status, next_attempt_at, created_at)
+		VALUES (:user_id, :chat_id, :text, :notification_id, :status, :next_attempt_at, :created_at)
+	`
+	_, err := r.db.GetDB().NamedExecContext(ctx, query, message)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue telegram message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TelegramOutbox) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]*entities.TelegramMessage, error) {
+	query := `
+		UPDATE telegram_outbox SET next_attempt_at = $2, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM telegram_outbox
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, chat_id, text, notification_id, status, attempts, next_attempt_at, last_error, created_at, sent_at
+	`
+	var messages []*entities.TelegramMessage
+	err := r.db.GetDB().SelectContext(ctx, &messages, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim telegram messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (r *TelegramOutbox) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	query := `
+		UPDATE telegram_outbox SET status = 'sent', sent_at = $2, last_error = NULL WHERE id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, id, sentAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark telegram message as sent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TelegramOutbox) Reschedule(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	query := `
+		UPDATE telegram_outbox SET next_attempt_at = $2, last_error = $3 WHERE id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, id, nextAttemptAt, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule telegram message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TelegramOutbox) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		UPDATE telegram_outbox SET status = 'failed', last_error = $2 WHERE id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, id, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to mark telegram message as failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TelegramOutbox) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		DELETE FROM telegram_outbox WHERE user_id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete telegram messages: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TwoFactorChallenge struct {
+	db *clients.PostgresClient
+}
+
+func NewTwoFactorChallengeRepository(db *clients.PostgresClient) repository.TwoFactorChallengeRepository {
+	return &TwoFactorChallenge{db: db}
+}
+
+func (r *TwoFactorChallenge) Create(ctx context.Context, challengeID, userID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		INSERT INTO two_factor_challenges (id, user_id, created_at, expires_at)
+		VALUES ($1, $2, NOW(), $3)
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, challengeID, userID, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create two-factor challenge: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TwoFactorChallenge) Exists(ctx context.Context, challengeID, userID uuid.UUID, now time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM two_factor_challenges WHERE id = $1 AND user_id = $2 AND expires_at > $3
+		)
+	`
+	var exists bool
+	err := r.db.GetDB().GetContext(ctx, &exists, query, challengeID, userID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to check two-factor challenge: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *TwoFactorChallenge) Consume(ctx context.Context, challengeID, userID uuid.UUID, now time.Time) (bool, error) {
+	query := `
+		DELETE FROM two_factor_challenges WHERE id = $1 AND user_id = $2 AND expires_at > $3
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, challengeID, userID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume two-factor challenge: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume two-factor challenge: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *TwoFactorChallenge) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		DELETE FROM two_factor_challenges WHERE expires_at <= $1
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired two-factor challenges: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -2,14 +2,32 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	stderrors "errors"
 	"fmt"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
 	"startup-scout/internal/repository"
 	"startup-scout/pkg/clients"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// publicUserColumns колонки, которые можно показывать другим пользователям
+const publicUserColumns = "id, username, COALESCE(first_name, '') AS first_name, COALESCE(last_name, '') AS last_name, " +
+	"COALESCE(avatar, '') AS avatar, telegram_username, created_at, profile_public, show_telegram, show_activity"
+
+// userColumns список колонок users для выборки в entities.User
+const userColumns = "id, username, COALESCE(email, '') AS email, COALESCE(first_name, '') AS first_name, " +
+	"COALESCE(last_name, '') AS last_name, COALESCE(password_hash, '') AS password_hash, COALESCE(avatar, '') AS avatar, " +
+	"auth_type, COALESCE(auth_id, '') AS auth_id, telegram_id, telegram_username, is_active, is_admin, email_verified, " +
+	"verification_token, totp_secret, totp_enabled, profile_public, show_telegram, show_activity, " +
+	"notify_comments, notify_vote_milestones, notify_launch_results, notify_launch_started, notify_telegram, " +
+	"deletion_scheduled_at, deleted_at, created_at, updated_at"
+
 type User struct {
 	db *clients.PostgresClient
 }
@@ -42,6 +60,9 @@ func (r *User) Create(
 	`
 	rows, err := r.db.GetDB().NamedQueryContext(ctx, query, user)
 	if err != nil {
+		if uniqueErr := mapUserUniqueViolation(err); uniqueErr != nil {
+			return uniqueErr
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 	defer rows.Close()
@@ -60,11 +81,14 @@ func (r *User) GetByID(
 	id uuid.UUID,
 ) (*entities.User, error) {
 	query := `
-		SELECT id, username, email, first_name, last_name, password_hash, avatar, auth_type, auth_id, telegram_id, is_active, email_verified, verification_token, created_at, updated_at 
+		SELECT ` + userColumns + `
 		FROM users WHERE id = $1
 	`
 	var user entities.User
 	err := r.db.GetDB().GetContext(ctx, &user, query, id)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by id: %w", err)
 	}
@@ -78,7 +102,7 @@ func (r *User) GetByAuthID(
 	authType entities.AuthType,
 ) (*entities.User, error) {
 	query := `
-		SELECT id, username, email, first_name, last_name, password_hash, avatar, auth_type, auth_id, telegram_id, is_active, email_verified, verification_token, created_at, updated_at 
+		SELECT ` + userColumns + `
 		FROM users 
 		WHERE auth_id = $1 AND auth_type = $2
 	`
@@ -110,6 +134,9 @@ func (r *User) Update(
 	`
 	_, err := r.db.GetDB().NamedExecContext(ctx, query, user)
 	if err != nil {
+		if uniqueErr := mapUserUniqueViolation(err); uniqueErr != nil {
+			return uniqueErr
+		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
@@ -121,11 +148,14 @@ func (r *User) GetByEmail(
 	email string,
 ) (*entities.User, error) {
 	query := `
-		SELECT id, username, email, first_name, last_name, password_hash, avatar, auth_type, auth_id, telegram_id, is_active, email_verified, verification_token, created_at, updated_at 
+		SELECT ` + userColumns + `
 		FROM users WHERE email = $1 AND is_active = true
 	`
 	var user entities.User
 	err := r.db.GetDB().GetContext(ctx, &user, query, email)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
@@ -138,11 +168,14 @@ func (r *User) GetByUsername(
 	username string,
 ) (*entities.User, error) {
 	query := `
-		SELECT id, username, email, first_name, last_name, password_hash, avatar, auth_type, auth_id, telegram_id, is_active, email_verified, verification_token, created_at, updated_at 
+		SELECT ` + userColumns + `
 		FROM users WHERE username = $1 AND is_active = true
 	`
 	var user entities.User
 	err := r.db.GetDB().GetContext(ctx, &user, query, username)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by username: %w", err)
 	}
@@ -155,7 +188,7 @@ func (r *User) GetByTelegramID(
 	telegramID int64,
 ) (*entities.User, error) {
 	query := `
-		SELECT id, username, email, first_name, last_name, password_hash, avatar, auth_type, auth_id, telegram_id, is_active, email_verified, verification_token, created_at, updated_at 
+		SELECT ` + userColumns + `
 		FROM users WHERE telegram_id = $1 AND is_active = true
 	`
 	var user entities.User
@@ -189,11 +222,12 @@ func (r *User) LinkTelegram(
 	ctx context.Context,
 	userID uuid.UUID,
 	telegramID int64,
+	telegramUsername string,
 ) error {
 	query := `
-		UPDATE users SET telegram_id = $1, updated_at = NOW() WHERE id = $2
+		UPDATE users SET telegram_id = $1, telegram_username = NULLIF($2, ''), updated_at = NOW() WHERE id = $3
 	`
-	_, err := r.db.GetDB().ExecContext(ctx, query, telegramID, userID)
+	_, err := r.db.GetDB().ExecContext(ctx, query, telegramID, telegramUsername, userID)
 	if err != nil {
 		return fmt.Errorf("failed to link telegram: %w", err)
 	}
@@ -201,6 +235,180 @@ func (r *User) LinkTelegram(
 	return nil
 }
 
+func (r *User) GetPublicByUsername(
+	ctx context.Context,
+	username string,
+) (*entities.PublicUser, error) {
+	query := `
+		SELECT ` + publicUserColumns + `
+		FROM users WHERE username = $1 AND is_active = true AND deleted_at IS NULL
+	`
+	var user entities.PublicUser
+	err := r.db.GetDB().GetContext(ctx, &user, query, username)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public user by username: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *User) UpdateNotificationPreferences(
+	ctx context.Context,
+	userID uuid.UUID,
+	preferences entities.NotificationPreferences,
+) error {
+	query := `
+		UPDATE users SET
+			notify_comments = $1,
+			notify_vote_milestones = $2,
+			notify_launch_results = $3,
+			notify_launch_started = $4,
+			notify_telegram = $5,
+			updated_at = NOW()
+		WHERE id = $6
+	`
+	_, err := r.db.GetDB().ExecContext(
+		ctx,
+		query,
+		preferences.Comments,
+		preferences.VoteMilestones,
+		preferences.LaunchResults,
+		preferences.LaunchStarted,
+		preferences.Telegram,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update notification preferences: %w", err)
+	}
+
+	return nil
+}
+
+func (r *User) UpdatePrivacy(
+	ctx context.Context,
+	userID uuid.UUID,
+	settings entities.PrivacySettings,
+) error {
+	query := `
+		UPDATE users SET profile_public = $1, show_telegram = $2, show_activity = $3, updated_at = NOW()
+		WHERE id = $4
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, settings.ProfilePublic, settings.ShowTelegram, settings.ShowActivity, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update privacy settings: %w", err)
+	}
+
+	return nil
+}
+
+func (r *User) UpdateTOTP(
+	ctx context.Context,
+	userID uuid.UUID,
+	secret sql.NullString,
+	enabled bool,
+) error {
+	query := `
+		UPDATE users SET totp_secret = $1, totp_enabled = $2, updated_at = NOW() WHERE id = $3
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, secret, enabled, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update totp: %w", err)
+	}
+
+	return nil
+}
+
+// UseTOTPStep атомарно сдвигает последний принятый шаг, поэтому один код
+// не пройдет проверку дважды даже при параллельных запросах
+func (r *User) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE users SET totp_last_step = $1
+		WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $1)
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, step, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to use totp step: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to use totp step: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *User) ScheduleDeletion(
+	ctx context.Context,
+	userID uuid.UUID,
+	at *time.Time,
+) error {
+	query := `
+		UPDATE users SET deletion_scheduled_at = $1, updated_at = NOW() WHERE id = $2
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, at, userID)
+	if err != nil {
+		return fmt.Errorf("failed to schedule user deletion: %w", err)
+	}
+
+	return nil
+}
+
+func (r *User) GetScheduledForDeletion(
+	ctx context.Context,
+	before time.Time,
+) ([]*entities.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1 AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_at
+	`
+	var users []*entities.User
+	err := r.db.GetDB().SelectContext(ctx, &users, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users scheduled for deletion: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *User) Anonymize(
+	ctx context.Context,
+	userID uuid.UUID,
+) error {
+	query := `
+		UPDATE users SET
+			username = 'deleted_' || REPLACE(id::text, '-', ''),
+			email = NULL,
+			first_name = '',
+			last_name = '',
+			password_hash = NULL,
+			avatar = '',
+			auth_id = NULL,
+			telegram_id = NULL,
+			telegram_username = NULL,
+			verification_token = NULL,
+			totp_secret = NULL,
+			totp_enabled = FALSE,
+			is_active = FALSE,
+			is_admin = FALSE,
+			deletion_scheduled_at = NULL,
+			deleted_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	return nil
+}
+
 func (r *User) GetTotalCount(ctx context.Context) (int, error) {
 	query := `
 		SELECT COUNT(*) FROM users WHERE is_active = true
@@ -213,3 +421,19 @@ func (r *User) GetTotalCount(ctx context.Context) (int, error) {
 
 	return count, nil
 }
+
+// mapUserUniqueViolation преобразует нарушение уникальности username/email в доменную ошибку
+func mapUserUniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if !stderrors.As(err, &pqErr) || pqErr.Code != "23505" {
+		return nil
+	}
+
+	switch {
+	case strings.Contains(pqErr.Constraint, "username"):
+		return errors.ErrUsernameExists
+	case strings.Contains(pqErr.Constraint, "email"):
+		return errors.ErrEmailExists
+	}
+	return nil
+}
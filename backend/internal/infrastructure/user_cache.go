@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/cache"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxCachedUsers ограничивает размер кеша, после которого удаляются устаревшие записи
+const maxCachedUsers = 10000
+
+// CachedUser оборачивает UserRepository и кеширует GetByID и число активных
+// пользователей на короткое время. Любая запись профиля через репозиторий
+// сбрасывает запись в кеше.
+type CachedUser struct {
+	repository.UserRepository
+
+	users      *cache.TTL[uuid.UUID, entities.User]
+	totalCount *cache.TTL[struct{}, int]
+}
+
+func NewCachedUserRepository(repo repository.UserRepository, ttl time.Duration) repository.UserRepository {
+	return &CachedUser{
+		UserRepository: repo,
+		users:          cache.NewTTL[uuid.UUID, entities.User](ttl, maxCachedUsers),
+		totalCount:     cache.NewTTL[struct{}, int](ttl, 1),
+	}
+}
+
+func (r *CachedUser) Create(ctx context.Context, user *entities.User) error {
+	defer r.totalCount.Clear()
+	return r.UserRepository.Create(ctx, user)
+}
+
+func (r *CachedUser) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	// Кеш хранит пользователя по значению, поэтому каждый вызов получает свою копию
+	user, err := r.users.GetOrLoad(id, func() (entities.User, error) {
+		user, err := r.UserRepository.GetByID(ctx, id)
+		if err != nil {
+			return entities.User{}, err
+		}
+		return *user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *CachedUser) Update(ctx context.Context, user *entities.User) error {
+	defer r.totalCount.Clear()
+	defer r.Invalidate(user.ID)
+	return r.UserRepository.Update(ctx, user)
+}
+
+func (r *CachedUser) UpdateAvatar(ctx context.Context, userID uuid.UUID, avatar string) error {
+	defer r.Invalidate(userID)
+	return r.UserRepository.UpdateAvatar(ctx, userID, avatar)
+}
+
+func (r *CachedUser) LinkTelegram(ctx context.Context, userID uuid.UUID, telegramID int64, telegramUsername string) error {
+	defer r.Invalidate(userID)
+	return r.UserRepository.LinkTelegram(ctx, userID, telegramID, telegramUsername)
+}
+
+func (r *CachedUser) UpdatePrivacy(ctx context.Context, userID uuid.UUID, settings entities.PrivacySettings) error {
+	defer r.Invalidate(userID)
+	return r.UserRepository.UpdatePrivacy(ctx, userID, settings)
+}
+
+func (r *CachedUser) UpdateNotificationPreferences(ctx context.Context, userID uuid.UUID, preferences entities.NotificationPreferences) error {
+	defer r.Invalidate(userID)
+	return r.UserRepository.UpdateNotificationPreferences(ctx, userID, preferences)
+}
+
+func (r *CachedUser) UpdateTOTP(ctx context.Context, userID uuid.UUID, secret sql.NullString, enabled bool) error {
+	defer r.Invalidate(userID)
+	return r.UserRepository.UpdateTOTP(ctx, userID, secret, enabled)
+}
+
+func (r *CachedUser) ScheduleDeletion(ctx context.Context, userID uuid.UUID, at *time.Time) error {
+	defer r.Invalidate(userID)
+	return r.UserRepository.ScheduleDeletion(ctx, userID, at)
+}
+
+func (r *CachedUser) Anonymize(ctx context.Context, userID uuid.UUID) error {
+	defer r.totalCount.Clear()
+	defer r.Invalidate(userID)
+	return r.UserRepository.Anonymize(ctx, userID)
+}
+
+func (r *CachedUser) GetTotalCount(ctx context.Context) (int, error) {
+	return r.totalCount.GetOrLoad(struct{}{}, func() (int, error) {
+		return r.UserRepository.GetTotalCount(ctx)
+	})
+}
+
+// Invalidate удаляет пользователя из кеша. Чтения, начатые до вызова, не вернут
+// в кеш прочитанную ими старую запись.
+func (r *CachedUser) Invalidate(id uuid.UUID) {
+	r.users.Delete(id)
+}
@@ -2,8 +2,11 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/pagination"
 	"startup-scout/internal/repository"
 	"startup-scout/pkg/clients"
 	"time"
@@ -35,6 +38,9 @@ func (r *Vote) Create(ctx context.Context, vote *entities.Vote) error {
 
 	_, err := r.db.GetDB().NamedExecContext(ctx, query, vote)
 	if err != nil {
+		if isProjectReferenceViolation(err) {
+			return errors.ErrProjectNotFound
+		}
 		return fmt.Errorf("failed to create vote: %w", err)
 	}
 
@@ -70,6 +76,36 @@ func (r *Vote) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.V
 	return votes, nil
 }
 
+func (r *Vote) GetPageByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*entities.Vote, error) {
+	query := `
+		SELECT * FROM votes
+		WHERE user_id = $1
+			AND ($2::timestamp IS NULL OR (created_at, id) < ($2::timestamp, $3))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+
+	var before sql.NullTime
+	beforeID := uuid.Nil
+	if cursor != nil {
+		before = sql.NullTime{Time: cursor.Time, Valid: true}
+		beforeID = cursor.ID
+	}
+
+	var votes []*entities.Vote
+	err := r.db.GetDB().SelectContext(ctx, &votes, query, userID, before, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes page by user: %w", err)
+	}
+
+	return votes, nil
+}
+
 func (r *Vote) Update(ctx context.Context, vote *entities.Vote) error {
 	query := `
 		UPDATE votes SET created_at = :created_at WHERE id = :id
@@ -110,3 +146,16 @@ func (r *Vote) GetProjectVotes(ctx context.Context, projectID uuid.UUID) (int, e
 
 	return count, nil
 }
+
+func (r *Vote) DeleteByUserID(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		DELETE FROM votes WHERE user_id = $1 RETURNING project_id
+	`
+	var projectIDs []uuid.UUID
+	err := r.db.GetDB().SelectContext(ctx, &projectIDs, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete votes by user: %w", err)
+	}
+
+	return projectIDs, nil
+}
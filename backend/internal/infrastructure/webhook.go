@@ -0,0 +1,297 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/pagination"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const webhookDeliveryColumns = "id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, " +
+	"response_status, response_body, duration_ms, last_error, created_at, delivered_at"
+
+type Webhook struct {
+	db *clients.PostgresClient
+}
+
+func NewWebhookRepository(db *clients.PostgresClient) repository.WebhookRepository {
+	return &Webhook{db: db}
+}
+
+func (r *Webhook) Create(ctx context.Context, webhook *entities.Webhook) error {
+	query := `
+		INSERT INTO webhooks (url, secret, event_types, description, is_active, created_by, created_at, updated_at)
+		VALUES (:url, :secret, :event_types, :description, :is_active, :created_by, :created_at, :updated_at)
+		RETURNING id
+	`
+	rows, err := r.db.GetDB().NamedQueryContext(ctx, query, webhook)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&webhook.ID); err != nil {
+			return fmt.Errorf("failed to get created webhook id: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Webhook) GetByID(ctx context.Context, id uuid.UUID) (*entities.Webhook, error) {
+	query := `
+		SELECT * FROM webhooks WHERE id = $1
+	`
+	var webhook entities.Webhook
+	err := r.db.GetDB().GetContext(ctx, &webhook, query, id)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+func (r *Webhook) GetAll(ctx context.Context) ([]*entities.Webhook, error) {
+	query := `
+		SELECT * FROM webhooks ORDER BY created_at DESC
+	`
+	var webhooks []*entities.Webhook
+	err := r.db.GetDB().SelectContext(ctx, &webhooks, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *Webhook) Update(ctx context.Context, webhook *entities.Webhook) error {
+	query := `
+		UPDATE webhooks SET
+			url = :url,
+			secret = :secret,
+			event_types = :event_types,
+			description = :description,
+			is_active = :is_active,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+	result, err := r.db.GetDB().NamedExecContext(ctx, query, webhook)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return webhookAffected(result)
+}
+
+func (r *Webhook) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM webhooks WHERE id = $1
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return webhookAffected(result)
+}
+
+func (r *Webhook) EnqueueEvent(ctx context.Context, event *entities.WebhookEvent, payload []byte) (int, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		SELECT id, $1, $2, $3::jsonb, 'pending', $4, $4
+		FROM webhooks
+		WHERE is_active AND $2 = ANY(event_types)
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, event.ID, string(event.Type), string(payload), event.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook event: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(affected), nil
+}
+
+func (r *Webhook) ClaimDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]*entities.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = $2, attempts = attempts + 1
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND w.is_active
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns + `
+	`
+	var deliveries []*entities.WebhookDelivery
+	err := r.db.GetDB().SelectContext(ctx, &deliveries, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *Webhook) MarkDelivered(
+	ctx context.Context,
+	id uuid.UUID,
+	attempt *entities.WebhookAttempt,
+	deliveredAt time.Time,
+) error {
+	query := `
+		UPDATE webhook_deliveries SET
+			status = 'sent',
+			response_status = $2,
+			response_body = NULLIF($3, ''),
+			duration_ms = $4,
+			last_error = NULL,
+			delivered_at = $5
+		WHERE id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, id,
+		attempt.ResponseStatus, attempt.ResponseBody, attempt.Duration.Milliseconds(), deliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery as sent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Webhook) RescheduleDelivery(
+	ctx context.Context,
+	id uuid.UUID,
+	attempt *entities.WebhookAttempt,
+	nextAttemptAt time.Time,
+) error {
+	query := `
+		UPDATE webhook_deliveries SET
+			next_attempt_at = $2,
+			response_status = NULLIF($3, 0),
+			response_body = NULLIF($4, ''),
+			duration_ms = $5,
+			last_error = NULLIF($6, '')
+		WHERE id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, id, nextAttemptAt,
+		attempt.ResponseStatus, attempt.ResponseBody, attempt.Duration.Milliseconds(), attempt.Error)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Webhook) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, attempt *entities.WebhookAttempt) error {
+	query := `
+		UPDATE webhook_deliveries SET
+			status = 'failed',
+			response_status = NULLIF($2, 0),
+			response_body = NULLIF($3, ''),
+			duration_ms = $4,
+			last_error = NULLIF($5, '')
+		WHERE id = $1
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, id,
+		attempt.ResponseStatus, attempt.ResponseBody, attempt.Duration.Milliseconds(), attempt.Error)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery as failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Webhook) RetryDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID, now time.Time) (bool, error) {
+	query := `
+		UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = $3
+		WHERE id = $1 AND webhook_id = $2
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, deliveryID, webhookID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to retry webhook delivery: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *Webhook) GetDeliveries(
+	ctx context.Context,
+	webhookID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*entities.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+			AND ($2::timestamp IS NULL OR (created_at, id) < ($2::timestamp, $3))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+
+	var before sql.NullTime
+	afterID := uuid.Nil
+	if cursor != nil {
+		before = sql.NullTime{Time: cursor.Time, Valid: true}
+		afterID = cursor.ID
+	}
+
+	var deliveries []*entities.WebhookDelivery
+	err := r.db.GetDB().SelectContext(ctx, &deliveries, query, webhookID, before, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *Webhook) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1
+	`
+	result, err := r.db.GetDB().ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// webhookAffected возвращает ErrWebhookNotFound, если запрос не затронул ни одной подписки
+func webhookAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errors.ErrWebhookNotFound
+	}
+
+	return nil
+}
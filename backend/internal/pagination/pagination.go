@@ -0,0 +1,94 @@
+// Package pagination реализует курсорную (keyset) пагинацию списков.
+// Курсор непрозрачен для клиента: это base64 от JSON с ключом сортировки
+// последнего элемента страницы и его ID для стабильного порядка при равенстве.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ErrInvalidCursor возвращается для поврежденного или чужого курсора
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor позиция в отсортированном списке. Score задает первичный ключ
+// для списков, упорядоченных по числовому значению (например, рейтингу).
+type Cursor struct {
+	Score int       `json:"s,omitempty"`
+	Time  time.Time `json:"t,omitempty"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// Encode сериализует курсор в непрозрачную строку
+func Encode(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode разбирает курсор; пустая строка означает первую страницу (nil)
+func Decode(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// ParseLimit разбирает limit из запроса, ограничивая его диапазоном [1, MaxLimit]
+func ParseLimit(value string) int {
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+// Params параметры запроса страницы
+type Params struct {
+	Cursor *Cursor
+	Limit  int
+}
+
+// Page общий конверт страницы списка. NextCursor пуст на последней странице.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPage собирает страницу из выборки, запрошенной с limit+1 элементом:
+// лишний элемент означает, что есть следующая страница, и отбрасывается,
+// а курсор строится по последнему оставшемуся элементу.
+func NewPage[T any](items []T, limit int, cursorOf func(T) Cursor) *Page[T] {
+	page := &Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = Encode(cursorOf(page.Items[len(page.Items)-1]))
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page
+}
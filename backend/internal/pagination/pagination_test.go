@@ -0,0 +1,235 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	at := time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"time and id", Cursor{Time: at, ID: uuid.New()}},
+		{"score, time and id", Cursor{Score: 42, Time: at, ID: uuid.New()}},
+		{"negative score", Cursor{Score: -3, Time: at, ID: uuid.New()}},
+		{"zero time", Cursor{ID: uuid.New()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := Decode(Encode(tt.cursor))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if decoded.Score != tt.cursor.Score || !decoded.Time.Equal(tt.cursor.Time) || decoded.ID != tt.cursor.ID {
+				t.Errorf("Decode(Encode(%+v)) = %+v", tt.cursor, *decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeEmptyIsFirstPage(t *testing.T) {
+	cursor, err := Decode("")
+	if cursor != nil || err != nil {
+		t.Errorf("Decode(\"\") = %v, %v; want nil, nil", cursor, err)
+	}
+}
+
+func TestDecodeRejectsTamperedCursor(t *testing.T) {
+	valid := Encode(Cursor{Score: 5, Time: time.Now(), ID: uuid.New()})
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"not base64", "!!!not-base64!!!"},
+		{"padding", valid + "=="},
+		{"truncated", valid[:len(valid)/2]},
+		{"not json", encode("rating=5")},
+		{"json array", encode(`[1,2,3]`)},
+		{"missing id", encode(`{"s":5,"t":"2025-01-01T00:00:00Z"}`)},
+		{"nil id", encode(`{"id":"` + uuid.Nil.String() + `"}`)},
+		{"malformed id", encode(`{"id":"not-a-uuid"}`)},
+		{"score of wrong type", encode(`{"s":"5","id":"` + uuid.NewString() + `"}`)},
+		{"malformed time", encode(`{"t":"yesterday","id":"` + uuid.NewString() + `"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := Decode(tt.value)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode(%q) = %+v, %v; want ErrInvalidCursor", tt.value, cursor, err)
+			}
+		})
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", DefaultLimit},
+		{"abc", DefaultLimit},
+		{"0", DefaultLimit},
+		{"-5", DefaultLimit},
+		{"1", 1},
+		{"50", 50},
+		{fmt.Sprint(MaxLimit), MaxLimit},
+		{fmt.Sprint(MaxLimit + 1), MaxLimit},
+	}
+
+	for _, tt := range tests {
+		if got := ParseLimit(tt.value); got != tt.want {
+			t.Errorf("ParseLimit(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNewPageUsesExtraItemForNextCursor(t *testing.T) {
+	cursorOf := func(n int) Cursor { return Cursor{Score: n, ID: uuid.New()} }
+
+	tests := []struct {
+		name      string
+		items     []int
+		limit     int
+		wantItems int
+		wantNext  bool
+		wantLast  int
+	}{
+		{"no items", nil, 3, 0, false, 0},
+		{"fewer than limit", []int{1, 2}, 3, 2, false, 0},
+		{"exactly limit", []int{1, 2, 3}, 3, 3, false, 0},
+		{"limit plus one", []int{1, 2, 3, 4}, 3, 3, true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewPage(tt.items, tt.limit, cursorOf)
+
+			if page.Items == nil {
+				t.Fatal("Items is nil, want empty slice for JSON []")
+			}
+			if len(page.Items) != tt.wantItems {
+				t.Errorf("len(Items) = %d, want %d", len(page.Items), tt.wantItems)
+			}
+			if (page.NextCursor != "") != tt.wantNext {
+				t.Fatalf("NextCursor = %q, want present = %v", page.NextCursor, tt.wantNext)
+			}
+			if tt.wantNext {
+				next, err := Decode(page.NextCursor)
+				if err != nil {
+					t.Fatalf("Decode(NextCursor): %v", err)
+				}
+				if next.Score != tt.wantLast {
+					t.Errorf("NextCursor points at %d, want last returned item %d", next.Score, tt.wantLast)
+				}
+			}
+		})
+	}
+}
+
+type rankedItem struct {
+	rating    int
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+// rankedBefore порядок rating DESC, created_at, id из GetPageByFilter
+func rankedBefore(a, b rankedItem) bool {
+	if a.rating != b.rating {
+		return a.rating > b.rating
+	}
+	if !a.createdAt.Equal(b.createdAt) {
+		return a.createdAt.Before(b.createdAt)
+	}
+	return a.id.String() < b.id.String()
+}
+
+// rankedAfter повторяет условие keyset из GetPageByFilter:
+// rating < $score OR (rating = $score AND (created_at, id) > ($time, $id))
+func rankedAfter(item rankedItem, cursor *Cursor) bool {
+	if cursor == nil {
+		return true
+	}
+	if item.rating != cursor.Score {
+		return item.rating < cursor.Score
+	}
+	if !item.createdAt.Equal(cursor.Time) {
+		return item.createdAt.After(cursor.Time)
+	}
+	return item.id.String() > cursor.ID.String()
+}
+
+func TestKeysetWalkWithTies(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Много проектов с одинаковым рейтингом и временем: порядок держится только на id
+	var items []rankedItem
+	for i := 0; i < 23; i++ {
+		items = append(items, rankedItem{
+			rating:    []int{7, 7, 7, 3, 0}[i%5],
+			createdAt: base.Add(time.Duration(i%2) * time.Minute),
+			id:        uuid.New(),
+		})
+	}
+	sorted := append([]rankedItem(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool { return rankedBefore(sorted[i], sorted[j]) })
+
+	cursorOf := func(item rankedItem) Cursor {
+		return Cursor{Score: item.rating, Time: item.createdAt, ID: item.id}
+	}
+
+	for _, limit := range []int{1, 2, 5, 22, 23, 50} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			var walked []rankedItem
+			next := ""
+			for pages := 0; ; pages++ {
+				if pages > len(items) {
+					t.Fatal("pagination does not terminate")
+				}
+
+				cursor, err := Decode(next)
+				if err != nil {
+					t.Fatalf("Decode: %v", err)
+				}
+
+				// Выборка limit+1 элемента после курсора, как в сервисах
+				var fetched []rankedItem
+				for _, item := range sorted {
+					if rankedAfter(item, cursor) && len(fetched) < limit+1 {
+						fetched = append(fetched, item)
+					}
+				}
+
+				page := NewPage(fetched, limit, cursorOf)
+				walked = append(walked, page.Items...)
+				if page.NextCursor == "" {
+					break
+				}
+				next = page.NextCursor
+			}
+
+			if len(walked) != len(sorted) {
+				t.Fatalf("walked %d items, want %d", len(walked), len(sorted))
+			}
+			for i := range sorted {
+				if walked[i].id != sorted[i].id {
+					t.Fatalf("item %d differs: pages skipped or repeated a tied item", i)
+				}
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/pagination"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -16,17 +19,151 @@ type UserRepository interface {
 	GetByTelegramID(ctx context.Context, telegramID int64) (*entities.User, error)
 	Update(ctx context.Context, user *entities.User) error
 	UpdateAvatar(ctx context.Context, userID uuid.UUID, avatar string) error
-	LinkTelegram(ctx context.Context, userID uuid.UUID, telegramID int64) error
+	LinkTelegram(ctx context.Context, userID uuid.UUID, telegramID int64, telegramUsername string) error
+	// GetPublicByUsername выбирает только публичные поля активного пользователя
+	GetPublicByUsername(ctx context.Context, username string) (*entities.PublicUser, error)
+	UpdatePrivacy(ctx context.Context, userID uuid.UUID, settings entities.PrivacySettings) error
+	UpdateNotificationPreferences(ctx context.Context, userID uuid.UUID, preferences entities.NotificationPreferences) error
+	UpdateTOTP(ctx context.Context, userID uuid.UUID, secret sql.NullString, enabled bool) error
+	// UseTOTPStep запоминает принятый шаг TOTP; false если шаг не новее последнего принятого
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// ScheduleDeletion устанавливает (или сбрасывает при nil) время окончательного удаления
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, at *time.Time) error
+	GetScheduledForDeletion(ctx context.Context, before time.Time) ([]*entities.User, error)
+	// Anonymize стирает персональные данные, оставляя запись для связанных комментариев
+	Anonymize(ctx context.Context, userID uuid.UUID) error
 	GetTotalCount(ctx context.Context) (int, error)
 }
 
+type RecoveryCodeRepository interface {
+	// ReplaceForUser удаляет старые коды пользователя и сохраняет новые хеши
+	ReplaceForUser(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	// Use помечает неиспользованный код как использованный; false если код не найден
+	Use(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	CountUnused(ctx context.Context, userID uuid.UUID) (int, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type TwoFactorChallengeRepository interface {
+	Create(ctx context.Context, challengeID, userID uuid.UUID, expiresAt time.Time) error
+	// Exists сообщает, что вызов не использован и не истек
+	Exists(ctx context.Context, challengeID, userID uuid.UUID, now time.Time) (bool, error)
+	// Consume удаляет вызов; false если он уже использован или истек
+	Consume(ctx context.Context, challengeID, userID uuid.UUID, now time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, sessionID, userID uuid.UUID, expiresAt time.Time) error
+	// IsActive сообщает, что сессия существует, не отозвана и не истекла
+	IsActive(ctx context.Context, sessionID, userID uuid.UUID, now time.Time) (bool, error)
+	Revoke(ctx context.Context, sessionID uuid.UUID) error
+	// RevokeByUserID отзывает все сессии пользователя
+	RevokeByUserID(ctx context.Context, userID uuid.UUID) error
+	// DeleteExpired удаляет истекшие и отозванные сессии
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
 type ProjectRepository interface {
-	Create(ctx context.Context, project *entities.Project) error
+	// Create сохраняет проект вместе со ссылками на изображения imageFiles
+	// из загрузок автора в одной транзакции
+	Create(ctx context.Context, project *entities.Project, imageFiles []string) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Project, error)
 	GetByLaunchID(ctx context.Context, launchID uuid.UUID) ([]*entities.Project, error)
 	GetByLaunchIDOrderedByRating(ctx context.Context, launchID uuid.UUID) ([]*entities.Project, error)
+	// GetByFilter возвращает проекты запуска с учетом категории и тега, упорядоченные по рейтингу
+	GetByFilter(ctx context.Context, filter entities.ProjectFilter) ([]*entities.Project, error)
+	// GetPageByFilter возвращает страницу GetByFilter после курсора (рейтинг, created_at, id)
+	GetPageByFilter(ctx context.Context, filter entities.ProjectFilter, cursor *pagination.Cursor, limit int) ([]*entities.Project, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Project, error)
+	// GetPageByUserID возвращает проекты пользователя, включая проекты, где он соавтор,
+	// старше курсора в порядке (created_at, id) по убыванию
+	GetPageByUserID(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*entities.Project, error)
+	// Update сохраняет редактируемые поля проекта, не трогая счетчики голосов
 	Update(ctx context.Context, project *entities.Project) error
+	// UpdateRating пересчитывает лайки и рейтинг проекта по таблице голосов
+	// и возвращает прежнее число лайков
+	UpdateRating(ctx context.Context, id uuid.UUID) (int, error)
+	// UpdateWithImages обновляет проект и заменяет его ссылки на изображения в одной
+	// транзакции. Уже прикрепленные загрузки сохраняются, новые берутся из загрузок userID.
+	UpdateWithImages(ctx context.Context, project *entities.Project, userID uuid.UUID, imageFiles []string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// GetPlacementsByUserID возвращает проекты пользователя с местом в их запусках
+	GetPlacementsByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.ProjectPlacement, error)
+	// DetachFromUser отвязывает проекты пользователя, оставляя их на витрине
+	DetachFromUser(ctx context.Context, userID uuid.UUID) error
+	// DeleteByUserID удаляет проекты пользователя вместе с их голосами и комментариями
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// ImageRepository учет загрузок изображений, файлов (blobs) и ссылок на загрузки
+type ImageRepository interface {
+	// Create сохраняет загрузку и увеличивает счетчик ссылок файла. Повторная загрузка
+	// того же файла тем же пользователем обновляет дату загрузки. Возвращает true,
+	// если файл встречается впервые и его нужно записать в хранилище.
+	// Для новой загрузки checkQuota (если задан) получает использование владельца
+	// всего и начиная с quotaSince под блокировкой владельца; ошибка отменяет загрузку.
+	Create(
+		ctx context.Context,
+		image *entities.Image,
+		quotaSince time.Time,
+		checkQuota func(usage *entities.ImageUsage) error,
+	) (bool, error)
+	GetByOwner(ctx context.Context, ownerID uuid.UUID, fileName string) (*entities.Image, error)
+	// GetAllByOwner возвращает все загрузки пользователя, в том числе неприкрепленные
+	GetAllByOwner(ctx context.Context, ownerID uuid.UUID) ([]*entities.Image, error)
+	// GetUsage возвращает количество и суммарный размер загрузок пользователя
+	// всего и сделанных начиная с since
+	GetUsage(ctx context.Context, ownerID uuid.UUID, since time.Time) (*entities.ImageUsage, error)
+	// GetUsable возвращает имена из fileNames, которые пользователь может использовать:
+	// загруженные им самим или уже прикрепленные к проекту projectID
+	GetUsable(ctx context.Context, userID, projectID uuid.UUID, fileNames []string) ([]string, error)
+	// ReplaceAvatarReference заменяет ссылку аватара пользователя; пустое имя удаляет ссылку
+	ReplaceAvatarReference(ctx context.Context, userID uuid.UUID, fileName string) error
+	// GetOrphans возвращает загрузки без ссылок, сделанные раньше before
+	GetOrphans(ctx context.Context, before time.Time, limit int) ([]*entities.Image, error)
+	// DeleteOrphan удаляет загрузку, если на нее по-прежнему никто не ссылается, и
+	// уменьшает счетчик ссылок файла. Если файл больше не используется, removeFiles
+	// удаляет его из хранилища до фиксации: строка файла остается заблокированной,
+	// и параллельная загрузка того же файла ждет, пока файлы не будут удалены.
+	// Ошибка removeFiles отменяет удаление загрузки.
+	DeleteOrphan(ctx context.Context, id uuid.UUID, removeFiles func(fileName string) error) (bool, error)
+}
+
+// TeamRepository участники команд проектов и приглашения в команды
+type TeamRepository interface {
+	// AddMember добавляет участника; повторное добавление не считается ошибкой
+	AddMember(ctx context.Context, member *entities.ProjectMember) error
+	// GetMembers возвращает команду проекта: владелец первым, затем по дате вступления
+	GetMembers(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectMember, error)
+	// GetRole возвращает роль пользователя в проекте или пустую строку, если он не участник
+	GetRole(ctx context.Context, projectID, userID uuid.UUID) (entities.ProjectRole, error)
+	// RemoveMember удаляет соавтора; false, если такого соавтора нет (владелец не удаляется)
+	RemoveMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error)
+	CreateInvitation(ctx context.Context, invitation *entities.ProjectInvitation) error
+	GetInvitation(ctx context.Context, id uuid.UUID) (*entities.ProjectInvitation, error)
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*entities.ProjectInvitation, error)
+	// GetPendingByProject возвращает ожидающие ответа приглашения проекта
+	GetPendingByProject(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectInvitation, error)
+	// GetPendingByInvitee возвращает непросроченные приглашения пользователя
+	GetPendingByInvitee(ctx context.Context, userID uuid.UUID, now time.Time) ([]*entities.ProjectInvitation, error)
+	// SetInvitationStatus закрывает ожидающее приглашение; false, если оно уже не ожидает ответа
+	SetInvitationStatus(ctx context.Context, id uuid.UUID, status entities.InvitationStatus, at time.Time) (bool, error)
+	// AcceptInvitation в одной транзакции закрывает приглашение и добавляет пользователя в команду
+	AcceptInvitation(ctx context.Context, invitation *entities.ProjectInvitation, userID uuid.UUID, at time.Time) (bool, error)
+	// TransferOwnership передает проекты пользователя самому давнему участнику их
+	// команд. Проекты без других участников остаются за пользователем.
+	TransferOwnership(ctx context.Context, userID uuid.UUID) error
+	// DeleteByUserID удаляет членство пользователя в командах и адресованные ему приглашения
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *entities.Category) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*entities.Category, error)
+	GetAll(ctx context.Context) ([]*entities.Category, error)
+	Update(ctx context.Context, category *entities.Category) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
@@ -38,22 +175,133 @@ type LaunchRepository interface {
 	Update(ctx context.Context, launch *entities.Launch) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetProjectsByLaunchID(ctx context.Context, launchID uuid.UUID) ([]*entities.Project, error)
+	// GetExpiredActive возвращает запуски, которые еще активны, но закончились к now
+	GetExpiredActive(ctx context.Context, now time.Time) ([]*entities.Launch, error)
+	// Deactivate снимает флаг активности; false если запуск уже деактивирован
+	Deactivate(ctx context.Context, id uuid.UUID) (bool, error)
+	// LockRollover ждет advisory lock смены запусков и возвращает функцию освобождения
+	LockRollover(ctx context.Context) (func() error, error)
 }
 
 type VoteRepository interface {
 	Create(ctx context.Context, vote *entities.Vote) error
 	GetByUserAndProject(ctx context.Context, userID, projectID, launchID uuid.UUID) (*entities.Vote, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Vote, error)
+	// GetPageByUserID возвращает голоса пользователя старше курсора в порядке (created_at, id) по убыванию
+	GetPageByUserID(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*entities.Vote, error)
 	Update(ctx context.Context, vote *entities.Vote) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetProjectVotes(ctx context.Context, projectID uuid.UUID) (int, error) // только количество лайков
+	// DeleteByUserID удаляет все голоса пользователя и возвращает ID затронутых проектов
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 }
 
 type CommentRepository interface {
 	Create(ctx context.Context, comment *entities.Comment) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error)
 	GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]*entities.Comment, error)
-	GetByProjectIDWithUsers(ctx context.Context, projectID uuid.UUID) ([]*entities.CommentWithUser, error)
+	// GetByProjectIDWithUsers возвращает комментарии старше курсора в порядке (created_at, id) по убыванию
+	GetByProjectIDWithUsers(ctx context.Context, projectID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*entities.CommentWithUser, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Comment, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int, error)
 	Update(ctx context.Context, comment *entities.Comment) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
+
+type FollowRepository interface {
+	// FollowUser и FollowProject идемпотентны: повторная подписка не считается ошибкой
+	FollowUser(ctx context.Context, followerID, followeeID uuid.UUID) error
+	UnfollowUser(ctx context.Context, followerID, followeeID uuid.UUID) error
+	FollowProject(ctx context.Context, userID, projectID uuid.UUID) error
+	UnfollowProject(ctx context.Context, userID, projectID uuid.UUID) error
+	GetStats(ctx context.Context, userID uuid.UUID) (*entities.FollowStats, error)
+	// GetFeed возвращает события ленты старше курсора в порядке (created_at, id) по убыванию
+	GetFeed(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*entities.FeedItem, error)
+	// DeleteByUserID удаляет все подписки пользователя и подписки на него
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type NotificationRepository interface {
+	// Create сохраняет уведомление; false, если уведомление с тем же dedup_key уже есть
+	Create(ctx context.Context, notification *entities.Notification) (bool, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, cursor *pagination.Cursor, limit int) ([]*entities.Notification, error)
+	// Broadcast создает копию уведомления для каждого активного пользователя, включившего
+	// этот тип, и возвращает ID получателей
+	Broadcast(ctx context.Context, notification *entities.Notification) ([]uuid.UUID, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	// MarkRead помечает уведомление прочитанным; false, если оно не найдено у пользователя
+	MarkRead(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// TelegramOutboxRepository очередь сообщений для доставки через Telegram-бота
+type TelegramOutboxRepository interface {
+	Enqueue(ctx context.Context, message *entities.TelegramMessage) error
+	// ClaimDue забирает готовые к отправке сообщения и откладывает их на lease,
+	// чтобы параллельные обработчики не отправили их повторно
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.TelegramMessage, error)
+	MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+	Reschedule(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type NewsletterRepository interface {
+	// Upsert создает подписку или обновляет существующую с тем же email
+	Upsert(ctx context.Context, subscription *entities.NewsletterSubscription) error
+	GetByEmail(ctx context.Context, email string) (*entities.NewsletterSubscription, error)
+	GetByConfirmToken(ctx context.Context, token string) (*entities.NewsletterSubscription, error)
+	GetByUnsubscribeToken(ctx context.Context, token string) (*entities.NewsletterSubscription, error)
+	Confirm(ctx context.Context, id uuid.UUID, at time.Time) error
+	Unsubscribe(ctx context.Context, id uuid.UUID, at time.Time) error
+	// GetUndelivered возвращает подтвержденных подписчиков с ID больше afterID,
+	// которым еще не отправлен дайджест
+	GetUndelivered(ctx context.Context, digestKey string, afterID uuid.UUID, limit int) ([]*entities.NewsletterSubscription, error)
+	RecordDelivery(ctx context.Context, digestKey string, subscriptionID uuid.UUID, at time.Time) error
+	DeleteByEmail(ctx context.Context, email string) error
+}
+
+// WebhookRepository подписки на события и журнал их доставки
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *entities.Webhook) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Webhook, error)
+	GetAll(ctx context.Context) ([]*entities.Webhook, error)
+	Update(ctx context.Context, webhook *entities.Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// EnqueueEvent создает доставки события для всех активных подписок на его тип
+	// и возвращает их количество
+	EnqueueEvent(ctx context.Context, event *entities.WebhookEvent, payload []byte) (int, error)
+	// ClaimDueDeliveries забирает готовые к отправке доставки активных подписок и
+	// откладывает их на lease, чтобы параллельные обработчики не отправили их повторно
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID, attempt *entities.WebhookAttempt, deliveredAt time.Time) error
+	RescheduleDelivery(ctx context.Context, id uuid.UUID, attempt *entities.WebhookAttempt, nextAttemptAt time.Time) error
+	MarkDeliveryFailed(ctx context.Context, id uuid.UUID, attempt *entities.WebhookAttempt) error
+	// RetryDelivery возвращает доставку в очередь; false, если она не найдена
+	RetryDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID, now time.Time) (bool, error)
+	GetDeliveries(ctx context.Context, webhookID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*entities.WebhookDelivery, error)
+	// DeleteDeliveriesBefore удаляет завершенные доставки старше before
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SearchRepository полнотекстовый и нечеткий поиск; результаты упорядочены по релевантности
+type SearchRepository interface {
+	SearchProjects(ctx context.Context, filter entities.SearchFilter) ([]*entities.ProjectSearchResult, error)
+	// SearchMakers ищет мейкеров по имени; запуск и период отбирают тех, кто
+	// состоит в команде хотя бы одного подходящего проекта
+	SearchMakers(ctx context.Context, filter entities.SearchFilter) ([]*entities.MakerSearchResult, error)
+	SearchComments(ctx context.Context, filter entities.SearchFilter) ([]*entities.CommentSearchResult, error)
+}
+
+// RateLimitStore хранит счетчики для ограничения частоты запросов и блокировок входа
+type RateLimitStore interface {
+	// Increment увеличивает счетчик ключа; если окно истекло, начинает новое окно длиной window
+	Increment(ctx context.Context, key string, window time.Duration) (*entities.RateLimitCounter, error)
+	// Get возвращает текущее состояние ключа или nil, если записи нет
+	Get(ctx context.Context, key string) (*entities.RateLimitCounter, error)
+	Block(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+	// DeleteExpired удаляет записи с истекшим окном и без активной блокировки
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
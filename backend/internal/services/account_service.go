@@ -0,0 +1,385 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// ProjectPolicyDetach оставляет проекты удаленного пользователя без владельца
+	ProjectPolicyDetach = "detach"
+	// ProjectPolicyDelete удаляет проекты вместе с аккаунтом
+	ProjectPolicyDelete = "delete"
+
+	defaultDeletionGracePeriod = 30 * 24 * time.Hour
+)
+
+type AccountConfig struct {
+	DeletionGracePeriod   time.Duration
+	DeletionProjectPolicy string
+}
+
+// AccountService отвечает за выгрузку персональных данных и удаление аккаунта
+type AccountService struct {
+	userRepo         repository.UserRepository
+	projectRepo      repository.ProjectRepository
+	voteRepo         repository.VoteRepository
+	commentRepo      repository.CommentRepository
+	followRepo       repository.FollowRepository
+	notificationRepo repository.NotificationRepository
+	telegramOutbox   repository.TelegramOutboxRepository
+	newsletterRepo   repository.NewsletterRepository
+	teamRepo         repository.TeamRepository
+	sessionRepo      repository.SessionRepository
+	projectService   *ProjectService
+	imageService     *ImageService
+	config           AccountConfig
+	logger           *zap.Logger
+}
+
+func NewAccountService(
+	userRepo repository.UserRepository,
+	projectRepo repository.ProjectRepository,
+	voteRepo repository.VoteRepository,
+	commentRepo repository.CommentRepository,
+	followRepo repository.FollowRepository,
+	notificationRepo repository.NotificationRepository,
+	telegramOutbox repository.TelegramOutboxRepository,
+	newsletterRepo repository.NewsletterRepository,
+	teamRepo repository.TeamRepository,
+	sessionRepo repository.SessionRepository,
+	projectService *ProjectService,
+	imageService *ImageService,
+	config AccountConfig,
+	logger *zap.Logger,
+) *AccountService {
+	if config.DeletionGracePeriod <= 0 {
+		config.DeletionGracePeriod = defaultDeletionGracePeriod
+	}
+	if config.DeletionProjectPolicy != ProjectPolicyDelete {
+		config.DeletionProjectPolicy = ProjectPolicyDetach
+	}
+
+	return &AccountService{
+		userRepo:         userRepo,
+		projectRepo:      projectRepo,
+		voteRepo:         voteRepo,
+		commentRepo:      commentRepo,
+		followRepo:       followRepo,
+		notificationRepo: notificationRepo,
+		telegramOutbox:   telegramOutbox,
+		newsletterRepo:   newsletterRepo,
+		teamRepo:         teamRepo,
+		sessionRepo:      sessionRepo,
+		projectService:   projectService,
+		imageService:     imageService,
+		config:           config,
+		logger:           logger,
+	}
+}
+
+// AccountExport все данные пользователя для выгрузки
+type AccountExport struct {
+	ExportedAt time.Time           `json:"exported_at"`
+	Profile    *entities.User      `json:"profile"`
+	Projects   []*entities.Project `json:"projects"`
+	Votes      []*entities.Vote    `json:"votes"`
+	Comments   []*entities.Comment `json:"comments"`
+	Images     []string            `json:"images"`
+}
+
+// Export собирает профиль, проекты, голоса, комментарии и ссылки на загруженные изображения
+func (s *AccountService) Export(ctx context.Context, userID uuid.UUID) (*AccountExport, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.projectRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := s.voteRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uploads, err := s.imageService.OwnedImageURLs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &AccountExport{
+		ExportedAt: time.Now(),
+		Profile:    user,
+		Projects:   nonNil(projects),
+		Votes:      nonNil(votes),
+		Comments:   nonNil(comments),
+		Images:     collectImages(uploads, user, projects),
+	}
+
+	return export, nil
+}
+
+// WriteExportZip пишет ZIP-архив: data.json и файлы изображений пользователя
+func (s *AccountService) WriteExportZip(ctx context.Context, export *AccountExport, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	dataFile, err := archive.Create("data.json")
+	if err != nil {
+		return fmt.Errorf("failed to create data.json: %w", err)
+	}
+
+	encoder := json.NewEncoder(dataFile)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("failed to write data.json: %w", err)
+	}
+
+	baseURL := s.imageService.GetConfig().BaseURL
+	for _, imageURL := range export.Images {
+		fileName, ok := validation.UploadFileName(imageURL, baseURL)
+		if !ok {
+			continue
+		}
+
+		if err := s.addImageToArchive(ctx, archive, fileName); err != nil {
+			// Отсутствующий файл не должен ломать всю выгрузку
+			s.logger.Warn("failed to add image to export", zap.String("file_name", fileName), zap.Error(err))
+		}
+	}
+
+	return archive.Close()
+}
+
+func (s *AccountService) addImageToArchive(ctx context.Context, archive *zip.Writer, fileName string) error {
+	src, _, err := s.imageService.OpenImage(ctx, fileName)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := archive.Create("images/" + fileName)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// RequestDeletion планирует удаление аккаунта по истечении льготного периода
+func (s *AccountService) RequestDeletion(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	deleteAt := time.Now().Add(s.config.DeletionGracePeriod)
+	if err := s.userRepo.ScheduleDeletion(ctx, userID, &deleteAt); err != nil {
+		return time.Time{}, err
+	}
+	return deleteAt, nil
+}
+
+// CancelDeletion отменяет запланированное удаление
+func (s *AccountService) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.DeletionScheduledAt == nil {
+		return errors.ErrDeletionNotScheduled
+	}
+
+	return s.userRepo.ScheduleDeletion(ctx, userID, nil)
+}
+
+// PurgeScheduledAccounts окончательно удаляет аккаунты, у которых истек льготный период.
+// Возвращает количество очищенных аккаунтов.
+func (s *AccountService) PurgeScheduledAccounts(ctx context.Context, now time.Time) (int, error) {
+	users, err := s.userRepo.GetScheduledForDeletion(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range users {
+		if err := s.purgeAccount(ctx, user); err != nil {
+			s.logger.Error("failed to purge account", zap.String("user_id", user.ID.String()), zap.Error(err))
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// purgeAccount удаляет голоса с пересчетом рейтингов, передает проекты команде или
+// применяет к ним политику, удаляет изображения и обезличивает запись пользователя
+// (комментарии остаются анонимными).
+// Шаги идемпотентны, поэтому прерванную очистку можно безопасно повторить.
+func (s *AccountService) purgeAccount(ctx context.Context, user *entities.User) error {
+	projectIDs, err := s.voteRepo.DeleteByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, projectID := range uniqueIDs(projectIDs) {
+		if err := s.projectService.RecalculateRating(ctx, projectID); err != nil {
+			return fmt.Errorf("failed to recalculate rating for project %s: %w", projectID, err)
+		}
+	}
+
+	// Проекты с командой переходят к самому давнему участнику, политика удаления
+	// касается только проектов, которые пользователь делал один
+	if err := s.teamRepo.TransferOwnership(ctx, user.ID); err != nil {
+		return err
+	}
+
+	projects, err := s.projectRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	images := []string{}
+	if user.Avatar != "" {
+		images = append(images, user.Avatar)
+	}
+
+	switch s.config.DeletionProjectPolicy {
+	case ProjectPolicyDelete:
+		for _, project := range projects {
+			images = append(images, projectImages(project)...)
+		}
+		if err := s.projectRepo.DeleteByUserID(ctx, user.ID); err != nil {
+			return err
+		}
+	default:
+		if err := s.projectRepo.DetachFromUser(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+	s.projectService.InvalidateListings()
+
+	if err := s.followRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.teamRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.notificationRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.telegramOutbox.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if user.Email != "" {
+		if err := s.newsletterRepo.DeleteByEmail(ctx, strings.ToLower(user.Email)); err != nil {
+			return err
+		}
+	}
+
+	if err := s.sessionRepo.RevokeByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Anonymize(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// Аватар больше не используется, даже если его файл не удастся удалить ниже
+	if err := s.imageService.AttachAvatar(ctx, user.ID, ""); err != nil {
+		return err
+	}
+
+	baseURL := s.imageService.GetConfig().BaseURL
+	for _, imageURL := range images {
+		fileName, ok := validation.UploadFileName(imageURL, baseURL)
+		if !ok {
+			continue
+		}
+		if err := s.imageService.DeleteImage(ctx, user.ID, fileName); err != nil {
+			s.logger.Warn("failed to delete image of purged account", zap.String("file_name", fileName), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// collectImages объединяет загрузки пользователя с аватаром и файлами его проектов:
+// файлы, загруженные до учета загрузок, есть только в ссылках
+func collectImages(uploads []string, user *entities.User, projects []*entities.Project) []string {
+	candidates := append([]string{}, uploads...)
+	if user.Avatar != "" {
+		candidates = append(candidates, user.Avatar)
+	}
+	for _, project := range projects {
+		candidates = append(candidates, projectImages(project)...)
+	}
+
+	images := make([]string, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, image := range candidates {
+		if !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+// projectImages возвращает логотип и файлы галереи проекта: изображения, ролики
+// и обложки. Встраиваемые ролики и обложки сторонних сервисов отбрасываются
+// позже при разборе URL нашего хранилища.
+func projectImages(project *entities.Project) []string {
+	images := []string{}
+	if project.Logo != nil && *project.Logo != "" {
+		images = append(images, *project.Logo)
+	}
+	for _, item := range project.Gallery {
+		if item.Type != entities.GalleryEmbed {
+			images = append(images, item.URL)
+		}
+		if item.Thumbnail != "" {
+			images = append(images, item.Thumbnail)
+		}
+	}
+	return images
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
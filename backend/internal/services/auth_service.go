@@ -5,12 +5,15 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	stderrors "errors"
 	"fmt"
 	"sort"
 	"startup-scout/internal/entities"
 	"startup-scout/internal/errors"
 	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,8 +21,10 @@ import (
 )
 
 type AuthService struct {
-	userRepo repository.UserRepository
-	config   AuthConfig
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	loginGuard  *LoginGuard
+	config      AuthConfig
 }
 
 type AuthConfig struct {
@@ -28,13 +33,71 @@ type AuthConfig struct {
 	SessionDuration  time.Duration
 }
 
-func NewAuthService(userRepo repository.UserRepository, config AuthConfig) *AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	loginGuard *LoginGuard,
+	config AuthConfig,
+) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		config:   config,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		loginGuard:  loginGuard,
+		config:      config,
 	}
 }
 
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// compareDummyPassword выполняет bcrypt-сравнение с фиктивным хешем, чтобы
+// время ответа для несуществующего пользователя не отличалось от неверного пароля
+func compareDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("startup-scout-dummy-password"), bcrypt.DefaultCost)
+	})
+	bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
+// SessionDuration возвращает время жизни сессии
+func (s *AuthService) SessionDuration() time.Duration {
+	if s.config.SessionDuration <= 0 {
+		return 24 * time.Hour
+	}
+	return s.config.SessionDuration
+}
+
+// StartSession сохраняет новую сессию пользователя и возвращает ее идентификатор
+// для claim sid и время истечения
+func (s *AuthService) StartSession(ctx context.Context, userID uuid.UUID) (uuid.UUID, time.Time, error) {
+	sessionID := uuid.New()
+	expiresAt := time.Now().Add(s.SessionDuration())
+	if err := s.sessionRepo.Create(ctx, sessionID, userID, expiresAt); err != nil {
+		return uuid.Nil, time.Time{}, err
+	}
+	return sessionID, expiresAt, nil
+}
+
+// CheckSession возвращает ErrSessionExpired, если сессия отозвана, истекла или
+// принадлежит другому пользователю
+func (s *AuthService) CheckSession(ctx context.Context, sessionID, userID uuid.UUID) error {
+	active, err := s.sessionRepo.IsActive(ctx, sessionID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !active {
+		return errors.ErrSessionExpired
+	}
+	return nil
+}
+
+// EndSession отзывает сессию при выходе
+func (s *AuthService) EndSession(ctx context.Context, sessionID uuid.UUID) error {
+	return s.sessionRepo.Revoke(ctx, sessionID)
+}
+
 type TelegramAuthData struct {
 	ID        int64  `json:"id"`
 	FirstName string `json:"first_name"`
@@ -108,17 +171,34 @@ func (s *AuthService) parseTelegramData(data map[string]string, authData *Telegr
 
 // Email авторизация
 func (s *AuthService) RegisterEmail(ctx context.Context, email, username, password string) (*entities.User, error) {
+	email = strings.TrimSpace(email)
+	username = strings.TrimSpace(username)
+
+	v := validation.New()
+	v.Email("email", email)
+	v.Username("username", username)
+	v.Password("password", password)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
 	// Проверяем, что пользователь с таким email не существует
-	existingUser, err := s.userRepo.GetByEmail(ctx, email)
-	if err == nil && existingUser != nil {
+	_, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
 		return nil, errors.ErrEmailExists
 	}
+	if !stderrors.Is(err, errors.ErrUserNotFound) {
+		return nil, err
+	}
 
 	// Проверяем, что пользователь с таким username не существует
-	existingUserByUsername, err := s.userRepo.GetByUsername(ctx, username)
-	if err == nil && existingUserByUsername != nil {
+	_, err = s.userRepo.GetByUsername(ctx, username)
+	if err == nil {
 		return nil, errors.ErrUsernameExists
 	}
+	if !stderrors.Is(err, errors.ErrUserNotFound) {
+		return nil, err
+	}
 
 	// Хешируем пароль
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -149,6 +229,8 @@ func (s *AuthService) AuthenticateEmail(ctx context.Context, login, password str
 	var user *entities.User
 	var err error
 
+	login = strings.TrimSpace(login)
+
 	// Определяем, является ли login email или username
 	if strings.Contains(login, "@") {
 		// Если содержит @, ищем по email
@@ -157,19 +239,63 @@ func (s *AuthService) AuthenticateEmail(ctx context.Context, login, password str
 		// Иначе ищем по username
 		user, err = s.userRepo.GetByUsername(ctx, login)
 	}
+	if err != nil && !stderrors.Is(err, errors.ErrUserNotFound) {
+		return nil, err
+	}
+
+	// Счетчик неудач ведем по аккаунту, а для несуществующих - по логину
+	guardKey := strings.ToLower(login)
+	if err == nil {
+		guardKey = user.ID.String()
+	}
+
+	if s.loginGuard != nil {
+		if err := s.loginGuard.Check(ctx, guardKey); err != nil {
+			return nil, err
+		}
+	}
 
 	if err != nil {
-		return nil, errors.ErrUserNotFound
+		compareDummyPassword(password)
+		return nil, s.registerLoginFailure(ctx, guardKey)
 	}
 
 	// Проверяем пароль
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, errors.ErrInvalidPassword
+		return nil, s.registerLoginFailure(ctx, guardKey)
+	}
+
+	if s.loginGuard != nil {
+		if err := s.loginGuard.RegisterSuccess(ctx, guardKey); err != nil {
+			return nil, fmt.Errorf("failed to reset login failures: %w", err)
+		}
 	}
 
 	return user, nil
 }
 
+// VerifyPassword проверяет пароль пользователя для подтверждения опасных действий.
+// Для аккаунтов без пароля (например, созданных через Telegram) проверка не требуется.
+func (s *AuthService) VerifyPassword(user *entities.User, password string) error {
+	if user.PasswordHash == "" {
+		return nil
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return errors.ErrInvalidPassword
+	}
+	return nil
+}
+
+// registerLoginFailure учитывает неудачную попытку и возвращает единообразную ошибку
+func (s *AuthService) registerLoginFailure(ctx context.Context, guardKey string) error {
+	if s.loginGuard != nil {
+		if err := s.loginGuard.RegisterFailure(ctx, guardKey); err != nil {
+			return fmt.Errorf("failed to register login failure: %w", err)
+		}
+	}
+	return errors.ErrInvalidCredentials
+}
+
 // Связывание Telegram с существующим пользователем
 func (s *AuthService) LinkTelegramToUser(ctx context.Context, userID uuid.UUID, telegramData map[string]string) error {
 	// Проверяем хеш Telegram
@@ -184,7 +310,7 @@ func (s *AuthService) LinkTelegramToUser(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Связываем Telegram ID с пользователем
-	if err := s.userRepo.LinkTelegram(ctx, userID, authData.ID); err != nil {
+	if err := s.userRepo.LinkTelegram(ctx, userID, authData.ID, authData.Username); err != nil {
 		return fmt.Errorf("failed to link telegram: %w", err)
 	}
 
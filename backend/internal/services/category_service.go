@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategoryService управляет категориями проектов и рейтингами внутри категорий
+type CategoryService struct {
+	categoryRepo   repository.CategoryRepository
+	projectRepo    repository.ProjectRepository
+	launchService  *LaunchService
+	projectService *ProjectService
+}
+
+func NewCategoryService(
+	categoryRepo repository.CategoryRepository,
+	projectRepo repository.ProjectRepository,
+	launchService *LaunchService,
+	projectService *ProjectService,
+) *CategoryService {
+	return &CategoryService{
+		categoryRepo:   categoryRepo,
+		projectRepo:    projectRepo,
+		launchService:  launchService,
+		projectService: projectService,
+	}
+}
+
+func (s *CategoryService) GetAll(ctx context.Context) ([]*entities.Category, error) {
+	categories, err := s.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if categories == nil {
+		return []*entities.Category{}, nil
+	}
+
+	return categories, nil
+}
+
+func (s *CategoryService) GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
+	return s.categoryRepo.GetByID(ctx, id)
+}
+
+func (s *CategoryService) Create(ctx context.Context, category *entities.Category) error {
+	if err := validateCategory(category); err != nil {
+		return err
+	}
+
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = category.CreatedAt
+
+	return s.categoryRepo.Create(ctx, category)
+}
+
+func (s *CategoryService) Update(ctx context.Context, category *entities.Category) error {
+	if err := validateCategory(category); err != nil {
+		return err
+	}
+
+	category.UpdatedAt = time.Now()
+
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
+		return err
+	}
+
+	// Закешированные списки фильтруются по адресу категории, который мог измениться
+	s.projectService.InvalidateListings()
+	return nil
+}
+
+// Delete удаляет категорию; ее проекты остаются без категории
+func (s *CategoryService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.categoryRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// У проектов категории обнулился category_id, закешированные списки устарели
+	s.projectService.InvalidateListings()
+	return nil
+}
+
+// Leaderboard возвращает рейтинг проектов категории в запуске (текущем, если launchID
+// в фильтре не задан). Места считаются только среди проектов категории с учетом
+// остальных фильтров, проекты с равным рейтингом делят место.
+func (s *CategoryService) Leaderboard(ctx context.Context, slug string, listFilter ProjectListFilter) (*entities.CategoryLeaderboard, error) {
+	category, err := s.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	launch, err := s.launchService.GetByIDOrActive(ctx, listFilter.LaunchID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := listFilter.projectFilter(launch.ID, &category.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.projectRepo.GetByFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	places := ratingPlaces(projects)
+	entries := make([]entities.LeaderboardEntry, len(projects))
+	for i, project := range projects {
+		entries[i] = entities.LeaderboardEntry{Place: places[i], Project: project}
+	}
+
+	return &entities.CategoryLeaderboard{
+		Category: category,
+		Launch:   launch,
+		Projects: entries,
+	}, nil
+}
+
+// validateCategory нормализует и проверяет поля категории перед сохранением
+func validateCategory(category *entities.Category) error {
+	category.Slug = strings.ToLower(strings.TrimSpace(category.Slug))
+	category.Name = strings.TrimSpace(category.Name)
+	category.Description = strings.TrimSpace(category.Description)
+
+	v := validation.New()
+	v.Slug("slug", category.Slug, validation.CategorySlugMaxLength)
+	if v.Required("name", category.Name) {
+		v.MaxLength("name", category.Name, validation.CategoryNameMaxLength)
+	}
+	v.MaxLength("description", category.Description, validation.DescriptionMaxLength)
+
+	return v.Err()
+}
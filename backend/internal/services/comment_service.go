@@ -4,23 +4,38 @@ import (
 	"context"
 	"fmt"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/pagination"
 	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type CommentService struct {
-	commentRepo repository.CommentRepository
+	commentRepo   repository.CommentRepository
+	notifications *NotificationService
+	webhooks      *WebhookService
 }
 
-func NewCommentService(commentRepo repository.CommentRepository) *CommentService {
+func NewCommentService(
+	commentRepo repository.CommentRepository,
+	notifications *NotificationService,
+	webhooks *WebhookService,
+) *CommentService {
 	return &CommentService{
-		commentRepo: commentRepo,
+		commentRepo:   commentRepo,
+		notifications: notifications,
+		webhooks:      webhooks,
 	}
 }
 
 func (s *CommentService) CreateComment(ctx context.Context, userID, projectID uuid.UUID, content string) (*entities.Comment, error) {
+	if err := validateCommentContent(content); err != nil {
+		return nil, err
+	}
+
 	comment := &entities.Comment{
 		UserID:    userID,
 		ProjectID: projectID,
@@ -33,6 +48,9 @@ func (s *CommentService) CreateComment(ctx context.Context, userID, projectID uu
 		return nil, fmt.Errorf("failed to create comment: %w", err)
 	}
 
+	s.notifications.NotifyComment(ctx, comment)
+	s.webhooks.EmitCommentCreated(ctx, comment)
+
 	return comment, nil
 }
 
@@ -40,11 +58,30 @@ func (s *CommentService) GetProjectComments(ctx context.Context, projectID uuid.
 	return s.commentRepo.GetByProjectID(ctx, projectID)
 }
 
-func (s *CommentService) GetProjectCommentsWithUsers(ctx context.Context, projectID uuid.UUID) ([]*entities.CommentWithUser, error) {
-	return s.commentRepo.GetByProjectIDWithUsers(ctx, projectID)
+// CommentPage страница комментариев проекта
+type CommentPage = pagination.Page[*entities.CommentWithUser]
+
+// GetProjectCommentsWithUsers возвращает страницу комментариев проекта, начиная с последних
+func (s *CommentService) GetProjectCommentsWithUsers(
+	ctx context.Context,
+	projectID uuid.UUID,
+	params pagination.Params,
+) (*CommentPage, error) {
+	comments, err := s.commentRepo.GetByProjectIDWithUsers(ctx, projectID, params.Cursor, params.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return pagination.NewPage(comments, params.Limit, func(comment *entities.CommentWithUser) pagination.Cursor {
+		return pagination.Cursor{Time: comment.CreatedAt, ID: comment.ID}
+	}), nil
 }
 
 func (s *CommentService) UpdateComment(ctx context.Context, commentID, userID uuid.UUID, content string) error {
+	if err := validateCommentContent(content); err != nil {
+		return err
+	}
+
 	// Получаем комментарий для проверки принадлежности
 	comment, err := s.commentRepo.GetByID(ctx, commentID)
 	if err != nil {
@@ -53,7 +90,7 @@ func (s *CommentService) UpdateComment(ctx context.Context, commentID, userID uu
 
 	// Проверяем, что комментарий принадлежит пользователю
 	if comment.UserID != userID {
-		return fmt.Errorf("comment does not belong to user")
+		return errors.ErrNotCommentAuthor
 	}
 
 	// Обновляем содержимое и время
@@ -72,8 +109,16 @@ func (s *CommentService) DeleteComment(ctx context.Context, commentID, userID uu
 
 	// Проверяем, что комментарий принадлежит пользователю
 	if comment.UserID != userID {
-		return fmt.Errorf("comment does not belong to user")
+		return errors.ErrNotCommentAuthor
 	}
 
 	return s.commentRepo.Delete(ctx, commentID)
 }
+
+func validateCommentContent(content string) error {
+	v := validation.New()
+	if v.Required("content", content) {
+		v.MaxLength("content", content, validation.CommentMaxLength)
+	}
+	return v.Err()
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/pagination"
+	"startup-scout/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type FollowService struct {
+	followRepo  repository.FollowRepository
+	userRepo    repository.UserRepository
+	projectRepo repository.ProjectRepository
+}
+
+func NewFollowService(
+	followRepo repository.FollowRepository,
+	userRepo repository.UserRepository,
+	projectRepo repository.ProjectRepository,
+) *FollowService {
+	return &FollowService{
+		followRepo:  followRepo,
+		userRepo:    userRepo,
+		projectRepo: projectRepo,
+	}
+}
+
+// FeedPage страница персональной ленты
+type FeedPage = pagination.Page[*entities.FeedItem]
+
+// FollowUser подписывает пользователя на мейкера. Скрытые и удаленные профили недоступны.
+func (s *FollowService) FollowUser(ctx context.Context, followerID uuid.UUID, username string) error {
+	followee, err := s.userRepo.GetPublicByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !followee.ProfilePublic {
+		return errors.ErrUserNotFound
+	}
+
+	if followee.ID == followerID {
+		return errors.ErrCannotFollowSelf
+	}
+
+	return s.followRepo.FollowUser(ctx, followerID, followee.ID)
+}
+
+func (s *FollowService) UnfollowUser(ctx context.Context, followerID uuid.UUID, username string) error {
+	followee, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return s.followRepo.UnfollowUser(ctx, followerID, followee.ID)
+}
+
+func (s *FollowService) FollowProject(ctx context.Context, userID, projectID uuid.UUID) error {
+	if _, err := s.projectRepo.GetByID(ctx, projectID); err != nil {
+		return err
+	}
+
+	return s.followRepo.FollowProject(ctx, userID, projectID)
+}
+
+func (s *FollowService) UnfollowProject(ctx context.Context, userID, projectID uuid.UUID) error {
+	return s.followRepo.UnfollowProject(ctx, userID, projectID)
+}
+
+func (s *FollowService) GetStats(ctx context.Context, userID uuid.UUID) (*entities.FollowStats, error) {
+	return s.followRepo.GetStats(ctx, userID)
+}
+
+// GetFeed возвращает страницу ленты; запрашиваем на один элемент больше,
+// чтобы понять, есть ли следующая страница
+func (s *FollowService) GetFeed(ctx context.Context, userID uuid.UUID, params pagination.Params) (*FeedPage, error) {
+	items, err := s.followRepo.GetFeed(ctx, userID, params.Cursor, params.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return pagination.NewPage(items, params.Limit, func(item *entities.FeedItem) pagination.Cursor {
+		return pagination.Cursor{Time: item.CreatedAt, ID: item.ID}
+	}), nil
+}
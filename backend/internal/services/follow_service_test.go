@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"startup-scout/internal/entities"
+	"startup-scout/internal/pagination"
+	"startup-scout/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// memoryFeed отдает ленту с тем же порядком и условием keyset, что и Follow.GetFeed:
+// (created_at, id) < курсора, ORDER BY created_at DESC, id DESC
+type memoryFeed struct {
+	repository.FollowRepository
+
+	items  []*entities.FeedItem
+	limits []int
+}
+
+func (r *memoryFeed) GetFeed(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*entities.FeedItem, error) {
+	r.limits = append(r.limits, limit)
+
+	var page []*entities.FeedItem
+	for _, item := range r.items {
+		if len(page) == limit {
+			break
+		}
+		if cursor == nil || feedItemBefore(item, cursor.Time, cursor.ID) {
+			page = append(page, item)
+		}
+	}
+	return page, nil
+}
+
+// feedItemBefore сообщает, что item стоит в ленте после позиции (at, id)
+func feedItemBefore(item *entities.FeedItem, at time.Time, id uuid.UUID) bool {
+	if !item.CreatedAt.Equal(at) {
+		return item.CreatedAt.Before(at)
+	}
+	return item.ID.String() < id.String()
+}
+
+func TestGetFeedWalksTiedItemsOnce(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Записи созданы в одну и ту же секунду группами по четыре
+	var items []*entities.FeedItem
+	for i := 0; i < 10; i++ {
+		items = append(items, &entities.FeedItem{ID: uuid.New(), CreatedAt: base.Add(time.Duration(i/4) * time.Second)})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return feedItemBefore(items[j], items[i].CreatedAt, items[i].ID)
+	})
+
+	repo := &memoryFeed{items: items}
+	service := NewFollowService(repo, nil, nil)
+
+	const limit = 3
+	var walked []*entities.FeedItem
+	next := ""
+	for {
+		cursor, err := pagination.Decode(next)
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+
+		page, err := service.GetFeed(context.Background(), uuid.New(), pagination.Params{Cursor: cursor, Limit: limit})
+		if err != nil {
+			t.Fatalf("GetFeed: %v", err)
+		}
+		if len(page.Items) > limit {
+			t.Fatalf("page has %d items, want at most %d", len(page.Items), limit)
+		}
+
+		walked = append(walked, page.Items...)
+		if page.NextCursor == "" {
+			break
+		}
+		next = page.NextCursor
+	}
+
+	for _, requested := range repo.limits {
+		if requested != limit+1 {
+			t.Errorf("repository asked for %d items, want limit+1 = %d", requested, limit+1)
+		}
+	}
+	if len(walked) != len(items) {
+		t.Fatalf("walked %d items, want %d", len(walked), len(items))
+	}
+	for i := range items {
+		if walked[i].ID != items[i].ID {
+			t.Fatalf("item %d differs: pages skipped or repeated a tied item", i)
+		}
+	}
+}
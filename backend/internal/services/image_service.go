@@ -1,22 +1,38 @@
 package services
 
 import (
-	"crypto/md5"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
 	"fmt"
+	"image"
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"startup-scout/config"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
+	"startup-scout/pkg/imaging"
+	"startup-scout/pkg/storage"
+	"startup-scout/pkg/video"
 
 	"github.com/google/uuid"
 )
 
 type ImageService struct {
-	config *config.StorageConfig
+	config    *config.StorageConfig
+	storage   storage.Storage
+	imageRepo repository.ImageRepository
 }
 
 // GetConfig возвращает конфигурацию хранилища (для доступа из handlers)
@@ -24,102 +40,571 @@ func (s *ImageService) GetConfig() *config.StorageConfig {
 	return s.config
 }
 
-func NewImageService(cfg *config.StorageConfig) *ImageService {
-	// Создаем директорию для загрузок если её нет
-	if cfg.Type == "local" {
-		if err := os.MkdirAll(cfg.LocalPath, 0755); err != nil {
-			panic(fmt.Sprintf("Failed to create uploads directory: %v", err))
+func NewImageService(cfg *config.StorageConfig, store storage.Storage, imageRepo repository.ImageRepository) *ImageService {
+	return &ImageService{
+		config:    cfg,
+		storage:   store,
+		imageRepo: imageRepo,
+	}
+}
+
+// UploadImage загружает изображение пользователя ownerID и возвращает имя файла.
+// Изображение декодируется и перекодируется без метаданных, рядом с оригиналом
+// сохраняются уменьшенные варианты из StorageConfig.ImageSizes.
+func (s *ImageService) UploadImage(ctx context.Context, ownerID uuid.UUID, file *multipart.FileHeader) (string, error) {
+	data, err := readUpload(file, s.config.MaxFileSize)
+	if err != nil {
+		return "", err
+	}
+
+	// Определяем MIME тип
+	mimeType := http.DetectContentType(data)
+	if !slices.Contains(s.config.AllowedTypes, mimeType) {
+		return "", fmt.Errorf("%w: file type %s is not allowed", errors.ErrInvalidImage, mimeType)
+	}
+
+	// Размеры проверяются по заголовку до декодирования пикселей
+	if err := imaging.CheckDimensions(data, s.config.MaxImageSide, s.config.MaxImagePixels); err != nil {
+		if stderrors.Is(err, imaging.ErrTooLarge) {
+			return "", fmt.Errorf("%w: %v", errors.ErrImageDimensionsTooLarge, err)
 		}
+		return "", fmt.Errorf("%w: %v", errors.ErrInvalidImage, err)
 	}
 
-	return &ImageService{
-		config: cfg,
+	// Файл декодируется целиком и сохраняется только перекодированным, поэтому
+	// данные, приклеенные к картинке (polyglot), в хранилище не попадают
+	original, img, format, err := s.process(data, mimeType)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errors.ErrInvalidImage, err)
 	}
+
+	return s.store(ctx, ownerID, original, mimeType, func(fileName string) ([]imageObject, error) {
+		if img == nil {
+			return nil, nil
+		}
+		variants := make([]imageObject, 0, len(s.config.ImageSizes))
+		for _, size := range s.config.ImageSizes {
+			variant, err := renderVariant(img, format, size)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render image variant: %w", err)
+			}
+			variants = append(variants, imageObject{key: variantKey(fileName, size.Name), data: variant})
+		}
+		return variants, nil
+	})
 }
 
-// UploadImage загружает изображение и возвращает URL
-func (s *ImageService) UploadImage(file *multipart.FileHeader) (string, error) {
+// VideoUpload загруженный ролик
+type VideoUpload struct {
+	FileName string
+	Duration time.Duration
+}
+
+// UploadVideo загружает ролик mp4/webm пользователя ownerID для галереи проекта.
+// Ролик не перекодируется: проверяются структура контейнера и длительность, а
+// отдается он только с типом по расширению.
+func (s *ImageService) UploadVideo(ctx context.Context, ownerID uuid.UUID, file *multipart.FileHeader) (*VideoUpload, error) {
+	data, err := readUpload(file, s.config.MaxVideoSize)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !slices.Contains(s.config.AllowedVideoTypes, mimeType) {
+		return nil, fmt.Errorf("%w: file type %s is not allowed", errors.ErrInvalidVideo, mimeType)
+	}
+
+	info, err := video.Probe(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidVideo, err)
+	}
+	if "video/"+info.Format != mimeType {
+		return nil, fmt.Errorf("%w: %s container does not match content type %s", errors.ErrInvalidVideo, info.Format, mimeType)
+	}
+	if s.config.MaxVideoDuration > 0 && info.Duration > s.config.MaxVideoDuration {
+		return nil, fmt.Errorf("%w: %s, max %s", errors.ErrVideoTooLong, info.Duration, s.config.MaxVideoDuration)
+	}
+
+	fileName, err := s.store(ctx, ownerID, data, mimeType, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VideoUpload{FileName: fileName, Duration: info.Duration}, nil
+}
+
+// readUpload читает загруженный файл, если он не больше maxSize
+func readUpload(file *multipart.FileHeader, maxSize int64) ([]byte, error) {
 	// Проверяем размер файла
-	if file.Size > s.config.MaxFileSize {
-		return "", fmt.Errorf("file size exceeds maximum allowed size of %d bytes", s.config.MaxFileSize)
+	if file.Size > maxSize {
+		return nil, fmt.Errorf("%w of %d bytes", errors.ErrFileTooLarge, maxSize)
 	}
 
 	// Открываем файл
 	src, err := file.Open()
 	if err != nil {
-		return "", fmt.Errorf("failed to open uploaded file: %v", err)
+		return nil, fmt.Errorf("failed to open uploaded file: %v", err)
 	}
 	defer src.Close()
 
-	// Читаем первые 512 байт для определения MIME типа
-	buffer := make([]byte, 512)
-	_, err = src.Read(buffer)
-	if err != nil && err != io.EOF {
-		return "", fmt.Errorf("failed to read file header: %v", err)
+	data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %v", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w of %d bytes", errors.ErrFileTooLarge, maxSize)
+	}
+
+	return data, nil
+}
+
+// store сохраняет проверенный файл под именем по хешу содержимого и учитывает
+// загрузку пользователя. variants строит дополнительные файлы (уменьшенные
+// копии) для нового файла и может быть nil.
+func (s *ImageService) store(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	data []byte,
+	mimeType string,
+	variants func(fileName string) ([]imageObject, error),
+) (string, error) {
+	// Имя файла - хеш содержимого, поэтому одинаковые файлы хранятся один раз
+	hash := sha256.Sum256(data)
+	fileName := hex.EncodeToString(hash[:]) + s.getExtensionFromMimeType(mimeType)
+
+	// Загрузка учитывается до записи файла: счетчик ссылок не даст удалить общий файл,
+	// а незаписанный файл без ссылок удалит очистка брошенных загрузок.
+	// Квота проверяется в той же транзакции; повторная загрузка своего же файла ее не расходует.
+	size := int64(len(data))
+	now := time.Now()
+	created, err := s.imageRepo.Create(ctx, &entities.Image{
+		FileName:  fileName,
+		OwnerID:   &ownerID,
+		Size:      size,
+		MimeType:  mimeType,
+		Hash:      hex.EncodeToString(hash[:]),
+		CreatedAt: now,
+	}, now.Add(-uploadQuotaWindow), func(usage *entities.ImageUsage) error {
+		return s.checkUploadQuota(usage, size)
+	})
+	if err != nil {
+		return "", err
 	}
 
-	// Определяем MIME тип
-	mimeType := http.DetectContentType(buffer)
-	if !s.isAllowedMimeType(mimeType) {
-		return "", fmt.Errorf("file type %s is not allowed", mimeType)
+	// Файл уже хранится; недостающие варианты создаются при первом запросе
+	if !created {
+		_, err := s.storage.Stat(ctx, fileName)
+		if err == nil {
+			return fileName, nil
+		}
+		if !stderrors.Is(err, storage.ErrNotFound) {
+			return "", err
+		}
+	}
+
+	objects := []imageObject{{key: fileName, data: data}}
+	if variants != nil {
+		extra, err := variants(fileName)
+		if err != nil {
+			return "", err
+		}
+		objects = append(objects, extra...)
+	}
+
+	for _, object := range objects {
+		if err := s.put(ctx, object); err != nil {
+			return "", err
+		}
+	}
+
+	return fileName, nil
+}
+
+// uploadQuotaWindow окно суточной квоты загрузок
+const uploadQuotaWindow = 24 * time.Hour
+
+// GetUploadQuota возвращает квоты загрузок пользователя и их остаток
+func (s *ImageService) GetUploadQuota(ctx context.Context, ownerID uuid.UUID) (*entities.UploadQuota, error) {
+	usage, err := s.imageRepo.GetUsage(ctx, ownerID, time.Now().Add(-uploadQuotaWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	quota := &entities.UploadQuota{
+		DailyFiles: quotaUsage(int64(s.config.UploadDailyFiles), usage.RecentFiles),
+		DailyBytes: quotaUsage(s.config.UploadDailyBytes, usage.RecentBytes),
+		TotalFiles: quotaUsage(int64(s.config.UploadTotalFiles), usage.TotalFiles),
+		TotalBytes: quotaUsage(s.config.UploadTotalBytes, usage.TotalBytes),
+	}
+	if usage.OldestRecent != nil {
+		resetAt := usage.OldestRecent.Add(uploadQuotaWindow)
+		quota.DailyResetAt = &resetAt
+	}
+
+	return quota, nil
+}
+
+// checkUploadQuota проверяет, что новая загрузка размером size не превысит квоты
+// пользователя с использованием usage. Общая квота проверяется первой: ожидание ее не освободит.
+func (s *ImageService) checkUploadQuota(usage *entities.ImageUsage, size int64) error {
+	if exceedsQuota(int64(s.config.UploadTotalFiles), usage.TotalFiles, 1) ||
+		exceedsQuota(s.config.UploadTotalBytes, usage.TotalBytes, size) {
+		return errors.ErrUploadQuotaExceeded
+	}
+	if exceedsQuota(int64(s.config.UploadDailyFiles), usage.RecentFiles, 1) ||
+		exceedsQuota(s.config.UploadDailyBytes, usage.RecentBytes, size) {
+		return errors.ErrDailyUploadQuotaExceeded
+	}
+
+	return nil
+}
+
+// exceedsQuota сообщает, превысит ли добавление add лимит limit; 0 - без ограничения
+func exceedsQuota(limit, used, add int64) bool {
+	return limit > 0 && used+add > limit
+}
+
+// quotaUsage описывает использование лимита; для limit 0 лимит и остаток не указываются
+func quotaUsage(limit, used int64) entities.QuotaUsage {
+	usage := entities.QuotaUsage{Used: used}
+	if limit > 0 {
+		remaining := max(limit-used, 0)
+		usage.Limit = &limit
+		usage.Remaining = &remaining
+	}
+	return usage
+}
+
+// imageObject файл, подготовленный к сохранению в хранилище
+type imageObject struct {
+	key  string
+	data []byte
+}
+
+func (s *ImageService) put(ctx context.Context, object imageObject) error {
+	return s.storage.Put(ctx, object.key, bytes.NewReader(object.data), int64(len(object.data)), UploadContentType(object.key))
+}
+
+// imageMimeTypes MIME типы форматов, которые декодирует imaging
+var imageMimeTypes = map[string]string{
+	imaging.FormatJPEG: "image/jpeg",
+	imaging.FormatPNG:  "image/png",
+	imaging.FormatGIF:  "image/gif",
+	imaging.FormatWebP: "image/webp",
+}
+
+// uploadContentTypes MIME типы по расширениям сохраняемых файлов
+var uploadContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+}
+
+// UploadContentType возвращает MIME тип загруженного файла по расширению. Тип не
+// берется из хранилища и не определяется по содержимому; для неизвестных
+// расширений возвращается application/octet-stream.
+func UploadContentType(fileName string) string {
+	if contentType, ok := uploadContentTypes[filepath.Ext(fileName)]; ok {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// process перекодирует оригинал без метаданных и возвращает его вместе с
+// декодированным изображением для вариантов. WebP декодируется целиком для
+// проверки, но кодировщика WebP нет: оригинал пересобирается из чанков
+// изображения без метаданных, а варианты сохраняются в PNG.
+func (s *ImageService) process(data []byte, mimeType string) ([]byte, image.Image, string, error) {
+	img, format, err := imaging.Decode(data)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if imageMimeTypes[format] != mimeType {
+		return nil, nil, "", fmt.Errorf("decoded %s does not match content type %s", format, mimeType)
+	}
+
+	if format == imaging.FormatWebP {
+		original, err := imaging.StripWebPMetadata(data)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return original, img, format, nil
 	}
 
-	// Возвращаемся к началу файла
-	if _, err := src.Seek(0, 0); err != nil {
-		return "", fmt.Errorf("failed to seek to beginning of file: %v", err)
+	if format == imaging.FormatGIF {
+		// Анимация сохраняется только в оригинале, варианты строятся по первому кадру
+		original, err := imaging.ReencodeGIF(data)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return original, img, format, nil
+	}
+
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, format); err != nil {
+		return nil, nil, "", err
+	}
+
+	return buf.Bytes(), img, format, nil
+}
+
+// ImageSize возвращает вариант изображения по имени
+func (s *ImageService) ImageSize(name string) (config.ImageSize, bool) {
+	for _, size := range s.config.ImageSizes {
+		if size.Name == name {
+			return size, true
+		}
 	}
+	return config.ImageSize{}, false
+}
 
-	// Генерируем уникальное имя файла
-	fileName := s.generateFileName(file.Filename, mimeType)
+// renderVariant уменьшает изображение до варианта size
+func renderVariant(img image.Image, format string, size config.ImageSize) ([]byte, error) {
+	if size.Crop {
+		img = imaging.Fill(img, size.Width, size.Height)
+	} else {
+		img = imaging.Fit(img, size.Width, size.Height)
+	}
 
-	// Сохраняем файл
-	if s.config.Type == "local" {
-		return s.saveToLocal(src, fileName)
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, format); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
+}
 
-	return "", fmt.Errorf("unsupported storage type: %s", s.config.Type)
+// variantKey имя файла варианта: abc.jpg -> abc-thumb.jpg. Варианты GIF и
+// WebP сохраняются в PNG.
+func variantKey(fileName, sizeName string) string {
+	ext := filepath.Ext(fileName)
+	variantExt := ext
+	if ext == ".gif" || ext == ".webp" {
+		variantExt = ".png"
+	}
+	return strings.TrimSuffix(fileName, ext) + "-" + sizeName + variantExt
 }
 
-// GetImageURL возвращает полный URL изображения
+// contentAddressedName имя загрузки по SHA-256 содержимого или ее варианта
+var contentAddressedName = regexp.MustCompile(`^[0-9a-f]{64}(-[a-z0-9_]+)?\.[a-z0-9]+$`)
+
+// IsContentAddressed сообщает, назван ли файл по хешу содержимого. Такие файлы
+// никогда не перезаписываются, и клиенты могут кешировать их без ограничения срока.
+func IsContentAddressed(fileName string) bool {
+	return contentAddressedName.MatchString(fileName)
+}
+
+// ResolveImage возвращает имя файла для отдачи: оригинал без size или вариант.
+// Отсутствующий вариант (файлы, загруженные до появления варианта) создается
+// из оригинала при первом запросе. Для форматов без декодера и роликов
+// отдается оригинал.
+func (s *ImageService) ResolveImage(ctx context.Context, fileName, sizeName string) (string, error) {
+	// Отдаются только файлы с именами, которые выдает загрузка
+	isVideo := validation.IsVideoFileName(fileName)
+	if !isVideo && !validation.IsImageFileName(fileName) {
+		return "", fmt.Errorf("%w: %q", storage.ErrInvalidKey, fileName)
+	}
+
+	// У роликов нет уменьшенных вариантов
+	if sizeName == "" || isVideo {
+		return fileName, nil
+	}
+
+	size, ok := s.ImageSize(sizeName)
+	if !ok {
+		return "", errors.ErrUnknownImageSize
+	}
+
+	key := variantKey(fileName, size.Name)
+	_, err := s.storage.Stat(ctx, key)
+	if err == nil {
+		return key, nil
+	}
+	if !stderrors.Is(err, storage.ErrNotFound) {
+		return "", err
+	}
+
+	src, _, err := s.storage.Get(ctx, fileName)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	// Загрузки до проверки размеров не декодируются, если превышают лимиты:
+	// вместо варианта отдается оригинал
+	if err := imaging.CheckDimensions(data, s.config.MaxImageSide, s.config.MaxImagePixels); err != nil {
+		return fileName, nil
+	}
+
+	img, format, err := imaging.Decode(data)
+	if stderrors.Is(err, imaging.ErrUnsupportedFormat) {
+		return fileName, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	variant, err := renderVariant(img, format, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to render image variant: %w", err)
+	}
+	if err := s.put(ctx, imageObject{key: key, data: variant}); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// GetImageURL возвращает полный URL изображения. Адрес ведет на API независимо
+// от хранилища, поэтому сохраненные ссылки не меняются при переносе файлов.
 func (s *ImageService) GetImageURL(fileName string) string {
-	if s.config.Type == "local" {
-		return fmt.Sprintf("%s/%s", s.config.BaseURL, fileName)
+	return fmt.Sprintf("%s/%s", s.config.BaseURL, fileName)
+}
+
+// OwnedImageURLs возвращает ссылки на все загрузки пользователя
+func (s *ImageService) OwnedImageURLs(ctx context.Context, ownerID uuid.UUID) ([]string, error) {
+	images, err := s.imageRepo.GetAllByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(images))
+	for _, image := range images {
+		urls = append(urls, s.GetImageURL(image.FileName))
+	}
+	return urls, nil
+}
+
+// PresignImageURL возвращает временную ссылку на файл в хранилище
+func (s *ImageService) PresignImageURL(ctx context.Context, fileName string) (string, error) {
+	return s.storage.PresignGet(ctx, fileName, s.config.PresignTTL)
+}
+
+// UsableImages возвращает имена из fileNames, которые userID может прикрепить к
+// проекту projectID (uuid.Nil для нового проекта и аватара): загруженные им самим
+// или уже прикрепленные к этому проекту
+func (s *ImageService) UsableImages(ctx context.Context, userID, projectID uuid.UUID, fileNames []string) (map[string]bool, error) {
+	usable := make(map[string]bool, len(fileNames))
+	if len(fileNames) == 0 {
+		return usable, nil
+	}
+
+	names, err := s.imageRepo.GetUsable(ctx, userID, projectID, fileNames)
+	if err != nil {
+		return nil, err
 	}
-	return ""
+	for _, name := range names {
+		usable[name] = true
+	}
+
+	return usable, nil
 }
 
-// DeleteImage удаляет изображение
-func (s *ImageService) DeleteImage(fileName string) error {
-	if s.config.Type == "local" {
-		filePath := filepath.Join(s.config.LocalPath, fileName)
-		return os.Remove(filePath)
+// ProjectImageFiles возвращает имена загруженных файлов изображений и роликов
+// проекта по их URL; ссылки на сторонние адреса пропускаются. Ссылки на файлы
+// сохраняются вместе с проектом (ProjectRepository.Create и UpdateWithImages).
+func (s *ImageService) ProjectImageFiles(imageURLs []string) []string {
+	fileNames := []string{}
+	for _, imageURL := range imageURLs {
+		if fileName, ok := validation.UploadFileName(imageURL, s.config.BaseURL); ok {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+
+	return fileNames
+}
+
+// AttachAvatar сохраняет ссылку пользователя на аватар; прежний аватар становится
+// кандидатом на удаление
+func (s *ImageService) AttachAvatar(ctx context.Context, userID uuid.UUID, avatarURL string) error {
+	fileName, _ := validation.ImageFileName(avatarURL, s.config.BaseURL)
+	return s.imageRepo.ReplaceAvatarReference(ctx, userID, fileName)
+}
+
+// DeleteImage удаляет загрузку пользователя, если на нее больше никто не ссылается.
+// Файл удаляется из хранилища, только когда его не использует ни одна загрузка.
+func (s *ImageService) DeleteImage(ctx context.Context, ownerID uuid.UUID, fileName string) error {
+	upload, err := s.imageRepo.GetByOwner(ctx, ownerID, fileName)
+	if stderrors.Is(err, errors.ErrImageNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
 	}
-	return fmt.Errorf("unsupported storage type: %s", s.config.Type)
+
+	_, err = s.deleteUpload(ctx, upload)
+	return err
+}
+
+// deleteUpload удаляет загрузку без ссылок вместе с файлом, если файл больше не
+// используется. Возвращает false, если на загрузку уже сослались.
+func (s *ImageService) deleteUpload(ctx context.Context, upload *entities.Image) (bool, error) {
+	return s.imageRepo.DeleteOrphan(ctx, upload.ID, func(fileName string) error {
+		if err := s.deleteFiles(ctx, fileName); err != nil {
+			return fmt.Errorf("failed to delete files of image %s: %w", fileName, err)
+		}
+		return nil
+	})
 }
 
-// isAllowedMimeType проверяет, разрешен ли MIME тип
-func (s *ImageService) isAllowedMimeType(mimeType string) bool {
-	for _, allowedType := range s.config.AllowedTypes {
-		if mimeType == allowedType {
-			return true
+// deleteFiles удаляет оригинал и варианты; уже удаленные файлы не считаются ошибкой
+func (s *ImageService) deleteFiles(ctx context.Context, fileName string) error {
+	keys := []string{fileName}
+	for _, size := range s.config.ImageSizes {
+		keys = append(keys, variantKey(fileName, size.Name))
+	}
+
+	for _, key := range keys {
+		err := s.storage.Delete(ctx, key)
+		if err != nil && !stderrors.Is(err, storage.ErrNotFound) {
+			return err
 		}
 	}
-	return false
+
+	return nil
 }
 
-// generateFileName генерирует уникальное имя файла
-func (s *ImageService) generateFileName(originalName, mimeType string) string {
-	// Получаем расширение из MIME типа
-	ext := s.getExtensionFromMimeType(mimeType)
+// orphanBatchSize количество изображений, удаляемых за один проход очистки
+const orphanBatchSize = 100
 
-	// Генерируем уникальный хеш
-	hash := md5.Sum([]byte(fmt.Sprintf("%s-%d", originalName, time.Now().UnixNano())))
-	hashStr := fmt.Sprintf("%x", hash)
+// DeleteOrphans удаляет изображения, на которые никто не ссылается и которые
+// загружены раньше before (брошенные загрузки, замененные логотипы и аватары).
+// Возвращает количество удаленных изображений.
+func (s *ImageService) DeleteOrphans(ctx context.Context, before time.Time) (int, error) {
+	deleted := 0
+	for {
+		images, err := s.imageRepo.GetOrphans(ctx, before, orphanBatchSize)
+		if err != nil {
+			return deleted, err
+		}
 
-	// Создаем UUID для дополнительной уникальности
-	id := uuid.New().String()
+		for _, orphan := range images {
+			// Загрузка удаляется, только если ссылка не появилась после выборки
+			ok, err := s.deleteUpload(ctx, orphan)
+			if err != nil {
+				return deleted, err
+			}
+			if ok {
+				deleted++
+			}
+		}
+
+		if len(images) < orphanBatchSize {
+			return deleted, nil
+		}
+	}
+}
 
-	return fmt.Sprintf("%s_%s%s", id[:8], hashStr[:8], ext)
+// OpenImage открывает сохраненное изображение для чтения
+func (s *ImageService) OpenImage(ctx context.Context, fileName string) (io.ReadCloser, *storage.Object, error) {
+	return s.storage.Get(ctx, fileName)
 }
 
 // getExtensionFromMimeType возвращает расширение файла на основе MIME типа
@@ -133,24 +618,11 @@ func (s *ImageService) getExtensionFromMimeType(mimeType string) string {
 		return ".gif"
 	case "image/webp":
 		return ".webp"
+	case "video/mp4":
+		return ".mp4"
+	case "video/webm":
+		return ".webm"
 	default:
 		return ".jpg" // fallback
 	}
 }
-
-// saveToLocal сохраняет файл локально
-func (s *ImageService) saveToLocal(src io.Reader, fileName string) (string, error) {
-	filePath := filepath.Join(s.config.LocalPath, fileName)
-
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create file: %v", err)
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", fmt.Errorf("failed to save file: %v", err)
-	}
-
-	return fileName, nil
-}
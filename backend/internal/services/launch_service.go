@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
 	"startup-scout/internal/repository"
 	"time"
 
@@ -11,12 +13,20 @@ import (
 )
 
 type LaunchService struct {
-	launchRepo repository.LaunchRepository
+	launchRepo    repository.LaunchRepository
+	notifications *NotificationService
+	webhooks      *WebhookService
 }
 
-func NewLaunchService(launchRepo repository.LaunchRepository) *LaunchService {
+func NewLaunchService(
+	launchRepo repository.LaunchRepository,
+	notifications *NotificationService,
+	webhooks *WebhookService,
+) *LaunchService {
 	return &LaunchService{
-		launchRepo: launchRepo,
+		launchRepo:    launchRepo,
+		notifications: notifications,
+		webhooks:      webhooks,
 	}
 }
 
@@ -32,6 +42,15 @@ func (s *LaunchService) GetByID(ctx context.Context, id uuid.UUID) (*entities.La
 	return s.launchRepo.GetByID(ctx, id)
 }
 
+// GetByIDOrActive возвращает указанный запуск или текущий, если launchID не задан.
+// Только читает: запуски меняет Rollover.
+func (s *LaunchService) GetByIDOrActive(ctx context.Context, launchID *uuid.UUID) (*entities.Launch, error) {
+	if launchID == nil {
+		return s.launchRepo.GetActive(ctx)
+	}
+	return s.launchRepo.GetByID(ctx, *launchID)
+}
+
 func (s *LaunchService) GetAll(ctx context.Context) ([]*entities.Launch, error) {
 	return s.launchRepo.GetAll(ctx)
 }
@@ -44,29 +63,64 @@ func (s *LaunchService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.launchRepo.Delete(ctx, id)
 }
 
-// EnsureActiveLaunch проверяет наличие активного запуска и создает новый если нужно
-func (s *LaunchService) EnsureActiveLaunch(ctx context.Context) (*entities.Launch, error) {
-	// Проверяем, есть ли активный запуск
-	activeLaunch, err := s.launchRepo.GetActive(ctx)
-	if err == nil && activeLaunch != nil {
-		// Проверяем, не истек ли срок действия активного запуска
-		if time.Now().Before(activeLaunch.EndDate) {
-			return activeLaunch, nil
-		}
-		
-		// Если запуск истек, деактивируем его
-		activeLaunch.IsActive = false
-		if err := s.launchRepo.Update(ctx, activeLaunch); err != nil {
+// EnsureActive возвращает активный запуск, а если его нет (cron еще не отработал
+// или не настроен) - выполняет Rollover. Нужен операциям, которые пишут в текущий
+// запуск: добавлению проекта и голосованию.
+func (s *LaunchService) EnsureActive(ctx context.Context) (*entities.Launch, error) {
+	launch, err := s.launchRepo.GetActive(ctx)
+	if !stderrors.Is(err, errors.ErrNoActiveLaunch) {
+		return launch, err
+	}
+	return s.Rollover(ctx)
+}
+
+// Rollover завершает истекшие запуски и создает новый, если активного нет.
+// Вызывается задачей cron launch и из EnsureActive; параллельные вызовы
+// сериализуются advisory lock, поэтому уведомления и события о начале и
+// завершении запуска отправляются ровно один раз.
+func (s *LaunchService) Rollover(ctx context.Context) (*entities.Launch, error) {
+	unlock, err := s.launchRepo.LockRollover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	expired, err := s.launchRepo.GetExpiredActive(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, launch := range expired {
+		deactivated, err := s.launchRepo.Deactivate(ctx, launch.ID)
+		if err != nil {
 			return nil, fmt.Errorf("failed to deactivate expired launch: %w", err)
 		}
+		if !deactivated {
+			continue
+		}
+
+		// Запуск завершен: сообщаем мейкерам итоговые места
+		launch.IsActive = false
+		s.notifications.NotifyLaunchFinished(ctx, launch)
+		s.webhooks.EmitLaunchFinished(ctx, launch)
+	}
+
+	activeLaunch, err := s.launchRepo.GetActive(ctx)
+	if err == nil {
+		return activeLaunch, nil
+	}
+	if !stderrors.Is(err, errors.ErrNoActiveLaunch) {
+		return nil, err
 	}
 
-	// Создаем новый запуск
 	newLaunch, err := s.CreateNewWeeklyLaunch(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new launch: %w", err)
 	}
 
+	s.notifications.NotifyLaunchStarted(ctx, newLaunch)
+	s.webhooks.EmitLaunchStarted(ctx, newLaunch)
+
 	return newLaunch, nil
 }
 
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"time"
+)
+
+// LoginGuardConfig настройки прогрессивной блокировки входа
+type LoginGuardConfig struct {
+	MaxFailures   int           // количество неудачных попыток до первой блокировки
+	FailureWindow time.Duration // окно, в котором считаются неудачные попытки
+	BaseLockout   time.Duration // длительность первой блокировки
+	MaxLockout    time.Duration // максимальная длительность блокировки
+}
+
+// LoginGuard считает неудачные попытки входа по аккаунту и блокирует его,
+// удваивая длительность блокировки с каждой следующей неудачей.
+type LoginGuard struct {
+	store  repository.RateLimitStore
+	config LoginGuardConfig
+}
+
+func NewLoginGuard(store repository.RateLimitStore, config LoginGuardConfig) *LoginGuard {
+	return &LoginGuard{
+		store:  store,
+		config: config,
+	}
+}
+
+// Check возвращает LockoutError, если вход для ключа сейчас заблокирован
+func (g *LoginGuard) Check(ctx context.Context, key string) error {
+	counter, err := g.store.Get(ctx, g.storeKey(key))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if counter != nil && counter.IsBlocked(now) {
+		return &errors.LockoutError{RetryAfter: counter.BlockedUntil.Sub(now)}
+	}
+
+	return nil
+}
+
+// RegisterFailure учитывает неудачную попытку и при необходимости блокирует ключ
+func (g *LoginGuard) RegisterFailure(ctx context.Context, key string) error {
+	counter, err := g.store.Increment(ctx, g.storeKey(key), g.config.FailureWindow)
+	if err != nil {
+		return err
+	}
+
+	if g.config.MaxFailures <= 0 || counter.Count < g.config.MaxFailures {
+		return nil
+	}
+
+	return g.store.Block(ctx, g.storeKey(key), time.Now().Add(g.lockoutDuration(counter.Count)))
+}
+
+// RegisterSuccess сбрасывает счетчик неудачных попыток
+func (g *LoginGuard) RegisterSuccess(ctx context.Context, key string) error {
+	return g.store.Reset(ctx, g.storeKey(key))
+}
+
+func (g *LoginGuard) lockoutDuration(failures int) time.Duration {
+	lockout := g.config.BaseLockout
+	for i := g.config.MaxFailures; i < failures; i++ {
+		lockout *= 2
+		if g.config.MaxLockout > 0 && lockout >= g.config.MaxLockout {
+			return g.config.MaxLockout
+		}
+	}
+	return lockout
+}
+
+func (g *LoginGuard) storeKey(key string) string {
+	return "login_failures:" + key
+}
@@ -0,0 +1,411 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"net/url"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
+	"startup-scout/pkg/mail"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+//go:embed templates/layout.html templates/newsletter/*
+var newsletterTemplates embed.FS
+
+const (
+	defaultDigestTopProjects = 5
+	// digestWindow сколько времени после события дайджест еще рассылается
+	// (например, подписчикам, подтвердившим адрес позже)
+	digestWindow    = 7 * 24 * time.Hour
+	digestBatchSize = 100
+
+	digestLaunchStarted  = "launch_started"
+	digestLaunchFinished = "launch_finished"
+)
+
+type NewsletterConfig struct {
+	From        string
+	SiteURL     string
+	APIURL      string
+	TopProjects int
+}
+
+// NewsletterService управляет подпиской на рассылку (double opt-in) и рассылает
+// дайджесты запусков
+type NewsletterService struct {
+	newsletterRepo repository.NewsletterRepository
+	launchRepo     repository.LaunchRepository
+	projectRepo    repository.ProjectRepository
+	sender         mail.Sender
+	html           *htmltemplate.Template
+	text           *texttemplate.Template
+	config         NewsletterConfig
+	logger         *zap.Logger
+}
+
+func NewNewsletterService(
+	newsletterRepo repository.NewsletterRepository,
+	launchRepo repository.LaunchRepository,
+	projectRepo repository.ProjectRepository,
+	sender mail.Sender,
+	config NewsletterConfig,
+	logger *zap.Logger,
+) *NewsletterService {
+	if config.TopProjects <= 0 {
+		config.TopProjects = defaultDigestTopProjects
+	}
+	if config.SiteURL == "" {
+		config.SiteURL = defaultSiteURL
+	}
+	config.SiteURL = strings.TrimRight(config.SiteURL, "/")
+	config.APIURL = strings.TrimRight(config.APIURL, "/")
+
+	return &NewsletterService{
+		newsletterRepo: newsletterRepo,
+		launchRepo:     launchRepo,
+		projectRepo:    projectRepo,
+		sender:         sender,
+		html:           htmltemplate.Must(htmltemplate.ParseFS(newsletterTemplates, "templates/layout.html", "templates/newsletter/*.html")),
+		text:           texttemplate.Must(texttemplate.ParseFS(newsletterTemplates, "templates/newsletter/*.txt")),
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// SiteURL адрес сайта, на который возвращаются пользователи после перехода по ссылкам из писем
+func (s *NewsletterService) SiteURL() string {
+	return s.config.SiteURL
+}
+
+// DigestProject проект в дайджесте
+type DigestProject struct {
+	Place       int
+	Name        string
+	Description string
+	Upvotes     int
+	URL         string
+}
+
+type newsletterEmailData struct {
+	Subject        string
+	SiteURL        string
+	UnsubscribeURL string
+	Email          string
+	ConfirmURL     string
+	Launch         *entities.Launch
+	PreviousLaunch *entities.Launch
+	Projects       []DigestProject
+}
+
+// Subscribe создает неподтвержденную подписку и отправляет письмо со ссылкой подтверждения.
+// Для уже подтвержденного адреса ничего не делает, чтобы не раскрывать чужие подписки.
+func (s *NewsletterService) Subscribe(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	v := validation.New()
+	if v.Required("email", email) {
+		v.Email("email", email)
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	existing, err := s.newsletterRepo.GetByEmail(ctx, email)
+	if err == nil && existing.Status == entities.SubscriptionConfirmed {
+		return nil
+	}
+
+	confirmToken, err := newsletterToken()
+	if err != nil {
+		return err
+	}
+	unsubscribeToken, err := newsletterToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	subscription := &entities.NewsletterSubscription{
+		Email:            email,
+		Status:           entities.SubscriptionPending,
+		ConfirmToken:     sql.NullString{String: confirmToken, Valid: true},
+		UnsubscribeToken: unsubscribeToken,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.newsletterRepo.Upsert(ctx, subscription); err != nil {
+		return err
+	}
+
+	data := &newsletterEmailData{
+		Subject:    "Подтвердите подписку на Startup Scout",
+		SiteURL:    s.config.SiteURL,
+		Email:      email,
+		ConfirmURL: s.config.APIURL + "/newsletter/confirm?token=" + url.QueryEscape(confirmToken),
+	}
+
+	return s.send(ctx, email, "confirm", data, nil)
+}
+
+func (s *NewsletterService) Confirm(ctx context.Context, token string) error {
+	if token == "" {
+		return errors.ErrSubscriptionNotFound
+	}
+
+	subscription, err := s.newsletterRepo.GetByConfirmToken(ctx, token)
+	if err != nil {
+		return errors.ErrSubscriptionNotFound
+	}
+
+	return s.newsletterRepo.Confirm(ctx, subscription.ID, time.Now())
+}
+
+// Действия по ссылкам из писем
+const (
+	NewsletterActionConfirm     = "confirm"
+	NewsletterActionUnsubscribe = "unsubscribe"
+)
+
+type newsletterPageData struct {
+	Subject   string
+	SiteURL   string
+	Heading   string
+	Text      string
+	Button    string
+	ActionURL string
+	Token     string
+	Valid     bool
+}
+
+// RenderPage отрисовывает страницу, на которую ведет ссылка из письма. Сама
+// ссылка ничего не меняет: почтовые сканеры и предпросмотр открывают ссылки
+// GET-запросом, поэтому подписка меняется только отправкой формы со страницы.
+func (s *NewsletterService) RenderPage(w io.Writer, action, token string) error {
+	data := &newsletterPageData{
+		SiteURL:   s.config.SiteURL,
+		ActionURL: s.config.APIURL + "/newsletter/" + action,
+		Token:     token,
+		Valid:     token != "",
+	}
+
+	switch action {
+	case NewsletterActionConfirm:
+		data.Subject = "Подтверждение подписки"
+		data.Heading = "Подтвердите подписку"
+		data.Text = "Нажмите кнопку, чтобы получать еженедельный дайджест запусков Startup Scout."
+		data.Button = "Подтвердить подписку"
+	case NewsletterActionUnsubscribe:
+		data.Subject = "Отписка от рассылки"
+		data.Heading = "Отписаться от рассылки?"
+		data.Text = "Вы перестанете получать дайджест запусков Startup Scout."
+		data.Button = "Отписаться"
+	default:
+		return fmt.Errorf("unknown newsletter action: %s", action)
+	}
+
+	return s.html.ExecuteTemplate(w, "page.html", data)
+}
+
+// Unsubscribe отписывает адрес по токену из письма. Повторная отписка не считается ошибкой.
+func (s *NewsletterService) Unsubscribe(ctx context.Context, token string) error {
+	if token == "" {
+		return errors.ErrSubscriptionNotFound
+	}
+
+	subscription, err := s.newsletterRepo.GetByUnsubscribeToken(ctx, token)
+	if err != nil {
+		return errors.ErrSubscriptionNotFound
+	}
+
+	if subscription.Status == entities.SubscriptionUnsubscribed {
+		return nil
+	}
+
+	return s.newsletterRepo.Unsubscribe(ctx, subscription.ID, time.Now())
+}
+
+// SendLaunchDigests рассылает дайджесты о начале текущего запуска и об итогах
+// недавно завершившихся. Каждому подписчику дайджест уходит один раз, поэтому
+// задачу можно безопасно запускать повторно.
+func (s *NewsletterService) SendLaunchDigests(ctx context.Context, now time.Time) (int, error) {
+	launches, err := s.launchRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, launch := range launches {
+		if !launch.StartDate.After(now) && now.Before(launch.EndDate) && now.Sub(launch.StartDate) <= digestWindow {
+			previous := previousLaunch(launches, launch)
+			var projects []DigestProject
+			if previous != nil {
+				if projects, err = s.topProjects(ctx, previous); err != nil {
+					return total, err
+				}
+			}
+
+			sent, err := s.sendDigest(ctx, digestLaunchStarted, &newsletterEmailData{
+				Subject:        launch.Name + " открыт",
+				Launch:         launch,
+				PreviousLaunch: previous,
+				Projects:       projects,
+			})
+			total += sent
+			if err != nil {
+				return total, err
+			}
+		}
+
+		if !launch.EndDate.After(now) && now.Sub(launch.EndDate) <= digestWindow {
+			projects, err := s.topProjects(ctx, launch)
+			if err != nil {
+				return total, err
+			}
+
+			sent, err := s.sendDigest(ctx, digestLaunchFinished, &newsletterEmailData{
+				Subject:  "Итоги: " + launch.Name,
+				Launch:   launch,
+				Projects: projects,
+			})
+			total += sent
+			if err != nil {
+				return total, err
+			}
+		}
+	}
+
+	return total, nil
+}
+
+// sendDigest отправляет дайджест всем подтвержденным подписчикам, которые его еще
+// не получили. Ошибка отправки одному адресу не останавливает рассылку: адрес
+// будет повторен при следующем запуске задачи.
+func (s *NewsletterService) sendDigest(ctx context.Context, kind string, data *newsletterEmailData) (int, error) {
+	digestKey := kind + ":" + data.Launch.ID.String()
+	data.SiteURL = s.config.SiteURL
+
+	sent := 0
+	afterID := uuid.Nil
+	for {
+		subscriptions, err := s.newsletterRepo.GetUndelivered(ctx, digestKey, afterID, digestBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		if len(subscriptions) == 0 {
+			return sent, nil
+		}
+
+		for _, subscription := range subscriptions {
+			afterID = subscription.ID
+
+			unsubscribeURL := s.config.APIURL + "/newsletter/unsubscribe?token=" + url.QueryEscape(subscription.UnsubscribeToken)
+			recipientData := *data
+			recipientData.UnsubscribeURL = unsubscribeURL
+
+			headers := map[string]string{
+				"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+				"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+			}
+			if err := s.send(ctx, subscription.Email, kind, &recipientData, headers); err != nil {
+				s.logger.Warn("failed to send digest", zap.Error(err),
+					zap.String("digest", digestKey),
+					zap.String("subscription_id", subscription.ID.String()))
+				continue
+			}
+
+			if err := s.newsletterRepo.RecordDelivery(ctx, digestKey, subscription.ID, time.Now()); err != nil {
+				return sent, err
+			}
+			sent++
+		}
+	}
+}
+
+func (s *NewsletterService) topProjects(ctx context.Context, launch *entities.Launch) ([]DigestProject, error) {
+	projects, err := s.projectRepo.GetByLaunchIDOrderedByRating(ctx, launch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(projects) > s.config.TopProjects {
+		projects = projects[:s.config.TopProjects]
+	}
+
+	places := ratingPlaces(projects)
+	result := make([]DigestProject, 0, len(projects))
+	for i, project := range projects {
+		result = append(result, DigestProject{
+			Place:       places[i],
+			Name:        project.Name,
+			Description: project.Description,
+			Upvotes:     project.Upvotes,
+			URL:         s.config.SiteURL + "/project/" + project.ID.String(),
+		})
+	}
+
+	return result, nil
+}
+
+func (s *NewsletterService) send(
+	ctx context.Context,
+	to string,
+	templateName string,
+	data *newsletterEmailData,
+	headers map[string]string,
+) error {
+	var html bytes.Buffer
+	if err := s.html.ExecuteTemplate(&html, templateName+".html", data); err != nil {
+		return fmt.Errorf("failed to render %s email: %w", templateName, err)
+	}
+
+	var text bytes.Buffer
+	if err := s.text.ExecuteTemplate(&text, templateName+".txt", data); err != nil {
+		return fmt.Errorf("failed to render %s email: %w", templateName, err)
+	}
+
+	return s.sender.Send(ctx, &mail.Message{
+		From:    s.config.From,
+		To:      to,
+		Subject: data.Subject,
+		HTML:    html.String(),
+		Text:    text.String(),
+		Headers: headers,
+	})
+}
+
+// previousLaunch находит последний запуск, завершившийся до начала данного
+func previousLaunch(launches []*entities.Launch, launch *entities.Launch) *entities.Launch {
+	var previous *entities.Launch
+	for _, candidate := range launches {
+		if candidate.ID == launch.ID || candidate.EndDate.After(launch.StartDate) {
+			continue
+		}
+		if previous == nil || candidate.EndDate.After(previous.EndDate) {
+			previous = candidate
+		}
+	}
+	return previous
+}
+
+func newsletterToken() (string, error) {
+	token := make([]byte, 24)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
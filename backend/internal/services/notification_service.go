@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/pagination"
+	"startup-scout/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// voteMilestones отметки лайков, о которых сообщаем мейкеру. После последней
+// отметки уведомляем о каждой следующей тысяче.
+var voteMilestones = []int{1, 10, 25, 50, 100, 250, 500, 1000}
+
+// NotificationChannel дополнительный канал доставки уведомлений (например, Telegram).
+// Канал сам решает, подходит ли ему получатель.
+type NotificationChannel interface {
+	Deliver(ctx context.Context, recipient *entities.User, notification *entities.Notification) error
+}
+
+// NotificationService создает уведомления по событиям и отдает их пользователю.
+// Ошибки при создании уведомлений только логируются: они не должны ломать
+// основное действие (комментарий, голос, завершение запуска).
+type NotificationService struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	projectRepo      repository.ProjectRepository
+	teamRepo         repository.TeamRepository
+	channels         []NotificationChannel
+	logger           *zap.Logger
+}
+
+func NewNotificationService(
+	notificationRepo repository.NotificationRepository,
+	userRepo repository.UserRepository,
+	projectRepo repository.ProjectRepository,
+	teamRepo repository.TeamRepository,
+	logger *zap.Logger,
+	channels ...NotificationChannel,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		projectRepo:      projectRepo,
+		teamRepo:         teamRepo,
+		channels:         channels,
+		logger:           logger,
+	}
+}
+
+// NotificationPage страница уведомлений
+type NotificationPage struct {
+	*pagination.Page[*entities.Notification]
+	UnreadCount int `json:"unread_count"`
+}
+
+// NotifyComment сообщает владельцу и участникам команды проекта о новом
+// комментарии; автор комментария уведомление не получает
+func (s *NotificationService) NotifyComment(ctx context.Context, comment *entities.Comment) {
+	project, err := s.projectRepo.GetByID(ctx, comment.ProjectID)
+	if err != nil {
+		s.logger.Warn("failed to load project for comment notification", zap.Error(err),
+			zap.String("project_id", comment.ProjectID.String()))
+		return
+	}
+
+	recipients := s.projectRecipients(ctx, project, comment.UserID)
+	if len(recipients) == 0 {
+		return
+	}
+
+	var actorUsername *string
+	if actor, err := s.userRepo.GetByID(ctx, comment.UserID); err == nil {
+		actorUsername = &actor.Username
+	}
+
+	for _, userID := range recipients {
+		s.notify(ctx, &entities.Notification{
+			UserID:        userID,
+			Type:          entities.NotificationComment,
+			ProjectID:     &project.ID,
+			ActorID:       &comment.UserID,
+			ActorUsername: actorUsername,
+			CommentID:     &comment.ID,
+			ProjectName:   &project.Name,
+			Content:       &comment.Content,
+		})
+	}
+}
+
+// NotifyVotes сообщает команде проекта, если число лайков перешагнуло очередную отметку.
+// Лайки приходят по одному, поэтому уведомления группируются по отметкам, а
+// повторное достижение той же отметки (после снятия лайка) не дублируется.
+func (s *NotificationService) NotifyVotes(ctx context.Context, project *entities.Project, previousUpvotes int) {
+	milestone := reachedVoteMilestone(previousUpvotes, project.Upvotes)
+	if milestone == 0 {
+		return
+	}
+
+	for _, userID := range s.projectRecipients(ctx, project, uuid.Nil) {
+		s.notify(ctx, &entities.Notification{
+			UserID:      userID,
+			Type:        entities.NotificationVoteMilestone,
+			ProjectID:   &project.ID,
+			LaunchID:    &project.LaunchID,
+			Milestone:   &milestone,
+			DedupKey:    dedupKey(entities.NotificationVoteMilestone, project.ID, milestone),
+			ProjectName: &project.Name,
+		})
+	}
+}
+
+// NotifyLaunchFinished сообщает командам проектов итоговые места в завершенном запуске
+func (s *NotificationService) NotifyLaunchFinished(ctx context.Context, launch *entities.Launch) {
+	projects, err := s.projectRepo.GetByLaunchIDOrderedByRating(ctx, launch.ID)
+	if err != nil {
+		s.logger.Error("failed to load launch projects for notifications", zap.Error(err),
+			zap.String("launch_id", launch.ID.String()))
+		return
+	}
+
+	places := ratingPlaces(projects)
+	for i, project := range projects {
+		projectPlace := places[i]
+		for _, userID := range s.projectRecipients(ctx, project, uuid.Nil) {
+			s.notify(ctx, &entities.Notification{
+				UserID:      userID,
+				Type:        entities.NotificationLaunchResult,
+				ProjectID:   &project.ID,
+				LaunchID:    &launch.ID,
+				Place:       &projectPlace,
+				DedupKey:    dedupKey(entities.NotificationLaunchResult, project.ID, 0),
+				ProjectName: &project.Name,
+				LaunchName:  &launch.Name,
+			})
+		}
+	}
+}
+
+// projectRecipients возвращает владельца и участников команды проекта без
+// повторов, кроме exclude (например, автора комментария)
+func (s *NotificationService) projectRecipients(ctx context.Context, project *entities.Project, exclude uuid.UUID) []uuid.UUID {
+	members, err := s.teamRepo.GetMembers(ctx, project.ID)
+	if err != nil {
+		s.logger.Warn("failed to load project team for notification", zap.Error(err),
+			zap.String("project_id", project.ID.String()))
+	}
+
+	// Владелец проекта может отсутствовать в команде у проектов, созданных до появления команд
+	candidates := []uuid.UUID{project.UserID}
+	for _, member := range members {
+		candidates = append(candidates, member.UserID)
+	}
+
+	recipients := make([]uuid.UUID, 0, len(candidates))
+	seen := make(map[uuid.UUID]bool, len(candidates))
+	for _, userID := range candidates {
+		if userID == uuid.Nil || userID == exclude || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		recipients = append(recipients, userID)
+	}
+	return recipients
+}
+
+// NotifyProjectInvitation сообщает пользователю о приглашении в команду проекта
+func (s *NotificationService) NotifyProjectInvitation(ctx context.Context, invitation *entities.ProjectInvitation) {
+	if invitation.InviteeID == nil {
+		return
+	}
+
+	projectName := invitation.ProjectName
+	s.notify(ctx, &entities.Notification{
+		UserID:        *invitation.InviteeID,
+		Type:          entities.NotificationProjectInvitation,
+		ProjectID:     &invitation.ProjectID,
+		ActorID:       invitation.InviterID,
+		DedupKey:      dedupKey(entities.NotificationProjectInvitation, invitation.ID, 0),
+		ProjectName:   &projectName,
+		ActorUsername: invitation.InviterUsername,
+	})
+}
+
+// NotifyLaunchStarted рассылает всем подписанным на этот тип пользователям весть о новом запуске
+func (s *NotificationService) NotifyLaunchStarted(ctx context.Context, launch *entities.Launch) {
+	notification := &entities.Notification{
+		Type:       entities.NotificationLaunchStarted,
+		LaunchID:   &launch.ID,
+		DedupKey:   dedupKey(entities.NotificationLaunchStarted, launch.ID, 0),
+		LaunchName: &launch.Name,
+		CreatedAt:  time.Now(),
+	}
+
+	recipients, err := s.notificationRepo.Broadcast(ctx, notification)
+	if err != nil {
+		s.logger.Error("failed to broadcast launch notification", zap.Error(err),
+			zap.String("launch_id", launch.ID.String()))
+		return
+	}
+
+	if len(s.channels) == 0 {
+		return
+	}
+
+	for _, userID := range recipients {
+		recipient, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			s.logger.Warn("failed to load notification recipient", zap.Error(err),
+				zap.String("user_id", userID.String()))
+			continue
+		}
+
+		delivered := *notification
+		delivered.UserID = userID
+		s.deliver(ctx, recipient, &delivered)
+	}
+}
+
+func (s *NotificationService) notify(ctx context.Context, notification *entities.Notification) {
+	recipient, err := s.userRepo.GetByID(ctx, notification.UserID)
+	if err != nil {
+		s.logger.Warn("failed to load notification recipient", zap.Error(err),
+			zap.String("user_id", notification.UserID.String()))
+		return
+	}
+
+	if !recipient.IsActive || recipient.DeletedAt != nil || !recipient.NotificationPreferences.Allows(notification.Type) {
+		return
+	}
+
+	notification.CreatedAt = time.Now()
+	created, err := s.notificationRepo.Create(ctx, notification)
+	if err != nil {
+		s.logger.Error("failed to create notification", zap.Error(err),
+			zap.String("user_id", notification.UserID.String()),
+			zap.String("type", string(notification.Type)))
+		return
+	}
+
+	// Повторные события (та же отметка лайков) не рассылаются по каналам заново
+	if created {
+		s.deliver(ctx, recipient, notification)
+	}
+}
+
+func (s *NotificationService) deliver(ctx context.Context, recipient *entities.User, notification *entities.Notification) {
+	for _, channel := range s.channels {
+		if err := channel.Deliver(ctx, recipient, notification); err != nil {
+			s.logger.Error("failed to deliver notification", zap.Error(err),
+				zap.String("user_id", recipient.ID.String()),
+				zap.String("type", string(notification.Type)))
+		}
+	}
+}
+
+// List возвращает страницу уведомлений пользователя вместе с числом непрочитанных
+func (s *NotificationService) List(
+	ctx context.Context,
+	userID uuid.UUID,
+	unreadOnly bool,
+	params pagination.Params,
+) (*NotificationPage, error) {
+	notifications, err := s.notificationRepo.GetByUserID(ctx, userID, unreadOnly, params.Cursor, params.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &NotificationPage{
+		Page: pagination.NewPage(notifications, params.Limit, func(notification *entities.Notification) pagination.Cursor {
+			return pagination.Cursor{Time: notification.CreatedAt, ID: notification.ID}
+		}),
+		UnreadCount: unread,
+	}
+
+	return page, nil
+}
+
+func (s *NotificationService) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+// MarkRead помечает уведомление прочитанным; false, если уведомление не найдено
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) (bool, error) {
+	return s.notificationRepo.MarkRead(ctx, userID, notificationID)
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+func (s *NotificationService) UpdatePreferences(
+	ctx context.Context,
+	userID uuid.UUID,
+	preferences entities.NotificationPreferences,
+) error {
+	return s.userRepo.UpdateNotificationPreferences(ctx, userID, preferences)
+}
+
+// reachedVoteMilestone возвращает наибольшую отметку в интервале (previous, current] или 0
+func reachedVoteMilestone(previous, current int) int {
+	reached := 0
+	for _, milestone := range voteMilestones {
+		if milestone > previous && milestone <= current {
+			reached = milestone
+		}
+	}
+
+	last := voteMilestones[len(voteMilestones)-1]
+	if current > last {
+		milestone := current / last * last
+		if milestone > previous && milestone > reached {
+			reached = milestone
+		}
+	}
+
+	return reached
+}
+
+// dedupKey ключ события: тип, объект (проект или запуск) и значение (отметка лайков)
+func dedupKey(notificationType entities.NotificationType, subjectID uuid.UUID, value int) sql.NullString {
+	return sql.NullString{String: fmt.Sprintf("%s:%s:%d", notificationType, subjectID, value), Valid: true}
+}
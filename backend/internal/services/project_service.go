@@ -2,10 +2,21 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
 	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/pagination"
 	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
+	"startup-scout/pkg/cache"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 )
@@ -14,28 +25,63 @@ type ProjectService struct {
 	projectRepo   repository.ProjectRepository
 	voteRepo      repository.VoteRepository
 	launchRepo    repository.LaunchRepository
+	categoryRepo  repository.CategoryRepository
+	teamRepo      repository.TeamRepository
 	launchService *LaunchService
+	imageService  *ImageService
+	notifications *NotificationService
+	webhooks      *WebhookService
+
+	// Кеш рейтинга проектов и статистики; сбрасывается при изменении проектов и голосов.
+	// Смена запуска в cron в этом процессе не видна, устаревание ограничено TTL.
+	pages       *cache.TTL[string, *ProjectPage]
+	leaderboard *cache.TTL[string, []*entities.Project]
 }
 
+// maxCachedPages ограничивает число кешируемых сочетаний фильтров и курсоров
+const maxCachedPages = 1000
+
 func NewProjectService(
 	projectRepo repository.ProjectRepository,
 	voteRepo repository.VoteRepository,
 	launchRepo repository.LaunchRepository,
+	categoryRepo repository.CategoryRepository,
+	teamRepo repository.TeamRepository,
 	launchService *LaunchService,
+	imageService *ImageService,
+	notifications *NotificationService,
+	webhooks *WebhookService,
+	listingTTL time.Duration,
 ) *ProjectService {
 	return &ProjectService{
 		projectRepo:   projectRepo,
 		voteRepo:      voteRepo,
 		launchRepo:    launchRepo,
+		categoryRepo:  categoryRepo,
+		teamRepo:      teamRepo,
 		launchService: launchService,
+		imageService:  imageService,
+		notifications: notifications,
+		webhooks:      webhooks,
+		pages:         cache.NewTTL[string, *ProjectPage](listingTTL, maxCachedPages),
+		leaderboard:   cache.NewTTL[string, []*entities.Project](listingTTL, 1),
 	}
 }
 
+// InvalidateListings сбрасывает кеш рейтинга после изменения проектов или голосов
+func (s *ProjectService) InvalidateListings() {
+	s.pages.Clear()
+	s.leaderboard.Clear()
+}
+
 func (s *ProjectService) CreateProject(ctx context.Context, project *entities.Project) error {
-	// Убеждаемся, что есть активный запуск
-	activeLaunch, err := s.launchService.EnsureActiveLaunch(ctx)
+	if err := s.validateProject(ctx, project.UserID, project); err != nil {
+		return err
+	}
+
+	activeLaunch, err := s.launchService.EnsureActive(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to ensure active launch: %w", err)
+		return err
 	}
 
 	project.CreatedAt = time.Now()
@@ -44,39 +90,594 @@ func (s *ProjectService) CreateProject(ctx context.Context, project *entities.Pr
 	project.Rating = 0
 	project.LaunchID = activeLaunch.ID
 
-	return s.projectRepo.Create(ctx, project)
+	// Ссылки на изображения пишутся в той же транзакции: иначе сохраненный проект
+	// остался бы без ссылок и его файлы удалила бы очистка неиспользуемых загрузок
+	imageFiles := s.imageService.ProjectImageFiles(projectImages(project))
+	if err := s.projectRepo.Create(ctx, project, imageFiles); err != nil {
+		return err
+	}
+	s.InvalidateListings()
+
+	s.webhooks.EmitProjectCreated(ctx, project)
+
+	return nil
+}
+
+// validateProject нормализует и проверяет поля проекта перед сохранением.
+// userID - пользователь, сохраняющий проект: прикреплять можно только его
+// загрузки и изображения, уже прикрепленные к проекту.
+func (s *ProjectService) validateProject(ctx context.Context, userID uuid.UUID, project *entities.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	project.Description = strings.TrimSpace(project.Description)
+	project.FullDescription = strings.TrimSpace(project.FullDescription)
+	project.TelegramContact.String = strings.TrimSpace(project.TelegramContact.String)
+	project.TelegramContact.Valid = project.TelegramContact.String != ""
+	project.Website.String = strings.TrimSpace(project.Website.String)
+	project.Website.Valid = project.Website.String != ""
+	project.Tagline = strings.TrimSpace(project.Tagline)
+	project.PricingModel = entities.PricingModel(strings.ToLower(strings.TrimSpace(string(project.PricingModel))))
+	project.Stage = entities.ProjectStage(strings.ToLower(strings.TrimSpace(string(project.Stage))))
+
+	imageBaseURL := s.imageService.GetConfig().BaseURL
+
+	v := validation.New()
+	if v.Required("name", project.Name) {
+		v.MaxLength("name", project.Name, validation.ProjectNameMaxLength)
+	}
+	if v.Required("description", project.Description) {
+		v.MaxLength("description", project.Description, validation.DescriptionMaxLength)
+	}
+	v.MaxLength("tagline", project.Tagline, validation.TaglineMaxLength)
+	v.MaxLength("full_description", project.FullDescription, validation.FullDescriptionMaxLength)
+
+	if project.Website.Valid {
+		v.URL("website", project.Website.String, "http", "https")
+	}
+	if project.TelegramContact.Valid {
+		v.TelegramContact("telegram_contact", project.TelegramContact.String)
+	}
+
+	// Изображения нашего хранилища для проверки владельца
+	imageFields := []imageField{}
+
+	if project.Logo != nil {
+		logo := strings.TrimSpace(*project.Logo)
+		if logo == "" {
+			project.Logo = nil
+		} else {
+			project.Logo = &logo
+			if v.ImageURL("logo", logo, imageBaseURL) {
+				fileName, _ := validation.ImageFileName(logo, imageBaseURL)
+				imageFields = append(imageFields, imageField{field: "logo", fileName: fileName})
+			}
+		}
+	}
+
+	// Клиенты без поддержки галереи присылают только images
+	if project.Gallery == nil {
+		project.Gallery = entities.GalleryFromImages(project.Images)
+	}
+	imageFields = append(imageFields, validateGallery(v, project, imageBaseURL)...)
+	project.Images = project.Gallery.Images()
+
+	if err := s.validateImageOwnership(ctx, v, userID, project.ID, imageFields); err != nil {
+		return err
+	}
+
+	if v.MaxItems("creators", len(project.Creators), validation.MaxCreators) {
+		creators := make(entities.StringArray, 0, len(project.Creators))
+		for i, creator := range project.Creators {
+			creator = strings.TrimSpace(creator)
+			if creator == "" {
+				continue
+			}
+			v.MaxLength(fmt.Sprintf("creators[%d]", i), creator, validation.CreatorMaxLength)
+			creators = append(creators, creator)
+		}
+		project.Creators = creators
+	}
+
+	validateProjectLinks(v, project)
+
+	if !project.PricingModel.Valid() {
+		v.Add("pricing_model", validation.CodeInvalidChoice, "unknown pricing model")
+	}
+	if !project.Stage.Valid() {
+		v.Add("stage", validation.CodeInvalidChoice, "unknown project stage")
+	}
+
+	platforms := make(entities.StringArray, 0, len(project.Platforms))
+	for i, platform := range project.Platforms {
+		platform = strings.ToLower(strings.TrimSpace(platform))
+		if !entities.IsProjectPlatform(platform) {
+			v.Add(fmt.Sprintf("platforms[%d]", i), validation.CodeInvalidChoice, "unknown platform")
+			continue
+		}
+		if !slices.Contains(platforms, platform) {
+			platforms = append(platforms, platform)
+		}
+	}
+	project.Platforms = platforms
+
+	if project.CategoryID != nil {
+		_, err := s.categoryRepo.GetByID(ctx, *project.CategoryID)
+		if stderrors.Is(err, errors.ErrCategoryNotFound) {
+			v.Add("category_id", validation.CodeNotFound, "category not found")
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if v.MaxItems("tags", len(project.Tags), validation.MaxProjectTags) {
+		tags := make(entities.StringArray, 0, len(project.Tags))
+		seen := make(map[string]bool, len(project.Tags))
+		for i, tag := range project.Tags {
+			tag = NormalizeTag(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			v.Tag(fmt.Sprintf("tags[%d]", i), tag)
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+		project.Tags = tags
+	}
+
+	return v.Err()
+}
+
+// UpdateProject заменяет редактируемые поля проекта. Изменять проект могут
+// владелец и соавторы; запуск, автор и рейтинг сохраняются.
+func (s *ProjectService) UpdateProject(ctx context.Context, userID uuid.UUID, project *entities.Project) error {
+	existing, err := s.projectRepo.GetByID(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	role, err := s.teamRepo.GetRole(ctx, project.ID, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return errors.ErrNotProjectMember
+	}
+
+	// Прежние клиенты присылают только images: ролики галереи при этом сохраняются
+	if project.Gallery == nil {
+		project.Gallery = entities.GalleryFromImages(project.Images)
+		for _, item := range existing.Gallery {
+			if item.Type != entities.GalleryImage {
+				project.Gallery = append(project.Gallery, item)
+			}
+		}
+	}
+
+	if err := s.validateProject(ctx, userID, project); err != nil {
+		return err
+	}
+
+	project.UserID = existing.UserID
+	project.LaunchID = existing.LaunchID
+	project.Upvotes = existing.Upvotes
+	project.Rating = existing.Rating
+	project.CreatedAt = existing.CreatedAt
+	project.UpdatedAt = time.Now()
+
+	imageFiles := s.imageService.ProjectImageFiles(projectImages(project))
+	if err := s.projectRepo.UpdateWithImages(ctx, project, userID, imageFiles); err != nil {
+		return err
+	}
+	s.InvalidateListings()
+
+	return nil
 }
 
+// ProjectImageQuota возвращает остаток галереи проекта для новой загрузки. Загружать
+// изображения и ролики в проект могут участники команды, пока галерея не заполнена
+// до лимита, который принимает CreateProject.
+func (s *ProjectService) ProjectImageQuota(ctx context.Context, userID, projectID uuid.UUID) (*entities.QuotaUsage, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.teamRepo.GetRole(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, errors.ErrNotProjectMember
+	}
+
+	quota := quotaUsage(validation.MaxGalleryItems, int64(len(project.Gallery)))
+	if *quota.Remaining == 0 {
+		return &quota, errors.ErrProjectImageLimit
+	}
+
+	return &quota, nil
+}
+
+// imageField поле проекта с файлом нашего хранилища
+type imageField struct {
+	field    string
+	fileName string
+}
+
+// validateImageOwnership добавляет ошибку CodeNotOwned для файлов, которые
+// userID не может прикрепить к проекту projectID
+func (s *ProjectService) validateImageOwnership(
+	ctx context.Context,
+	v *validation.Validator,
+	userID, projectID uuid.UUID,
+	imageFields []imageField,
+) error {
+	fileNames := make([]string, 0, len(imageFields))
+	for _, image := range imageFields {
+		fileNames = append(fileNames, image.fileName)
+	}
+
+	usable, err := s.imageService.UsableImages(ctx, userID, projectID, fileNames)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range imageFields {
+		if !usable[image.fileName] {
+			v.Add(image.field, validation.CodeNotOwned, "file was uploaded by another user")
+		}
+	}
+
+	return nil
+}
+
+// GetProject возвращает проект вместе с командой
 func (s *ProjectService) GetProject(ctx context.Context, id uuid.UUID) (*entities.Project, error) {
-	return s.projectRepo.GetByID(ctx, id)
+	project, err := s.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	makers, err := s.teamRepo.GetMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, maker := range makers {
+		maker.DisplayName = displayName(maker.FirstName, maker.LastName, maker.Username)
+	}
+	project.Makers = makers
+
+	return project, nil
 }
 
 func (s *ProjectService) GetProjectsByLaunch(ctx context.Context, launchID uuid.UUID) ([]*entities.Project, error) {
 	return s.projectRepo.GetByLaunchIDOrderedByRating(ctx, launchID)
 }
 
-func (s *ProjectService) GetActiveLaunchProjects(ctx context.Context) ([]*entities.Project, error) {
-	// Убеждаемся, что есть активный запуск
-	activeLaunch, err := s.launchService.EnsureActiveLaunch(ctx)
+// projectLinkHosts домены, на которые должны вести ссылки каждого типа;
+// для демо-видео домен не ограничивается
+var projectLinkHosts = map[entities.ProjectLinkType][]string{
+	entities.LinkAppStore:   {"apps.apple.com"},
+	entities.LinkGooglePlay: {"play.google.com"},
+	entities.LinkGitHub:     {"github.com"},
+	entities.LinkTwitter:    {"x.com", "twitter.com"},
+}
+
+// validateProjectLinks нормализует и проверяет ссылки проекта: не больше одной ссылки
+// каждого типа, https-адреса на домены, соответствующие типу
+func validateProjectLinks(v *validation.Validator, project *entities.Project) {
+	if !v.MaxItems("links", len(project.Links), validation.MaxProjectLinks) {
+		return
+	}
+
+	links := make(entities.ProjectLinks, 0, len(project.Links))
+	seen := make(map[entities.ProjectLinkType]bool, len(project.Links))
+	for i, link := range project.Links {
+		field := fmt.Sprintf("links[%d]", i)
+		link.Type = entities.ProjectLinkType(strings.TrimSpace(string(link.Type)))
+		link.URL = strings.TrimSpace(link.URL)
+
+		if !link.Type.Valid() {
+			v.Add(field+".type", validation.CodeInvalidChoice, "unknown link type")
+			continue
+		}
+		if seen[link.Type] {
+			v.Add(field+".type", validation.CodeTooMany, "a link of this type is already specified")
+			continue
+		}
+		seen[link.Type] = true
+
+		if !v.Required(field+".url", link.URL) || !v.URL(field+".url", link.URL, "https") {
+			continue
+		}
+		if hosts, ok := projectLinkHosts[link.Type]; ok {
+			parsed, _ := url.Parse(link.URL)
+			host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+			if !slices.Contains(hosts, host) {
+				v.Add(field+".url", validation.CodeInvalidFormat, "link must point to "+strings.Join(hosts, " or "))
+				continue
+			}
+		}
+
+		links = append(links, link)
+	}
+	project.Links = links
+}
+
+var (
+	youtubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoIDPattern   = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// videoEmbed встраиваемый ролик: адрес плеера и обложка сервиса (пустая, если
+// сервис не дает постоянной ссылки на обложку)
+type videoEmbed struct {
+	provider  string
+	embedURL  string
+	thumbnail string
+}
+
+// parseVideoEmbed распознает ссылку на ролик YouTube (watch, youtu.be, shorts,
+// embed) или Vimeo и строит адрес плеера. Плеер YouTube подключается с домена
+// youtube-nocookie.com.
+func parseVideoEmbed(rawURL string) (videoEmbed, bool) {
+	parsed, err := url.Parse(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to ensure active launch: %w", err)
+		return videoEmbed{}, false
 	}
 
-	projects, err := s.projectRepo.GetByLaunchIDOrderedByRating(ctx, activeLaunch.ID)
+	host := strings.ToLower(parsed.Hostname())
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "www."), "m.")
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+
+	switch host {
+	case "youtube.com", "youtube-nocookie.com", "youtu.be":
+		var id string
+		switch {
+		case host == "youtu.be" && len(segments) == 1:
+			id = segments[0]
+		case len(segments) == 1 && segments[0] == "watch":
+			id = parsed.Query().Get("v")
+		case len(segments) == 2 && slices.Contains([]string{"embed", "shorts", "live"}, segments[0]):
+			id = segments[1]
+		}
+		if !youtubeIDPattern.MatchString(id) {
+			return videoEmbed{}, false
+		}
+		return videoEmbed{
+			provider:  "youtube",
+			embedURL:  "https://www.youtube-nocookie.com/embed/" + id,
+			thumbnail: "https://i.ytimg.com/vi/" + id + "/hqdefault.jpg",
+		}, true
+	case "vimeo.com", "player.vimeo.com":
+		var id string
+		switch {
+		case host == "vimeo.com" && len(segments) == 1:
+			id = segments[0]
+		case host == "player.vimeo.com" && len(segments) == 2 && segments[0] == "video":
+			id = segments[1]
+		}
+		if !vimeoIDPattern.MatchString(id) {
+			return videoEmbed{}, false
+		}
+		return videoEmbed{
+			provider: "vimeo",
+			embedURL: "https://player.vimeo.com/video/" + id,
+		}, true
+	}
+
+	return videoEmbed{}, false
+}
+
+// validateGallery нормализует и проверяет элементы галереи и возвращает файлы
+// нашего хранилища для проверки владельца. Порядок элементов - порядок показа.
+func validateGallery(v *validation.Validator, project *entities.Project, baseURL string) []imageField {
+	fields := []imageField{}
+	if !v.MaxItems("gallery", len(project.Gallery), validation.MaxGalleryItems) {
+		return fields
+	}
+
+	gallery := make(entities.ProjectGallery, 0, len(project.Gallery))
+	for i, item := range project.Gallery {
+		field := fmt.Sprintf("gallery[%d]", i)
+		item.Type = entities.GalleryItemType(strings.TrimSpace(string(item.Type)))
+		item.URL = strings.TrimSpace(item.URL)
+		item.Caption = strings.TrimSpace(item.Caption)
+		item.Thumbnail = strings.TrimSpace(item.Thumbnail)
+		item.Provider = ""
+		item.EmbedURL = ""
+
+		v.MaxLength(field+".caption", item.Caption, validation.GalleryCaptionMaxLength)
+
+		// Своя обложка ролика должна быть изображением нашего хранилища
+		customThumbnail := item.Thumbnail != ""
+
+		switch item.Type {
+		case entities.GalleryImage:
+			item.Thumbnail = ""
+			customThumbnail = false
+			if v.Required(field+".url", item.URL) && v.ImageURL(field+".url", item.URL, baseURL) {
+				fileName, _ := validation.ImageFileName(item.URL, baseURL)
+				fields = append(fields, imageField{field: field + ".url", fileName: fileName})
+			}
+		case entities.GalleryVideo:
+			if v.Required(field+".url", item.URL) && v.VideoURL(field+".url", item.URL, baseURL) {
+				fileName, _ := validation.VideoFileName(item.URL, baseURL)
+				fields = append(fields, imageField{field: field + ".url", fileName: fileName})
+			}
+		case entities.GalleryEmbed:
+			if !v.Required(field+".url", item.URL) || !v.URL(field+".url", item.URL, "https") {
+				break
+			}
+			embed, ok := parseVideoEmbed(item.URL)
+			if !ok {
+				v.Add(field+".url", validation.CodeInvalidFormat, "only YouTube and Vimeo videos are supported")
+				break
+			}
+			item.Provider = embed.provider
+			item.EmbedURL = embed.embedURL
+
+			// Без своей обложки используется обложка сервиса (ее же клиент
+			// присылает обратно при редактировании)
+			if item.Thumbnail == "" || item.Thumbnail == embed.thumbnail {
+				item.Thumbnail = embed.thumbnail
+				customThumbnail = false
+				if item.Thumbnail == "" {
+					v.Add(field+".thumbnail", validation.CodeRequired, "upload a video thumbnail")
+				}
+			}
+		default:
+			v.Add(field+".type", validation.CodeInvalidChoice, "unknown gallery item type")
+			continue
+		}
+
+		if customThumbnail && v.ImageURL(field+".thumbnail", item.Thumbnail, baseURL) {
+			fileName, _ := validation.ImageFileName(item.Thumbnail, baseURL)
+			fields = append(fields, imageField{field: field + ".thumbnail", fileName: fileName})
+		}
+
+		gallery = append(gallery, item)
+	}
+	project.Gallery = gallery
+
+	return fields
+}
+
+// NormalizeTag приводит тег к каноничному виду: нижний регистр, без "#",
+// пробелы и подчеркивания заменяются дефисом
+func NormalizeTag(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	tag = strings.ToLower(tag)
+	tag = strings.Join(strings.FieldsFunc(tag, func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	}), "-")
+	return tag
+}
+
+// ProjectPage страница списка проектов
+type ProjectPage = pagination.Page[*entities.Project]
+
+// VotePage страница голосов пользователя
+type VotePage = pagination.Page[*entities.Vote]
+
+// ProjectListFilter фильтры списка проектов из запроса; пустые поля не ограничивают выборку
+type ProjectListFilter struct {
+	LaunchID     *uuid.UUID // nil - текущий запуск
+	Category     string     // slug категории
+	Tag          string
+	Platform     string
+	Stage        entities.ProjectStage
+	PricingModel entities.PricingModel
+}
+
+// projectFilter проверяет значения фильтров и собирает entities.ProjectFilter
+func (f ProjectListFilter) projectFilter(launchID uuid.UUID, categoryID *uuid.UUID) (entities.ProjectFilter, error) {
+	filter := entities.ProjectFilter{
+		LaunchID:     launchID,
+		CategoryID:   categoryID,
+		Tag:          NormalizeTag(f.Tag),
+		Platform:     strings.ToLower(strings.TrimSpace(f.Platform)),
+		Stage:        f.Stage,
+		PricingModel: f.PricingModel,
+	}
+
+	v := validation.New()
+	if filter.Platform != "" && !entities.IsProjectPlatform(filter.Platform) {
+		v.Add("platform", validation.CodeInvalidChoice, "unknown platform")
+	}
+	if !filter.Stage.Valid() {
+		v.Add("stage", validation.CodeInvalidChoice, "unknown project stage")
+	}
+	if !filter.PricingModel.Valid() {
+		v.Add("pricing_model", validation.CodeInvalidChoice, "unknown pricing model")
+	}
+
+	return filter, v.Err()
+}
+
+// GetProjects возвращает страницу проектов запуска в порядке рейтинга с учетом фильтров
+func (s *ProjectService) GetProjects(
+	ctx context.Context,
+	listFilter ProjectListFilter,
+	params pagination.Params,
+) (*ProjectPage, error) {
+	key, err := json.Marshal(struct {
+		Filter ProjectListFilter
+		Params pagination.Params
+	}{listFilter, params})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build projects cache key: %w", err)
+	}
+
+	return s.pages.GetOrLoad(string(key), func() (*ProjectPage, error) {
+		return s.loadProjects(ctx, listFilter, params)
+	})
+}
+
+func (s *ProjectService) loadProjects(
+	ctx context.Context,
+	listFilter ProjectListFilter,
+	params pagination.Params,
+) (*ProjectPage, error) {
+	launch, err := s.launchService.GetByIDOrActive(ctx, listFilter.LaunchID)
+	if listFilter.LaunchID == nil && stderrors.Is(err, errors.ErrNoActiveLaunch) {
+		return &ProjectPage{Items: []*entities.Project{}}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if projects == nil {
-		return []*entities.Project{}, nil
+	var categoryID *uuid.UUID
+	if listFilter.Category != "" {
+		category, err := s.categoryRepo.GetBySlug(ctx, listFilter.Category)
+		if err != nil {
+			return nil, err
+		}
+		categoryID = &category.ID
+	}
+
+	filter, err := listFilter.projectFilter(launch.ID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.projectRepo.GetPageByFilter(ctx, filter, params.Cursor, params.Limit+1)
+	if err != nil {
+		return nil, err
 	}
 
-	return projects, nil
+	return pagination.NewPage(projects, params.Limit, func(project *entities.Project) pagination.Cursor {
+		return pagination.Cursor{Score: project.Rating, Time: project.CreatedAt, ID: project.ID}
+	}), nil
+}
+
+// GetActiveLaunchProjects возвращает проекты текущего запуска в порядке рейтинга
+func (s *ProjectService) GetActiveLaunchProjects(ctx context.Context) ([]*entities.Project, error) {
+	return s.leaderboard.GetOrLoad("active", func() ([]*entities.Project, error) {
+		// Между окончанием запуска и работой cron активного запуска нет
+		activeLaunch, err := s.launchRepo.GetActive(ctx)
+		if stderrors.Is(err, errors.ErrNoActiveLaunch) {
+			return []*entities.Project{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		projects, err := s.projectRepo.GetByLaunchIDOrderedByRating(ctx, activeLaunch.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if projects == nil {
+			return []*entities.Project{}, nil
+		}
+
+		return projects, nil
+	})
 }
 
 func (s *ProjectService) Vote(ctx context.Context, userID, projectID uuid.UUID) error {
 	// Получаем активный запуск
-	activeLaunch, err := s.launchRepo.GetActive(ctx)
+	activeLaunch, err := s.launchService.EnsureActive(ctx)
 	if err != nil {
 		return err
 	}
@@ -107,7 +708,7 @@ func (s *ProjectService) Vote(ctx context.Context, userID, projectID uuid.UUID)
 // RemoveVote удаляет лайк пользователя за проект
 func (s *ProjectService) RemoveVote(ctx context.Context, userID, projectID uuid.UUID) error {
 	// Получаем активный запуск
-	activeLaunch, err := s.launchRepo.GetActive(ctx)
+	activeLaunch, err := s.launchService.EnsureActive(ctx)
 	if err != nil {
 		return err
 	}
@@ -128,28 +729,63 @@ func (s *ProjectService) RemoveVote(ctx context.Context, userID, projectID uuid.
 	return s.updateProjectRating(ctx, projectID)
 }
 
+// RecalculateRating пересчитывает рейтинг проекта по текущим голосам
+func (s *ProjectService) RecalculateRating(ctx context.Context, projectID uuid.UUID) error {
+	return s.updateProjectRating(ctx, projectID)
+}
+
 func (s *ProjectService) updateProjectRating(ctx context.Context, projectID uuid.UUID) error {
-	upvotes, err := s.voteRepo.GetProjectVotes(ctx, projectID)
+	previousUpvotes, err := s.projectRepo.UpdateRating(ctx, projectID)
 	if err != nil {
 		return err
 	}
+	s.InvalidateListings()
 
 	project, err := s.projectRepo.GetByID(ctx, projectID)
 	if err != nil {
 		return err
 	}
 
-	project.Upvotes = upvotes
-	project.Rating = upvotes
-	project.UpdatedAt = time.Now()
+	s.notifications.NotifyVotes(ctx, project, previousUpvotes)
+	s.webhooks.EmitProjectVoted(ctx, project, previousUpvotes)
 
-	return s.projectRepo.Update(ctx, project)
+	return nil
 }
 
-func (s *ProjectService) GetUserVotes(ctx context.Context, userID uuid.UUID) ([]*entities.Vote, error) {
-	return s.voteRepo.GetByUserID(ctx, userID)
+// ratingPlaces возвращает места проектов, упорядоченных по рейтингу.
+// Проекты с одинаковым рейтингом делят место.
+func ratingPlaces(projects []*entities.Project) []int {
+	places := make([]int, len(projects))
+	for i, project := range projects {
+		if i == 0 || project.Rating != projects[i-1].Rating {
+			places[i] = i + 1
+		} else {
+			places[i] = places[i-1]
+		}
+	}
+	return places
 }
 
-func (s *ProjectService) GetUserProjects(ctx context.Context, userID uuid.UUID) ([]*entities.Project, error) {
-	return s.projectRepo.GetByUserID(ctx, userID)
+// GetUserVotes возвращает страницу голосов пользователя, начиная с последних
+func (s *ProjectService) GetUserVotes(ctx context.Context, userID uuid.UUID, params pagination.Params) (*VotePage, error) {
+	votes, err := s.voteRepo.GetPageByUserID(ctx, userID, params.Cursor, params.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return pagination.NewPage(votes, params.Limit, func(vote *entities.Vote) pagination.Cursor {
+		return pagination.Cursor{Time: vote.CreatedAt, ID: vote.ID}
+	}), nil
+}
+
+// GetUserProjects возвращает страницу проектов пользователя, начиная с последних
+func (s *ProjectService) GetUserProjects(ctx context.Context, userID uuid.UUID, params pagination.Params) (*ProjectPage, error) {
+	projects, err := s.projectRepo.GetPageByUserID(ctx, userID, params.Cursor, params.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return pagination.NewPage(projects, params.Limit, func(project *entities.Project) pagination.Cursor {
+		return pagination.Cursor{Time: project.CreatedAt, ID: project.ID}
+	}), nil
 }
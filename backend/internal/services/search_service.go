@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
+	"strings"
+)
+
+// SearchService поиск по проектам, мейкерам и комментариям
+type SearchService struct {
+	searchRepo repository.SearchRepository
+}
+
+func NewSearchService(searchRepo repository.SearchRepository) *SearchService {
+	return &SearchService{searchRepo: searchRepo}
+}
+
+// Search ищет в указанных разделах (все разделы, если scopes пуст).
+// Limit ограничивает количество результатов в каждом разделе.
+func (s *SearchService) Search(
+	ctx context.Context,
+	filter entities.SearchFilter,
+	scopes []entities.SearchScope,
+) (*entities.SearchResults, error) {
+	filter.Query = strings.Join(strings.Fields(filter.Query), " ")
+
+	v := validation.New()
+	if v.Required("q", filter.Query) && v.MinLength("q", filter.Query, validation.SearchQueryMinLength) {
+		v.MaxLength("q", filter.Query, validation.SearchQueryMaxLength)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		v.Add("to", validation.CodeInvalidFormat, "end of the period must be after its start")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(scopes) == 0 {
+		scopes = []entities.SearchScope{entities.SearchProjects, entities.SearchMakers, entities.SearchComments}
+	}
+
+	results := &entities.SearchResults{Query: filter.Query}
+	for _, scope := range scopes {
+		switch scope {
+		case entities.SearchProjects:
+			projects, err := s.searchRepo.SearchProjects(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+			results.Projects = append([]*entities.ProjectSearchResult{}, projects...)
+		case entities.SearchMakers:
+			makers, err := s.searchRepo.SearchMakers(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+			for _, maker := range makers {
+				maker.DisplayName = displayName(maker.FirstName, maker.LastName, maker.Username)
+			}
+			results.Makers = append([]*entities.MakerSearchResult{}, makers...)
+		case entities.SearchComments:
+			comments, err := s.searchRepo.SearchComments(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+			results.Comments = append([]*entities.CommentSearchResult{}, comments...)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,435 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
+	"startup-scout/pkg/mail"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+//go:embed templates/layout.html templates/team/*
+var teamTemplates embed.FS
+
+const defaultInvitationTTL = 7 * 24 * time.Hour
+
+type TeamConfig struct {
+	From          string
+	SiteURL       string
+	InvitationTTL time.Duration
+}
+
+// TeamService управляет командами проектов: приглашает соавторов по username
+// или ссылкой на email, принимает ответы на приглашения и проверяет права
+type TeamService struct {
+	teamRepo      repository.TeamRepository
+	projectRepo   repository.ProjectRepository
+	userRepo      repository.UserRepository
+	notifications *NotificationService
+	sender        mail.Sender
+	html          *htmltemplate.Template
+	text          *texttemplate.Template
+	config        TeamConfig
+	logger        *zap.Logger
+}
+
+func NewTeamService(
+	teamRepo repository.TeamRepository,
+	projectRepo repository.ProjectRepository,
+	userRepo repository.UserRepository,
+	notifications *NotificationService,
+	sender mail.Sender,
+	config TeamConfig,
+	logger *zap.Logger,
+) *TeamService {
+	if config.InvitationTTL <= 0 {
+		config.InvitationTTL = defaultInvitationTTL
+	}
+	if config.SiteURL == "" {
+		config.SiteURL = defaultSiteURL
+	}
+	config.SiteURL = strings.TrimRight(config.SiteURL, "/")
+
+	return &TeamService{
+		teamRepo:      teamRepo,
+		projectRepo:   projectRepo,
+		userRepo:      userRepo,
+		notifications: notifications,
+		sender:        sender,
+		html:          htmltemplate.Must(htmltemplate.ParseFS(teamTemplates, "templates/layout.html", "templates/team/*.html")),
+		text:          texttemplate.Must(texttemplate.ParseFS(teamTemplates, "templates/team/*.txt")),
+		config:        config,
+		logger:        logger,
+	}
+}
+
+type invitationEmailData struct {
+	Subject     string
+	SiteURL     string
+	ProjectName string
+	Inviter     string
+	AcceptURL   string
+	ExpiresAt   time.Time
+}
+
+// GetMembers возвращает команду проекта: владельца и соавторов
+func (s *TeamService) GetMembers(ctx context.Context, projectID uuid.UUID) ([]*entities.ProjectMember, error) {
+	members, err := s.teamRepo.GetMembers(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		member.DisplayName = displayName(member.FirstName, member.LastName, member.Username)
+	}
+
+	return members, nil
+}
+
+// Invite приглашает в команду проекта зарегистрированного пользователя по username
+// или человека по email (письмо со ссылкой). Приглашать может только владелец.
+// Приглашение по email не связывается с аккаунтом до принятия.
+func (s *TeamService) Invite(
+	ctx context.Context,
+	inviterID, projectID uuid.UUID,
+	username, email string,
+) (*entities.ProjectInvitation, error) {
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	project, err := s.requireOwner(ctx, inviterID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	v := validation.New()
+	switch {
+	case username == "" && email == "":
+		v.Add("username", validation.CodeRequired, "username or email is required")
+	case username != "" && email != "":
+		v.Add("email", validation.CodeInvalidChoice, "specify either username or email, not both")
+	case email != "":
+		v.Email("email", email)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &entities.ProjectInvitation{
+		ProjectID:   projectID,
+		InviterID:   &inviterID,
+		Role:        entities.RoleMaker,
+		Status:      entities.InvitationPending,
+		ExpiresAt:   now.Add(s.config.InvitationTTL),
+		CreatedAt:   now,
+		ProjectName: project.Name,
+	}
+
+	var token string
+	if username != "" {
+		invitee, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil && !stderrors.Is(err, errors.ErrUserNotFound) {
+			return nil, err
+		}
+		if err != nil || invitee.DeletedAt != nil {
+			return nil, validation.Errors{{
+				Field:   "username",
+				Code:    validation.CodeNotFound,
+				Message: "user not found",
+			}}
+		}
+		invitation.InviteeID = &invitee.ID
+	} else {
+		token, err = newsletterToken()
+		if err != nil {
+			return nil, err
+		}
+		// Аккаунт по адресу не ищем: владелец не должен узнать, зарегистрирован ли
+		// человек и под каким именем. Приглашение закрепится за тем, кто примет его по ссылке.
+		invitation.Email = &email
+		invitation.TokenHash.String = hashInvitationToken(token)
+		invitation.TokenHash.Valid = true
+	}
+
+	if invitation.InviteeID != nil {
+		if *invitation.InviteeID == inviterID {
+			return nil, errors.ErrAlreadyMember
+		}
+		role, err := s.teamRepo.GetRole(ctx, projectID, *invitation.InviteeID)
+		if err != nil {
+			return nil, err
+		}
+		if role != "" {
+			return nil, errors.ErrAlreadyMember
+		}
+	}
+
+	if err := s.teamRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	if inviter, err := s.userRepo.GetByID(ctx, inviterID); err == nil {
+		invitation.InviterUsername = &inviter.Username
+	}
+
+	if token != "" {
+		if err := s.sendInvitation(ctx, invitation, token); err != nil {
+			// Без письма приглашение по email принять невозможно: отзываем его,
+			// чтобы владелец мог отправить приглашение повторно
+			if _, revokeErr := s.teamRepo.SetInvitationStatus(ctx, invitation.ID, entities.InvitationRevoked, time.Now()); revokeErr != nil {
+				s.logger.Error("failed to revoke unsent invitation", zap.Error(revokeErr),
+					zap.String("invitation_id", invitation.ID.String()))
+			}
+			return nil, err
+		}
+	}
+
+	if invitation.InviteeID != nil {
+		s.notifications.NotifyProjectInvitation(ctx, invitation)
+	}
+
+	return invitation, nil
+}
+
+func (s *TeamService) sendInvitation(ctx context.Context, invitation *entities.ProjectInvitation, token string) error {
+	data := &invitationEmailData{
+		Subject:     fmt.Sprintf("Приглашение в команду проекта «%s»", invitation.ProjectName),
+		SiteURL:     s.config.SiteURL,
+		ProjectName: invitation.ProjectName,
+		AcceptURL:   s.config.SiteURL + "/invitations/accept?token=" + url.QueryEscape(token),
+		ExpiresAt:   invitation.ExpiresAt,
+	}
+	if invitation.InviterUsername != nil {
+		data.Inviter = *invitation.InviterUsername
+	}
+
+	var html bytes.Buffer
+	if err := s.html.ExecuteTemplate(&html, "invitation.html", data); err != nil {
+		return fmt.Errorf("failed to render invitation email: %w", err)
+	}
+
+	var text bytes.Buffer
+	if err := s.text.ExecuteTemplate(&text, "invitation.txt", data); err != nil {
+		return fmt.Errorf("failed to render invitation email: %w", err)
+	}
+
+	return s.sender.Send(ctx, &mail.Message{
+		From:    s.config.From,
+		To:      *invitation.Email,
+		Subject: data.Subject,
+		HTML:    html.String(),
+		Text:    text.String(),
+	})
+}
+
+// ProjectInvitations возвращает ожидающие ответа приглашения проекта (только для владельца)
+func (s *TeamService) ProjectInvitations(ctx context.Context, userID, projectID uuid.UUID) ([]*entities.ProjectInvitation, error) {
+	if _, err := s.requireOwner(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	return s.teamRepo.GetPendingByProject(ctx, projectID)
+}
+
+// RevokeInvitation отзывает приглашение проекта (только для владельца)
+func (s *TeamService) RevokeInvitation(ctx context.Context, userID, projectID, invitationID uuid.UUID) error {
+	if _, err := s.requireOwner(ctx, userID, projectID); err != nil {
+		return err
+	}
+
+	invitation, err := s.teamRepo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.ProjectID != projectID {
+		return errors.ErrInvitationNotFound
+	}
+
+	revoked, err := s.teamRepo.SetInvitationStatus(ctx, invitationID, entities.InvitationRevoked, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.ErrInvitationNotActive
+	}
+
+	return nil
+}
+
+// UserInvitations возвращает действующие приглашения пользователя
+func (s *TeamService) UserInvitations(ctx context.Context, userID uuid.UUID) ([]*entities.ProjectInvitation, error) {
+	return s.teamRepo.GetPendingByInvitee(ctx, userID, time.Now())
+}
+
+// Accept принимает адресованное пользователю приглашение
+func (s *TeamService) Accept(ctx context.Context, userID, invitationID uuid.UUID) error {
+	invitation, err := s.inviteeInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+
+	return s.accept(ctx, userID, invitation)
+}
+
+// AcceptByToken принимает приглашение по ссылке из письма. Ссылку можно открыть
+// под любым аккаунтом, кроме случая, когда приглашение уже привязано к другому пользователю.
+func (s *TeamService) AcceptByToken(ctx context.Context, userID uuid.UUID, token string) (*entities.ProjectInvitation, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, errors.ErrInvitationNotFound
+	}
+
+	invitation, err := s.teamRepo.GetInvitationByTokenHash(ctx, hashInvitationToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if invitation.InviteeID != nil && *invitation.InviteeID != userID {
+		return nil, errors.ErrInvitationNotFound
+	}
+
+	if err := s.accept(ctx, userID, invitation); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (s *TeamService) accept(ctx context.Context, userID uuid.UUID, invitation *entities.ProjectInvitation) error {
+	now := time.Now()
+	if invitation.Status != entities.InvitationPending || invitation.Expired(now) {
+		return errors.ErrInvitationNotActive
+	}
+
+	role, err := s.teamRepo.GetRole(ctx, invitation.ProjectID, userID)
+	if err != nil {
+		return err
+	}
+	if role != "" {
+		return errors.ErrAlreadyMember
+	}
+
+	accepted, err := s.teamRepo.AcceptInvitation(ctx, invitation, userID, now)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return errors.ErrInvitationNotActive
+	}
+
+	invitation.Status = entities.InvitationAccepted
+	invitation.InviteeID = &userID
+	invitation.RespondedAt = &now
+	return nil
+}
+
+// Decline отклоняет адресованное пользователю приглашение
+func (s *TeamService) Decline(ctx context.Context, userID, invitationID uuid.UUID) error {
+	invitation, err := s.inviteeInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.Status != entities.InvitationPending {
+		return errors.ErrInvitationNotActive
+	}
+
+	declined, err := s.teamRepo.SetInvitationStatus(ctx, invitationID, entities.InvitationDeclined, time.Now())
+	if err != nil {
+		return err
+	}
+	if !declined {
+		return errors.ErrInvitationNotActive
+	}
+
+	return nil
+}
+
+// inviteeInvitation загружает приглашение, адресованное пользователю; чужие
+// приглашения неотличимы от несуществующих
+func (s *TeamService) inviteeInvitation(ctx context.Context, userID, invitationID uuid.UUID) (*entities.ProjectInvitation, error) {
+	invitation, err := s.teamRepo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.InviteeID == nil || *invitation.InviteeID != userID {
+		return nil, errors.ErrInvitationNotFound
+	}
+
+	return invitation, nil
+}
+
+// RemoveMember исключает соавтора из команды. Владелец может исключить любого
+// соавтора, соавтор - только выйти из команды сам. Владельца исключить нельзя.
+func (s *TeamService) RemoveMember(ctx context.Context, userID, projectID, memberID uuid.UUID) error {
+	role, err := s.teamRepo.GetRole(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case role == "":
+		return errors.ErrNotProjectMember
+	case role != entities.RoleOwner && memberID != userID:
+		return errors.ErrNotProjectOwner
+	}
+
+	memberRole, err := s.teamRepo.GetRole(ctx, projectID, memberID)
+	if err != nil {
+		return err
+	}
+	switch memberRole {
+	case "":
+		return errors.ErrNotProjectMember
+	case entities.RoleOwner:
+		return errors.ErrCannotRemoveOwner
+	}
+
+	removed, err := s.teamRepo.RemoveMember(ctx, projectID, memberID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.ErrNotProjectMember
+	}
+
+	return nil
+}
+
+// requireOwner загружает проект и проверяет, что пользователь - его владелец
+func (s *TeamService) requireOwner(ctx context.Context, userID, projectID uuid.UUID) (*entities.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.teamRepo.GetRole(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role != entities.RoleOwner {
+		return nil, errors.ErrNotProjectOwner
+	}
+
+	return project, nil
+}
+
+// hashInvitationToken в базе хранится только хеш токена из ссылки
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
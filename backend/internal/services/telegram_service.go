@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/clients"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTelegramBatchSize      = 20
+	defaultTelegramMaxAttempts    = 8
+	defaultTelegramRetryBaseDelay = 30 * time.Second
+	defaultTelegramRetryMaxDelay  = 6 * time.Hour
+	defaultSiteURL                = "https://startup-scout.ru"
+
+	// telegramOutboxLease время, на которое забранное сообщение скрыто от других
+	// обработчиков. Если процесс упадет во время отправки, сообщение вернется в очередь.
+	telegramOutboxLease = 2 * time.Minute
+)
+
+// TelegramSender отправляет сообщения через Bot API. Реализуется clients.TelegramClient;
+// в тестах подменяется заглушкой.
+type TelegramSender interface {
+	SendMessage(ctx context.Context, chatID int64, text string) error
+}
+
+type TelegramConfig struct {
+	SiteURL        string
+	BatchSize      int
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// TelegramService канал уведомлений через Telegram-бота. Уведомления сначала
+// попадают в outbox-таблицу, а затем отправляются фоновым обработчиком с повторами.
+type TelegramService struct {
+	outboxRepo repository.TelegramOutboxRepository
+	sender     TelegramSender
+	config     TelegramConfig
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+func NewTelegramService(
+	outboxRepo repository.TelegramOutboxRepository,
+	sender TelegramSender,
+	config TelegramConfig,
+	logger *zap.Logger,
+) *TelegramService {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultTelegramBatchSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultTelegramMaxAttempts
+	}
+	if config.RetryBaseDelay <= 0 {
+		config.RetryBaseDelay = defaultTelegramRetryBaseDelay
+	}
+	if config.RetryMaxDelay <= 0 {
+		config.RetryMaxDelay = defaultTelegramRetryMaxDelay
+	}
+	if config.SiteURL == "" {
+		config.SiteURL = defaultSiteURL
+	}
+	config.SiteURL = strings.TrimRight(config.SiteURL, "/")
+
+	return &TelegramService{
+		outboxRepo: outboxRepo,
+		sender:     sender,
+		config:     config,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Deliver ставит уведомление в очередь, если у получателя привязан Telegram
+// и включен этот канал
+func (s *TelegramService) Deliver(ctx context.Context, recipient *entities.User, notification *entities.Notification) error {
+	if recipient.TelegramID == nil || !recipient.NotificationPreferences.Telegram {
+		return nil
+	}
+
+	text := s.formatMessage(notification)
+	if text == "" {
+		return nil
+	}
+
+	now := s.now()
+	message := &entities.TelegramMessage{
+		UserID:        recipient.ID,
+		ChatID:        *recipient.TelegramID,
+		Text:          text,
+		Status:        entities.OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if notification.ID != uuid.Nil {
+		message.NotificationID = &notification.ID
+	}
+
+	return s.outboxRepo.Enqueue(ctx, message)
+}
+
+// Run обрабатывает очередь с заданным интервалом до отмены контекста
+func (s *TelegramService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				processed, err := s.ProcessOutbox(ctx)
+				if err != nil {
+					s.logger.Error("failed to process telegram outbox", zap.Error(err))
+					break
+				}
+				// Полная пачка означает, что в очереди могут оставаться сообщения
+				if processed < s.config.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// ProcessOutbox отправляет одну пачку готовых сообщений и возвращает их количество
+func (s *TelegramService) ProcessOutbox(ctx context.Context) (int, error) {
+	messages, err := s.outboxRepo.ClaimDue(ctx, s.now(), telegramOutboxLease, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, message := range messages {
+		if err := s.send(ctx, message); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(messages), nil
+}
+
+// send отправляет сообщение и записывает результат. Возвращает только ошибки
+// обновления очереди; ошибки Bot API превращаются в повтор или отказ.
+func (s *TelegramService) send(ctx context.Context, message *entities.TelegramMessage) error {
+	sendErr := s.sender.SendMessage(ctx, message.ChatID, message.Text)
+	if sendErr == nil {
+		return s.outboxRepo.MarkSent(ctx, message.ID, s.now())
+	}
+
+	var apiErr *clients.TelegramAPIError
+	permanent := stderrors.As(sendErr, &apiErr) && !apiErr.Temporary()
+
+	if permanent || message.Attempts >= s.config.MaxAttempts {
+		s.logger.Warn("telegram message dropped",
+			zap.String("message_id", message.ID.String()),
+			zap.Int("attempts", message.Attempts),
+			zap.Error(sendErr))
+		return s.outboxRepo.MarkFailed(ctx, message.ID, sendErr.Error())
+	}
+
+	delay := retryBackoff(s.config.RetryBaseDelay, s.config.RetryMaxDelay, message.Attempts)
+	if apiErr != nil && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+
+	return s.outboxRepo.Reschedule(ctx, message.ID, s.now().Add(delay), sendErr.Error())
+}
+
+// retryBackoff экспоненциальная задержка перед повтором: base, 2*base, 4*base, ... но не больше max
+func retryBackoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
+
+func (s *TelegramService) formatMessage(notification *entities.Notification) string {
+	projectName := valueOrEmpty(notification.ProjectName)
+	launchName := valueOrEmpty(notification.LaunchName)
+
+	var text string
+	switch notification.Type {
+	case entities.NotificationComment:
+		author := "пользователя"
+		if notification.ActorUsername != nil {
+			author = "@" + *notification.ActorUsername
+		}
+		text = fmt.Sprintf("💬 Новый комментарий к проекту «%s» от %s", projectName, author)
+		if notification.Content != nil {
+			text += ":\n\n" + truncateText(*notification.Content, 300)
+		}
+	case entities.NotificationVoteMilestone:
+		if notification.Milestone == nil {
+			return ""
+		}
+		text = fmt.Sprintf("🔥 Проект «%s» набрал %d ❤", projectName, *notification.Milestone)
+	case entities.NotificationLaunchResult:
+		if notification.Place == nil {
+			return ""
+		}
+		text = fmt.Sprintf("🏆 %s завершен: ваш проект «%s» занял %d место", launchName, projectName, *notification.Place)
+	case entities.NotificationLaunchStarted:
+		text = fmt.Sprintf("🚀 Начался %s. Публикуйте проекты и голосуйте за лучшие!", launchName)
+	case entities.NotificationProjectInvitation:
+		inviter := "Мейкер"
+		if notification.ActorUsername != nil {
+			inviter = "@" + *notification.ActorUsername
+		}
+		text = fmt.Sprintf("🤝 %s приглашает вас в команду проекта «%s»", inviter, projectName)
+	default:
+		return ""
+	}
+
+	if notification.ProjectID != nil {
+		text += "\n\n" + s.config.SiteURL + "/project/" + notification.ProjectID.String()
+	} else {
+		text += "\n\n" + s.config.SiteURL
+	}
+
+	return text
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// truncateText обрезает текст до limit символов (не байт)
+func truncateText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"startup-scout/internal/entities"
+	"startup-scout/pkg/clients"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memoryOutbox хранит очередь в памяти с той же семантикой, что и SQL-реализация:
+// ClaimDue увеличивает attempts и скрывает сообщение до истечения аренды
+type memoryOutbox struct {
+	mu       sync.Mutex
+	messages map[uuid.UUID]*entities.TelegramMessage
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{messages: make(map[uuid.UUID]*entities.TelegramMessage)}
+}
+
+func (o *memoryOutbox) Enqueue(ctx context.Context, message *entities.TelegramMessage) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	message.ID = uuid.New()
+	copied := *message
+	o.messages[message.ID] = &copied
+	return nil
+}
+
+func (o *memoryOutbox) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.TelegramMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var due []*entities.TelegramMessage
+	for _, message := range o.messages {
+		if message.Status == entities.OutboxStatusPending && !message.NextAttemptAt.After(now) {
+			due = append(due, message)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*entities.TelegramMessage, len(due))
+	for i, message := range due {
+		message.Attempts++
+		message.NextAttemptAt = now.Add(lease)
+		copied := *message
+		claimed[i] = &copied
+	}
+	return claimed, nil
+}
+
+func (o *memoryOutbox) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.messages[id].Status = entities.OutboxStatusSent
+	o.messages[id].SentAt = &sentAt
+	o.messages[id].LastError = sql.NullString{}
+	return nil
+}
+
+func (o *memoryOutbox) Reschedule(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.messages[id].NextAttemptAt = nextAttemptAt
+	o.messages[id].LastError = sql.NullString{String: lastError, Valid: true}
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.messages[id].Status = entities.OutboxStatusFailed
+	o.messages[id].LastError = sql.NullString{String: lastError, Valid: true}
+	return nil
+}
+
+func (o *memoryOutbox) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for id, message := range o.messages {
+		if message.UserID == userID {
+			delete(o.messages, id)
+		}
+	}
+	return nil
+}
+
+func (o *memoryOutbox) get(id uuid.UUID) entities.TelegramMessage {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return *o.messages[id]
+}
+
+// fakeBotAPI заглушка Bot API, отвечающая по очереди заданными ответами;
+// последний ответ повторяется
+type fakeBotAPI struct {
+	mu        sync.Mutex
+	responses []botResponse
+	requests  int
+}
+
+type botResponse struct {
+	status int
+	body   string
+}
+
+var botOK = botResponse{http.StatusOK, `{"ok":true,"result":{}}`}
+
+func (b *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	response := b.responses[len(b.responses)-1]
+	if b.requests < len(b.responses) {
+		response = b.responses[b.requests]
+	}
+	b.requests++
+	b.mu.Unlock()
+
+	w.WriteHeader(response.status)
+	w.Write([]byte(response.body))
+}
+
+func (b *fakeBotAPI) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.requests
+}
+
+type telegramFixture struct {
+	service *TelegramService
+	outbox  *memoryOutbox
+	bot     *fakeBotAPI
+	now     time.Time
+}
+
+func newTelegramFixture(t *testing.T, config TelegramConfig, responses ...botResponse) *telegramFixture {
+	t.Helper()
+
+	bot := &fakeBotAPI{responses: responses}
+	server := httptest.NewServer(bot)
+	t.Cleanup(server.Close)
+
+	f := &telegramFixture{
+		outbox: newMemoryOutbox(),
+		bot:    bot,
+		now:    time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+	}
+	f.service = NewTelegramService(f.outbox, clients.NewTelegramClient("token", server.URL, time.Second), config, zap.NewNop())
+	f.service.now = func() time.Time { return f.now }
+	return f
+}
+
+// enqueue ставит в очередь уведомление о старте запуска и возвращает ID сообщения
+func (f *telegramFixture) enqueue(t *testing.T) uuid.UUID {
+	t.Helper()
+
+	chatID := int64(1001)
+	launchName := "Weekly Launch #10"
+	recipient := &entities.User{
+		ID:                      uuid.New(),
+		TelegramID:              &chatID,
+		NotificationPreferences: entities.NotificationPreferences{Telegram: true},
+	}
+	notification := &entities.Notification{Type: entities.NotificationLaunchStarted, LaunchName: &launchName}
+
+	if err := f.service.Deliver(context.Background(), recipient, notification); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	for id := range f.outbox.messages {
+		return id
+	}
+	t.Fatal("message was not enqueued")
+	return uuid.Nil
+}
+
+func (f *telegramFixture) process(t *testing.T) int {
+	t.Helper()
+
+	processed, err := f.service.ProcessOutbox(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessOutbox: %v", err)
+	}
+	return processed
+}
+
+func TestTelegramOutboxDeliversMessage(t *testing.T) {
+	f := newTelegramFixture(t, TelegramConfig{}, botOK)
+	id := f.enqueue(t)
+
+	if processed := f.process(t); processed != 1 {
+		t.Fatalf("processed = %d, want 1", processed)
+	}
+
+	message := f.outbox.get(id)
+	if message.Status != entities.OutboxStatusSent || message.SentAt == nil {
+		t.Errorf("status = %s, sent_at = %v, want sent", message.Status, message.SentAt)
+	}
+	if message.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", message.Attempts)
+	}
+
+	// Отправленное сообщение больше не забирается
+	if processed := f.process(t); processed != 0 || f.bot.count() != 1 {
+		t.Errorf("processed = %d, requests = %d after delivery", processed, f.bot.count())
+	}
+}
+
+func TestTelegramOutboxHonoursRetryAfter(t *testing.T) {
+	f := newTelegramFixture(t, TelegramConfig{RetryBaseDelay: 10 * time.Second},
+		botResponse{http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":90}}`},
+		botOK,
+	)
+	id := f.enqueue(t)
+
+	f.process(t)
+
+	message := f.outbox.get(id)
+	if message.Status != entities.OutboxStatusPending {
+		t.Fatalf("status = %s, want pending", message.Status)
+	}
+	// retry_after больше базовой задержки, поэтому ждем столько, сколько просит Telegram
+	if want := f.now.Add(90 * time.Second); !message.NextAttemptAt.Equal(want) {
+		t.Errorf("next_attempt_at = %v, want %v", message.NextAttemptAt, want)
+	}
+	if !message.LastError.Valid {
+		t.Error("last_error is not recorded")
+	}
+
+	f.now = f.now.Add(89 * time.Second)
+	if processed := f.process(t); processed != 0 {
+		t.Fatalf("message retried before retry_after: processed = %d", processed)
+	}
+
+	f.now = f.now.Add(time.Second)
+	f.process(t)
+
+	message = f.outbox.get(id)
+	if message.Status != entities.OutboxStatusSent || message.Attempts != 2 {
+		t.Errorf("status = %s, attempts = %d, want sent after 2 attempts", message.Status, message.Attempts)
+	}
+}
+
+func TestTelegramOutboxRedeliversAfterLeaseExpiry(t *testing.T) {
+	f := newTelegramFixture(t, TelegramConfig{}, botOK)
+	id := f.enqueue(t)
+
+	// Другой обработчик забрал сообщение и упал, не записав результат
+	claimed, err := f.outbox.ClaimDue(context.Background(), f.now, telegramOutboxLease, 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimDue = %d messages, %v", len(claimed), err)
+	}
+
+	if processed := f.process(t); processed != 0 || f.bot.count() != 0 {
+		t.Fatalf("leased message was sent again: processed = %d, requests = %d", processed, f.bot.count())
+	}
+
+	f.now = f.now.Add(telegramOutboxLease)
+	if processed := f.process(t); processed != 1 {
+		t.Fatalf("processed = %d after lease expiry, want 1", processed)
+	}
+
+	message := f.outbox.get(id)
+	if message.Status != entities.OutboxStatusSent || message.Attempts != 2 {
+		t.Errorf("status = %s, attempts = %d, want sent after 2 attempts", message.Status, message.Attempts)
+	}
+}
+
+func TestTelegramOutboxGivesUpAfterMaxAttempts(t *testing.T) {
+	config := TelegramConfig{MaxAttempts: 3, RetryBaseDelay: time.Second, RetryMaxDelay: time.Minute}
+	f := newTelegramFixture(t, config, botResponse{http.StatusInternalServerError, `{"ok":false,"description":"Internal Server Error"}`})
+	id := f.enqueue(t)
+
+	for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
+		if processed := f.process(t); processed != 1 {
+			t.Fatalf("attempt %d: processed = %d, want 1", attempt, processed)
+		}
+		f.now = f.now.Add(time.Minute)
+	}
+
+	message := f.outbox.get(id)
+	if message.Status != entities.OutboxStatusFailed {
+		t.Fatalf("status = %s after %d attempts, want failed", message.Status, config.MaxAttempts)
+	}
+	if f.bot.count() != config.MaxAttempts {
+		t.Errorf("requests = %d, want %d", f.bot.count(), config.MaxAttempts)
+	}
+
+	f.now = f.now.Add(time.Hour)
+	if processed := f.process(t); processed != 0 {
+		t.Errorf("failed message was claimed again: processed = %d", processed)
+	}
+}
+
+func TestTelegramOutboxDropsPermanentErrors(t *testing.T) {
+	f := newTelegramFixture(t, TelegramConfig{},
+		botResponse{http.StatusForbidden, `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`})
+	id := f.enqueue(t)
+
+	f.process(t)
+
+	message := f.outbox.get(id)
+	if message.Status != entities.OutboxStatusFailed || message.Attempts != 1 {
+		t.Errorf("status = %s, attempts = %d, want failed after the first attempt", message.Status, message.Attempts)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	base, max := 30*time.Second, 5*time.Minute
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, expected := range want {
+		if got := retryBackoff(base, max, i+1); got != expected {
+			t.Errorf("retryBackoff(attempt %d) = %v, want %v", i+1, got, expected)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/totp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// recoveryCodeCount количество выдаваемых кодов восстановления
+	recoveryCodeCount = 10
+	// totpSkew допустимое расхождение часов в шагах TOTP
+	totpSkew = 1
+	// twoFactorChallengeTTL время на ввод второго фактора после проверки пароля
+	twoFactorChallengeTTL = 5 * time.Minute
+)
+
+type TwoFactorService struct {
+	userRepo         repository.UserRepository
+	recoveryCodeRepo repository.RecoveryCodeRepository
+	challengeRepo    repository.TwoFactorChallengeRepository
+	guard            *LoginGuard
+	issuer           string
+}
+
+func NewTwoFactorService(
+	userRepo repository.UserRepository,
+	recoveryCodeRepo repository.RecoveryCodeRepository,
+	challengeRepo repository.TwoFactorChallengeRepository,
+	guard *LoginGuard,
+	issuer string,
+) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:         userRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		challengeRepo:    challengeRepo,
+		guard:            guard,
+		issuer:           issuer,
+	}
+}
+
+// TwoFactorSetup данные для подключения приложения-аутентификатора
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// BeginEnrollment генерирует новый секрет. 2FA включается только после
+// подтверждения кодом в ConfirmEnrollment.
+func (s *TwoFactorService) BeginEnrollment(ctx context.Context, user *entities.User) (*TwoFactorSetup, error) {
+	if user.TOTPEnabled {
+		return nil, errors.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateTOTP(ctx, user.ID, sql.NullString{String: secret, Valid: true}, false); err != nil {
+		return nil, err
+	}
+
+	account := user.Email
+	if account == "" {
+		account = user.Username
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.issuer, account, secret),
+	}, nil
+}
+
+// ConfirmEnrollment проверяет первый код, включает 2FA и возвращает коды восстановления
+func (s *TwoFactorService) ConfirmEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, errors.ErrTwoFactorAlreadyEnabled
+	}
+	if !user.TOTPSecret.Valid {
+		return nil, errors.ErrTwoFactorNotInitiated
+	}
+
+	if err := s.useTOTPCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateTOTP(ctx, userID, user.TOTPSecret, true); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// StartChallenge выпускает одноразовый вызов второго фактора для входа
+// и возвращает его идентификатор для claim jti и время истечения
+func (s *TwoFactorService) StartChallenge(ctx context.Context, userID uuid.UUID) (uuid.UUID, time.Time, error) {
+	challengeID := uuid.New()
+	expiresAt := time.Now().Add(twoFactorChallengeTTL)
+	if err := s.challengeRepo.Create(ctx, challengeID, userID, expiresAt); err != nil {
+		return uuid.Nil, time.Time{}, err
+	}
+	return challengeID, expiresAt, nil
+}
+
+// VerifyChallenge завершает вход по вызову из StartChallenge. Вызов
+// расходуется только при верном коде, поэтому опечатка не требует нового входа,
+// а повторное использование токена отклоняется с ErrSessionExpired.
+func (s *TwoFactorService) VerifyChallenge(ctx context.Context, challengeID, userID uuid.UUID, code string) error {
+	exists, err := s.challengeRepo.Exists(ctx, challengeID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.ErrSessionExpired
+	}
+
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	consumed, err := s.challengeRepo.Consume(ctx, challengeID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return errors.ErrSessionExpired
+	}
+
+	return nil
+}
+
+// DeleteExpiredChallenges удаляет истекшие вызовы
+func (s *TwoFactorService) DeleteExpiredChallenges(ctx context.Context, now time.Time) (int64, error) {
+	return s.challengeRepo.DeleteExpired(ctx, now)
+}
+
+// Verify проверяет TOTP код или код восстановления при входе.
+// Неудачные попытки учитываются в LoginGuard, чтобы нельзя было перебрать код.
+func (s *TwoFactorService) Verify(ctx context.Context, userID uuid.UUID, code string) error {
+	guardKey := "2fa:" + userID.String()
+
+	if s.guard != nil {
+		if err := s.guard.Check(ctx, guardKey); err != nil {
+			return err
+		}
+	}
+
+	if err := s.verifyCode(ctx, userID, code); err != nil {
+		if err == errors.ErrInvalidTwoFactorCode && s.guard != nil {
+			if guardErr := s.guard.RegisterFailure(ctx, guardKey); guardErr != nil {
+				return fmt.Errorf("failed to register two-factor failure: %w", guardErr)
+			}
+		}
+		return err
+	}
+
+	if s.guard != nil {
+		if err := s.guard.RegisterSuccess(ctx, guardKey); err != nil {
+			return fmt.Errorf("failed to reset two-factor failures: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Disable отключает 2FA после проверки кода
+func (s *TwoFactorService) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	if err := s.recoveryCodeRepo.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.userRepo.UpdateTOTP(ctx, userID, sql.NullString{}, false)
+}
+
+// RegenerateRecoveryCodes выпускает новые коды восстановления, старые перестают действовать
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+// RemainingRecoveryCodes возвращает количество неиспользованных кодов восстановления
+func (s *TwoFactorService) RemainingRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.recoveryCodeRepo.CountUnused(ctx, userID)
+}
+
+func (s *TwoFactorService) verifyCode(ctx context.Context, userID uuid.UUID, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.TOTPEnabled || !user.TOTPSecret.Valid {
+		return errors.ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if err := s.useTOTPCode(ctx, user, code); err != errors.ErrInvalidTwoFactorCode {
+		return err
+	}
+
+	// Пробуем как код восстановления
+	used, err := s.recoveryCodeRepo.Use(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// useTOTPCode проверяет TOTP код и запоминает его шаг. Код того же или более
+// раннего шага, чем последний принятый, отклоняется как повторный.
+func (s *TwoFactorService) useTOTPCode(ctx context.Context, user *entities.User, code string) error {
+	step, ok := totp.MatchStep(user.TOTPSecret.String, code, time.Now(), totpSkew)
+	if !ok {
+		return errors.ErrInvalidTwoFactorCode
+	}
+
+	fresh, err := s.userRepo.UseTOTPStep(ctx, user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return errors.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func (s *TwoFactorService) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashRecoveryCode(code)
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceForUser(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// generateRecoveryCode создает код вида xxxx-xxxx-xxxx-xxxx (80 бит энтропии)
+func generateRecoveryCode() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+
+	encoded := strings.ToLower(base32.StdEncoding.EncodeToString(raw))
+	return encoded[0:4] + "-" + encoded[4:8] + "-" + encoded[8:12] + "-" + encoded[12:16], nil
+}
+
+// hashRecoveryCode нормализует код и возвращает его SHA-256 хеш
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
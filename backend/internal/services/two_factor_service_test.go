@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/pkg/totp"
+
+	"github.com/google/uuid"
+)
+
+// memoryTOTPUsers хранит пользователей в памяти; реализованы только методы,
+// которые использует TwoFactorService
+type memoryTOTPUsers struct {
+	repository.UserRepository
+
+	mu        sync.Mutex
+	users     map[uuid.UUID]*entities.User
+	lastSteps map[uuid.UUID]int64
+}
+
+func (r *memoryTOTPUsers) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *memoryTOTPUsers) UpdateTOTP(ctx context.Context, userID uuid.UUID, secret sql.NullString, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.users[userID].TOTPSecret = secret
+	r.users[userID].TOTPEnabled = enabled
+	return nil
+}
+
+func (r *memoryTOTPUsers) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if last, ok := r.lastSteps[userID]; ok && step <= last {
+		return false, nil
+	}
+	r.lastSteps[userID] = step
+	return true, nil
+}
+
+// memoryRecoveryCodes хранит хеши кодов восстановления и признак использования
+type memoryRecoveryCodes struct {
+	mu    sync.Mutex
+	codes map[uuid.UUID]map[string]bool
+}
+
+func (r *memoryRecoveryCodes) ReplaceForUser(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := make(map[string]bool, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes[hash] = false
+	}
+	r.codes[userID] = codes
+	return nil
+}
+
+func (r *memoryRecoveryCodes) Use(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	used, ok := r.codes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.codes[userID][codeHash] = true
+	return true, nil
+}
+
+func (r *memoryRecoveryCodes) CountUnused(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, used := range r.codes[userID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryRecoveryCodes) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.codes, userID)
+	return nil
+}
+
+type memoryChallenge struct {
+	userID    uuid.UUID
+	expiresAt time.Time
+}
+
+// memoryChallenges хранит вызовы второго фактора с той же семантикой, что и SQL-реализация
+type memoryChallenges struct {
+	mu         sync.Mutex
+	challenges map[uuid.UUID]memoryChallenge
+}
+
+func (r *memoryChallenges) Create(ctx context.Context, challengeID, userID uuid.UUID, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.challenges[challengeID] = memoryChallenge{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (r *memoryChallenges) Exists(ctx context.Context, challengeID, userID uuid.UUID, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	challenge, ok := r.challenges[challengeID]
+	return ok && challenge.userID == userID && now.Before(challenge.expiresAt), nil
+}
+
+func (r *memoryChallenges) Consume(ctx context.Context, challengeID, userID uuid.UUID, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	challenge, ok := r.challenges[challengeID]
+	if !ok || challenge.userID != userID || !now.Before(challenge.expiresAt) {
+		return false, nil
+	}
+	delete(r.challenges, challengeID)
+	return true, nil
+}
+
+func (r *memoryChallenges) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, challenge := range r.challenges {
+		if !now.Before(challenge.expiresAt) {
+			delete(r.challenges, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type twoFactorFixture struct {
+	service    *TwoFactorService
+	users      *memoryTOTPUsers
+	challenges *memoryChallenges
+	user       *entities.User
+}
+
+func newTwoFactorFixture(t *testing.T) *twoFactorFixture {
+	t.Helper()
+
+	user := &entities.User{ID: uuid.New(), Username: "maker", Email: "maker@example.com"}
+	users := &memoryTOTPUsers{
+		users:     map[uuid.UUID]*entities.User{user.ID: user},
+		lastSteps: make(map[uuid.UUID]int64),
+	}
+	challenges := &memoryChallenges{challenges: make(map[uuid.UUID]memoryChallenge)}
+	recoveryCodes := &memoryRecoveryCodes{codes: make(map[uuid.UUID]map[string]bool)}
+
+	return &twoFactorFixture{
+		service:    NewTwoFactorService(users, recoveryCodes, challenges, nil, "Startup Scout"),
+		users:      users,
+		challenges: challenges,
+		user:       user,
+	}
+}
+
+// code возвращает TOTP код пользователя для момента at
+func (f *twoFactorFixture) code(t *testing.T, at time.Time) string {
+	t.Helper()
+
+	code, err := totp.Code(f.users.users[f.user.ID].TOTPSecret.String, at)
+	if err != nil {
+		t.Fatalf("totp.Code: %v", err)
+	}
+	return code
+}
+
+// enroll подключает 2FA кодом текущего шага и возвращает коды восстановления
+func (f *twoFactorFixture) enroll(t *testing.T) []string {
+	t.Helper()
+
+	ctx := context.Background()
+	if _, err := f.service.BeginEnrollment(ctx, f.user); err != nil {
+		t.Fatalf("BeginEnrollment: %v", err)
+	}
+	codes, err := f.service.ConfirmEnrollment(ctx, f.user.ID, f.code(t, time.Now()))
+	if err != nil {
+		t.Fatalf("ConfirmEnrollment: %v", err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("recovery codes = %d, want %d", len(codes), recoveryCodeCount)
+	}
+	return codes
+}
+
+func TestTwoFactorRejectsReplayedTOTPCode(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.service.BeginEnrollment(ctx, f.user); err != nil {
+		t.Fatalf("BeginEnrollment: %v", err)
+	}
+	code := f.code(t, time.Now())
+	if _, err := f.service.ConfirmEnrollment(ctx, f.user.ID, code); err != nil {
+		t.Fatalf("ConfirmEnrollment: %v", err)
+	}
+
+	// Код, подтвердивший подключение, нельзя использовать повторно для входа
+	if err := f.service.Verify(ctx, f.user.ID, code); !stderrors.Is(err, errors.ErrInvalidTwoFactorCode) {
+		t.Errorf("Verify(replayed code) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+
+	// Код предыдущего шага в пределах допуска тоже старше принятого
+	previous := f.code(t, time.Now().Add(-totp.Period*time.Second))
+	if err := f.service.Verify(ctx, f.user.ID, previous); !stderrors.Is(err, errors.ErrInvalidTwoFactorCode) {
+		t.Errorf("Verify(previous step) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+
+	// Код следующего шага новее принятого и проходит один раз
+	next := f.code(t, time.Now().Add(totp.Period*time.Second))
+	if err := f.service.Verify(ctx, f.user.ID, next); err != nil {
+		t.Fatalf("Verify(next step): %v", err)
+	}
+	if err := f.service.Verify(ctx, f.user.ID, next); !stderrors.Is(err, errors.ErrInvalidTwoFactorCode) {
+		t.Errorf("Verify(next step again) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
+
+func TestTwoFactorRecoveryCodesAreSingleUse(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	ctx := context.Background()
+	codes := f.enroll(t)
+
+	if err := f.service.Verify(ctx, f.user.ID, codes[0]); err != nil {
+		t.Fatalf("Verify(recovery code): %v", err)
+	}
+	if err := f.service.Verify(ctx, f.user.ID, codes[0]); !stderrors.Is(err, errors.ErrInvalidTwoFactorCode) {
+		t.Errorf("Verify(used recovery code) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+
+	// Код принимается без дефисов и в верхнем регистре
+	normalized := strings.ToUpper(strings.ReplaceAll(codes[1], "-", ""))
+	if err := f.service.Verify(ctx, f.user.ID, normalized); err != nil {
+		t.Errorf("Verify(normalized recovery code): %v", err)
+	}
+
+	remaining, err := f.service.RemainingRecoveryCodes(ctx, f.user.ID)
+	if err != nil {
+		t.Fatalf("RemainingRecoveryCodes: %v", err)
+	}
+	if remaining != recoveryCodeCount-2 {
+		t.Errorf("remaining recovery codes = %d, want %d", remaining, recoveryCodeCount-2)
+	}
+}
+
+func TestTwoFactorRegenerateInvalidatesOldRecoveryCodes(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	ctx := context.Background()
+	old := f.enroll(t)
+
+	fresh, err := f.service.RegenerateRecoveryCodes(ctx, f.user.ID, old[0])
+	if err != nil {
+		t.Fatalf("RegenerateRecoveryCodes: %v", err)
+	}
+
+	if err := f.service.Verify(ctx, f.user.ID, old[1]); !stderrors.Is(err, errors.ErrInvalidTwoFactorCode) {
+		t.Errorf("Verify(old recovery code) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	if err := f.service.Verify(ctx, f.user.ID, fresh[0]); err != nil {
+		t.Errorf("Verify(new recovery code): %v", err)
+	}
+}
+
+func TestTwoFactorChallengeIsConsumedOnce(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	ctx := context.Background()
+	codes := f.enroll(t)
+
+	challengeID, _, err := f.service.StartChallenge(ctx, f.user.ID)
+	if err != nil {
+		t.Fatalf("StartChallenge: %v", err)
+	}
+
+	// Опечатка не расходует вызов
+	if err := f.service.VerifyChallenge(ctx, challengeID, f.user.ID, "000000"); !stderrors.Is(err, errors.ErrInvalidTwoFactorCode) {
+		t.Fatalf("VerifyChallenge(wrong code) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	if err := f.service.VerifyChallenge(ctx, challengeID, f.user.ID, codes[0]); err != nil {
+		t.Fatalf("VerifyChallenge: %v", err)
+	}
+
+	// Повторное использование токена отклоняется даже с верным кодом
+	if err := f.service.VerifyChallenge(ctx, challengeID, f.user.ID, codes[1]); !stderrors.Is(err, errors.ErrSessionExpired) {
+		t.Errorf("VerifyChallenge(consumed) = %v, want ErrSessionExpired", err)
+	}
+
+	// Отклоненный вызов не тратит код восстановления
+	if err := f.service.Verify(ctx, f.user.ID, codes[1]); err != nil {
+		t.Errorf("Verify(recovery code after rejected challenge): %v", err)
+	}
+}
+
+func TestTwoFactorChallengeRejectsExpiredAndForeign(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	ctx := context.Background()
+	codes := f.enroll(t)
+
+	expiredID := uuid.New()
+	if err := f.challenges.Create(ctx, expiredID, f.user.ID, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := f.service.VerifyChallenge(ctx, expiredID, f.user.ID, codes[0]); !stderrors.Is(err, errors.ErrSessionExpired) {
+		t.Errorf("VerifyChallenge(expired) = %v, want ErrSessionExpired", err)
+	}
+
+	challengeID, _, err := f.service.StartChallenge(ctx, f.user.ID)
+	if err != nil {
+		t.Fatalf("StartChallenge: %v", err)
+	}
+	if err := f.service.VerifyChallenge(ctx, challengeID, uuid.New(), codes[0]); !stderrors.Is(err, errors.ErrSessionExpired) {
+		t.Errorf("VerifyChallenge(other user) = %v, want ErrSessionExpired", err)
+	}
+}
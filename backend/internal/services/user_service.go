@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserService struct {
+	userRepo     repository.UserRepository
+	projectRepo  repository.ProjectRepository
+	commentRepo  repository.CommentRepository
+	followRepo   repository.FollowRepository
+	imageService *ImageService
+}
+
+func NewUserService(
+	userRepo repository.UserRepository,
+	projectRepo repository.ProjectRepository,
+	commentRepo repository.CommentRepository,
+	followRepo repository.FollowRepository,
+	imageService *ImageService,
+) *UserService {
+	return &UserService{
+		userRepo:     userRepo,
+		projectRepo:  projectRepo,
+		commentRepo:  commentRepo,
+		followRepo:   followRepo,
+		imageService: imageService,
+	}
+}
+
+// GetPublicProfile возвращает публичный профиль с учетом настроек приватности.
+// Скрытые профили для посторонних выглядят как несуществующие.
+func (s *UserService) GetPublicProfile(ctx context.Context, username string) (*entities.PublicProfile, error) {
+	user, err := s.userRepo.GetPublicByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.ProfilePublic {
+		return nil, errors.ErrUserNotFound
+	}
+
+	projects, err := s.projectRepo.GetPlacementsByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if projects == nil {
+		projects = []*entities.ProjectPlacement{}
+	}
+
+	stats, err := s.followRepo.GetStats(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &entities.PublicProfile{
+		ID:          user.ID,
+		Username:    user.Username,
+		DisplayName: displayName(user.FirstName, user.LastName, user.Username),
+		Avatar:      user.Avatar,
+		JoinedAt:    user.CreatedAt,
+		Followers:   stats.Followers,
+		Following:   stats.Following,
+		Projects:    projects,
+	}
+
+	if user.ShowTelegram && user.TelegramUsername.Valid {
+		telegram := "https://t.me/" + user.TelegramUsername.String
+		profile.Telegram = &telegram
+	}
+
+	if user.ShowActivity {
+		count, err := s.commentRepo.CountByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		profile.CommentCount = &count
+	}
+
+	return profile, nil
+}
+
+// UpdatePrivacy сохраняет настройки видимости профиля
+func (s *UserService) UpdatePrivacy(ctx context.Context, userID uuid.UUID, settings entities.PrivacySettings) error {
+	return s.userRepo.UpdatePrivacy(ctx, userID, settings)
+}
+
+// displayName собирает отображаемое имя из имени и фамилии, иначе использует username
+func displayName(firstName, lastName, username string) string {
+	name := strings.TrimSpace(firstName + " " + lastName)
+	if name == "" {
+		return username
+	}
+	return name
+}
+
+// UpdateProfile обновляет имя, фамилию и username. Пустые поля не изменяются.
+func (s *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID, firstName, lastName, username string) (*entities.User, error) {
+	firstName = strings.TrimSpace(firstName)
+	lastName = strings.TrimSpace(lastName)
+	username = strings.TrimSpace(username)
+
+	v := validation.New()
+	v.MaxLength("first_name", firstName, validation.PersonNameMaxLength)
+	v.MaxLength("last_name", lastName, validation.PersonNameMaxLength)
+	if username != "" {
+		v.Username("username", username)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if username != "" && username != user.Username {
+		existing, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil && !stderrors.Is(err, errors.ErrUserNotFound) {
+			return nil, err
+		}
+		if err == nil && existing.ID != user.ID {
+			return nil, errors.ErrUsernameExists
+		}
+		user.Username = username
+	}
+	if firstName != "" {
+		user.FirstName = firstName
+	}
+	if lastName != "" {
+		user.LastName = lastName
+	}
+	user.UpdatedAt = time.Now()
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateAvatar устанавливает аватар; допускаются только изображения из нашего
+// хранилища, загруженные самим пользователем
+func (s *UserService) UpdateAvatar(ctx context.Context, userID uuid.UUID, avatar string) error {
+	baseURL := s.imageService.GetConfig().BaseURL
+
+	v := validation.New()
+	if v.Required("avatar", avatar) && v.ImageURL("avatar", avatar, baseURL) {
+		fileName, _ := validation.ImageFileName(avatar, baseURL)
+		usable, err := s.imageService.UsableImages(ctx, userID, uuid.Nil, []string{fileName})
+		if err != nil {
+			return err
+		}
+		if !usable[fileName] {
+			v.Add("avatar", validation.CodeNotOwned, "image was uploaded by another user")
+		}
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdateAvatar(ctx, userID, avatar); err != nil {
+		return err
+	}
+
+	return s.imageService.AttachAvatar(ctx, userID, avatar)
+}
@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"startup-scout/internal/entities"
+	"startup-scout/internal/errors"
+	"startup-scout/internal/pagination"
+	"startup-scout/internal/repository"
+	"startup-scout/internal/validation"
+	"startup-scout/pkg/clients"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWebhookBatchSize      = 20
+	defaultWebhookMaxAttempts    = 10
+	defaultWebhookRetryBaseDelay = 30 * time.Second
+	defaultWebhookRetryMaxDelay  = 12 * time.Hour
+
+	// webhookDeliveryLease время, на которое забранная доставка скрыта от других
+	// обработчиков; должно быть больше таймаута HTTP-клиента
+	webhookDeliveryLease = 2 * time.Minute
+	// webhookLaunchResults сколько лучших проектов передается в launch.finished
+	webhookLaunchResults = 10
+)
+
+// WebhookSender отправляет подписанные события. Реализуется clients.WebhookClient.
+type WebhookSender interface {
+	Send(ctx context.Context, request *clients.WebhookRequest) (*clients.WebhookResponse, error)
+}
+
+type WebhookConfig struct {
+	SiteURL        string
+	BatchSize      int
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// WebhookService управляет подписками внешних систем и доставляет им события.
+// События сначала записываются в журнал доставок, а затем отправляются фоновым
+// обработчиком с повторами, поэтому ошибки подписчиков не влияют на основное действие.
+type WebhookService struct {
+	webhookRepo repository.WebhookRepository
+	projectRepo repository.ProjectRepository
+	sender      WebhookSender
+	config      WebhookConfig
+	logger      *zap.Logger
+}
+
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	projectRepo repository.ProjectRepository,
+	sender WebhookSender,
+	config WebhookConfig,
+	logger *zap.Logger,
+) *WebhookService {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultWebhookBatchSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if config.RetryBaseDelay <= 0 {
+		config.RetryBaseDelay = defaultWebhookRetryBaseDelay
+	}
+	if config.RetryMaxDelay <= 0 {
+		config.RetryMaxDelay = defaultWebhookRetryMaxDelay
+	}
+	if config.SiteURL == "" {
+		config.SiteURL = defaultSiteURL
+	}
+	config.SiteURL = strings.TrimRight(config.SiteURL, "/")
+
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		projectRepo: projectRepo,
+		sender:      sender,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// WebhookDeliveryPage страница журнала доставок
+type WebhookDeliveryPage = pagination.Page[*entities.WebhookDelivery]
+
+// Create создает подписку и генерирует секрет для подписи событий
+func (s *WebhookService) Create(ctx context.Context, createdBy uuid.UUID, webhook *entities.Webhook) error {
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+
+	secret, err := webhookSecret()
+	if err != nil {
+		return err
+	}
+
+	webhook.Secret = secret
+	webhook.CreatedBy = &createdBy
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	return s.webhookRepo.Create(ctx, webhook)
+}
+
+func (s *WebhookService) GetByID(ctx context.Context, id uuid.UUID) (*entities.Webhook, error) {
+	return s.webhookRepo.GetByID(ctx, id)
+}
+
+func (s *WebhookService) GetAll(ctx context.Context) ([]*entities.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if webhooks == nil {
+		webhooks = []*entities.Webhook{}
+	}
+
+	return webhooks, nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, webhook *entities.Webhook) error {
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+
+	webhook.UpdatedAt = time.Now()
+	return s.webhookRepo.Update(ctx, webhook)
+}
+
+// RotateSecret заменяет секрет подписки; старый перестает действовать сразу
+func (s *WebhookService) RotateSecret(ctx context.Context, id uuid.UUID) (*entities.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook.Secret, err = webhookSecret(); err != nil {
+		return nil, err
+	}
+	webhook.UpdatedAt = time.Now()
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+// Deliveries возвращает журнал доставок подписки, новые сначала
+func (s *WebhookService) Deliveries(
+	ctx context.Context,
+	webhookID uuid.UUID,
+	params pagination.Params,
+) (*WebhookDeliveryPage, error) {
+	if _, err := s.webhookRepo.GetByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookRepo.GetDeliveries(ctx, webhookID, params.Cursor, params.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return pagination.NewPage(deliveries, params.Limit, func(delivery *entities.WebhookDelivery) pagination.Cursor {
+		return pagination.Cursor{Time: delivery.CreatedAt, ID: delivery.ID}
+	}), nil
+}
+
+// RetryDelivery ставит доставку в очередь повторно (например, после исправления
+// ошибки на стороне подписчика)
+func (s *WebhookService) RetryDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (bool, error) {
+	return s.webhookRepo.RetryDelivery(ctx, webhookID, deliveryID, time.Now())
+}
+
+// PurgeDeliveries удаляет из журнала завершенные доставки старше before
+func (s *WebhookService) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	return s.webhookRepo.DeleteDeliveriesBefore(ctx, before)
+}
+
+// EmitProjectCreated публикует событие project.created
+func (s *WebhookService) EmitProjectCreated(ctx context.Context, project *entities.Project) {
+	s.emit(ctx, entities.WebhookProjectCreated, map[string]interface{}{
+		"project": project,
+		"url":     s.projectURL(project.ID),
+	})
+}
+
+// EmitProjectVoted публикует событие project.voted при изменении числа лайков
+func (s *WebhookService) EmitProjectVoted(ctx context.Context, project *entities.Project, previousUpvotes int) {
+	if project.Upvotes == previousUpvotes {
+		return
+	}
+
+	s.emit(ctx, entities.WebhookProjectVoted, map[string]interface{}{
+		"project_id":       project.ID,
+		"launch_id":        project.LaunchID,
+		"name":             project.Name,
+		"upvotes":          project.Upvotes,
+		"previous_upvotes": previousUpvotes,
+		"url":              s.projectURL(project.ID),
+	})
+}
+
+// EmitCommentCreated публикует событие comment.created
+func (s *WebhookService) EmitCommentCreated(ctx context.Context, comment *entities.Comment) {
+	s.emit(ctx, entities.WebhookCommentCreated, map[string]interface{}{
+		"comment": comment,
+		"url":     s.projectURL(comment.ProjectID),
+	})
+}
+
+// EmitLaunchStarted публикует событие launch.started. Вызывается только из
+// LaunchService.Rollover, который работает под advisory lock, поэтому
+// событие публикуется один раз на запуск.
+func (s *WebhookService) EmitLaunchStarted(ctx context.Context, launch *entities.Launch) {
+	s.emit(ctx, entities.WebhookLaunchStarted, map[string]interface{}{
+		"launch": launch,
+		"url":    s.config.SiteURL,
+	})
+}
+
+// EmitLaunchFinished публикует событие launch.finished с лучшими проектами запуска.
+// Как и EmitLaunchStarted, вызывается только из LaunchService.Rollover после
+// успешного Deactivate, поэтому повторные вызовы Rollover не дублируют событие.
+func (s *WebhookService) EmitLaunchFinished(ctx context.Context, launch *entities.Launch) {
+	projects, err := s.projectRepo.GetByLaunchIDOrderedByRating(ctx, launch.ID)
+	if err != nil {
+		s.logger.Error("failed to load launch results for webhooks", zap.Error(err),
+			zap.String("launch_id", launch.ID.String()))
+		return
+	}
+
+	if len(projects) > webhookLaunchResults {
+		projects = projects[:webhookLaunchResults]
+	}
+
+	places := ratingPlaces(projects)
+	results := make([]map[string]interface{}, 0, len(projects))
+	for i, project := range projects {
+		results = append(results, map[string]interface{}{
+			"place":      places[i],
+			"project_id": project.ID,
+			"name":       project.Name,
+			"upvotes":    project.Upvotes,
+			"url":        s.projectURL(project.ID),
+		})
+	}
+
+	s.emit(ctx, entities.WebhookLaunchFinished, map[string]interface{}{
+		"launch":  launch,
+		"results": results,
+	})
+}
+
+func (s *WebhookService) emit(ctx context.Context, eventType entities.WebhookEventType, data interface{}) {
+	event := &entities.WebhookEvent{
+		ID:        uuid.New(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("failed to encode webhook event", zap.Error(err), zap.String("type", string(eventType)))
+		return
+	}
+
+	if _, err := s.webhookRepo.EnqueueEvent(ctx, event, payload); err != nil {
+		s.logger.Error("failed to enqueue webhook event", zap.Error(err), zap.String("type", string(eventType)))
+	}
+}
+
+// Run обрабатывает очередь доставок с заданным интервалом до отмены контекста
+func (s *WebhookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				processed, err := s.ProcessDeliveries(ctx)
+				if err != nil {
+					s.logger.Error("failed to process webhook deliveries", zap.Error(err))
+					break
+				}
+				// Полная пачка означает, что в очереди могут оставаться доставки
+				if processed < s.config.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// ProcessDeliveries отправляет одну пачку готовых доставок и возвращает их количество
+func (s *WebhookService) ProcessDeliveries(ctx context.Context) (int, error) {
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), webhookDeliveryLease, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := make(map[uuid.UUID]*entities.Webhook)
+	for _, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			if webhook, err = s.webhookRepo.GetByID(ctx, delivery.WebhookID); err != nil {
+				// Подписку удалили после выборки: ее доставки удалены каскадно
+				if stderrors.Is(err, errors.ErrWebhookNotFound) {
+					continue
+				}
+				return 0, err
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		if err := s.deliver(ctx, webhook, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// deliver отправляет событие и записывает результат попытки в журнал. Возвращает
+// только ошибки обновления журнала; неуспешный ответ превращается в повтор или отказ.
+func (s *WebhookService) deliver(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) error {
+	started := time.Now()
+	response, err := s.sender.Send(ctx, &clients.WebhookRequest{
+		URL:        webhook.URL,
+		Secret:     webhook.Secret,
+		EventType:  string(delivery.EventType),
+		DeliveryID: delivery.ID.String(),
+		Payload:    delivery.Payload,
+	})
+
+	attempt := &entities.WebhookAttempt{Duration: time.Since(started)}
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case response.StatusCode < 200 || response.StatusCode >= 300:
+		attempt.ResponseStatus = response.StatusCode
+		attempt.ResponseBody = response.Body
+		attempt.Error = fmt.Sprintf("unexpected response status %d", response.StatusCode)
+	default:
+		attempt.ResponseStatus = response.StatusCode
+		attempt.ResponseBody = response.Body
+		return s.webhookRepo.MarkDelivered(ctx, delivery.ID, attempt, time.Now())
+	}
+
+	if delivery.Attempts >= s.config.MaxAttempts {
+		s.logger.Warn("webhook delivery dropped",
+			zap.String("delivery_id", delivery.ID.String()),
+			zap.String("webhook_id", webhook.ID.String()),
+			zap.Int("attempts", delivery.Attempts),
+			zap.String("error", attempt.Error))
+		return s.webhookRepo.MarkDeliveryFailed(ctx, delivery.ID, attempt)
+	}
+
+	delay := retryBackoff(s.config.RetryBaseDelay, s.config.RetryMaxDelay, delivery.Attempts)
+	return s.webhookRepo.RescheduleDelivery(ctx, delivery.ID, attempt, time.Now().Add(delay))
+}
+
+func (s *WebhookService) projectURL(projectID uuid.UUID) string {
+	return s.config.SiteURL + "/project/" + projectID.String()
+}
+
+// validateWebhook нормализует и проверяет параметры подписки
+func validateWebhook(webhook *entities.Webhook) error {
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	webhook.Description = strings.TrimSpace(webhook.Description)
+
+	v := validation.New()
+	if v.Required("url", webhook.URL) {
+		v.URL("url", webhook.URL, "http", "https")
+	}
+	v.MaxLength("description", webhook.Description, validation.DescriptionMaxLength)
+
+	if len(webhook.EventTypes) == 0 {
+		v.Add("event_types", validation.CodeRequired, "select at least one event")
+	}
+
+	eventTypes := make(entities.StringArray, 0, len(webhook.EventTypes))
+	seen := make(map[string]bool)
+	for i, eventType := range webhook.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if !isWebhookEventType(eventType) {
+			v.Add(fmt.Sprintf("event_types[%d]", i), validation.CodeInvalidFormat, "unknown event: "+eventType)
+			continue
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	webhook.EventTypes = eventTypes
+
+	return v.Err()
+}
+
+func isWebhookEventType(value string) bool {
+	for _, eventType := range entities.WebhookEventTypes {
+		if string(eventType) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,349 @@
+// Package validation содержит общие правила проверки пользовательского ввода.
+// Ошибки собираются по полям, чтобы клиент мог показать их рядом с формой.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Коды ошибок полей
+const (
+	CodeRequired      = "required"
+	CodeTooShort      = "too_short"
+	CodeTooLong       = "too_long"
+	CodeInvalidFormat = "invalid_format"
+	CodeInvalidScheme = "invalid_scheme"
+	CodeWeakPassword  = "weak_password"
+	CodeTooMany       = "too_many"
+	CodeNotOwned      = "not_owned"
+	CodeTaken         = "taken"
+	CodeNotFound      = "not_found"
+	CodeInvalidChoice = "invalid_choice"
+)
+
+// Ограничения полей
+const (
+	EmailMaxLength           = 255
+	UsernameMinLength        = 3
+	UsernameMaxLength        = 32
+	PasswordMinLength        = 8
+	PasswordMaxLength        = 72 // bcrypt учитывает только первые 72 байта
+	PersonNameMaxLength      = 100
+	ProjectNameMaxLength     = 255
+	DescriptionMaxLength     = 500
+	FullDescriptionMaxLength = 10000
+	URLMaxLength             = 255
+	CreatorMaxLength         = 100
+	MaxCreators              = 10
+	MaxGalleryItems          = 10
+	GalleryCaptionMaxLength  = 200
+	CommentMaxLength         = 2000
+	SearchQueryMinLength     = 2
+	SearchQueryMaxLength     = 200
+	CategorySlugMaxLength    = 64
+	CategoryNameMaxLength    = 100
+	TagMaxLength             = 32
+	MaxProjectTags           = 5
+	TaglineMaxLength         = 120
+	MaxProjectLinks          = 10
+)
+
+var (
+	usernamePattern         = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+	telegramUsernamePattern = regexp.MustCompile(`^@?[a-zA-Z0-9_]{5,32}$`)
+	imageFileNamePattern    = regexp.MustCompile(`^([0-9a-f]{64}|[0-9a-f]{8}_[0-9a-f]{8})\.(jpg|png|gif|webp)$`)
+	videoFileNamePattern    = regexp.MustCompile(`^[0-9a-f]{64}\.(mp4|webm)$`)
+	slugPattern             = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	tagPattern              = regexp.MustCompile(`^[\p{Ll}\p{N}]+(-[\p{Ll}\p{N}]+)*$`)
+)
+
+// FieldError ошибка конкретного поля
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors набор ошибок полей; реализует error
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fieldErr := range e {
+		parts[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Validator накапливает ошибки полей
+type Validator struct {
+	errors Errors
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Add добавляет ошибку поля
+func (v *Validator) Add(field, code, message string) {
+	v.errors = append(v.errors, FieldError{Field: field, Code: code, Message: message})
+}
+
+// HasError проверяет, есть ли уже ошибка для поля
+func (v *Validator) HasError(field string) bool {
+	for _, fieldErr := range v.errors {
+		if fieldErr.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err возвращает Errors или nil, если ошибок нет
+func (v *Validator) Err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return v.errors
+}
+
+// Required проверяет, что значение не пустое
+func (v *Validator) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, CodeRequired, "required field")
+		return false
+	}
+	return true
+}
+
+// MaxLength проверяет длину строки в символах
+func (v *Validator) MaxLength(field, value string, max int) bool {
+	if utf8.RuneCountInString(value) > max {
+		v.Add(field, CodeTooLong, fmt.Sprintf("at most %d characters", max))
+		return false
+	}
+	return true
+}
+
+// MinLength проверяет минимальную длину строки в символах
+func (v *Validator) MinLength(field, value string, min int) bool {
+	if utf8.RuneCountInString(value) < min {
+		v.Add(field, CodeTooShort, fmt.Sprintf("at least %d characters", min))
+		return false
+	}
+	return true
+}
+
+// MaxItems проверяет количество элементов списка
+func (v *Validator) MaxItems(field string, count, max int) bool {
+	if count > max {
+		v.Add(field, CodeTooMany, fmt.Sprintf("at most %d items", max))
+		return false
+	}
+	return true
+}
+
+// Email проверяет формат адреса электронной почты
+func (v *Validator) Email(field, value string) bool {
+	if !v.Required(field, value) || !v.MaxLength(field, value, EmailMaxLength) {
+		return false
+	}
+
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value || !strings.Contains(value[strings.LastIndex(value, "@"):], ".") {
+		v.Add(field, CodeInvalidFormat, "invalid email")
+		return false
+	}
+	return true
+}
+
+// Username проверяет длину и допустимые символы имени пользователя
+func (v *Validator) Username(field, value string) bool {
+	if !v.Required(field, value) ||
+		!v.MinLength(field, value, UsernameMinLength) ||
+		!v.MaxLength(field, value, UsernameMaxLength) {
+		return false
+	}
+
+	if !usernamePattern.MatchString(value) {
+		v.Add(field, CodeInvalidFormat, "only latin letters, digits, dot, hyphen and underscore are allowed")
+		return false
+	}
+	return true
+}
+
+// Slug проверяет адресный идентификатор: латинские буквы в нижнем регистре, цифры и дефисы
+func (v *Validator) Slug(field, value string, max int) bool {
+	if !v.Required(field, value) || !v.MaxLength(field, value, max) {
+		return false
+	}
+
+	if !slugPattern.MatchString(value) {
+		v.Add(field, CodeInvalidFormat, "only lowercase latin letters, digits and hyphen are allowed")
+		return false
+	}
+	return true
+}
+
+// Tag проверяет нормализованный тег: буквы в нижнем регистре, цифры и дефисы
+func (v *Validator) Tag(field, value string) bool {
+	if !v.MaxLength(field, value, TagMaxLength) {
+		return false
+	}
+
+	if !tagPattern.MatchString(value) {
+		v.Add(field, CodeInvalidFormat, "only letters, digits and hyphen are allowed")
+		return false
+	}
+	return true
+}
+
+// Password проверяет политику паролей: длина и наличие букв и цифр
+func (v *Validator) Password(field, value string) bool {
+	if value == "" {
+		v.Add(field, CodeRequired, "required field")
+		return false
+	}
+	if !v.MinLength(field, value, PasswordMinLength) {
+		return false
+	}
+	if len(value) > PasswordMaxLength {
+		v.Add(field, CodeTooLong, fmt.Sprintf("at most %d bytes", PasswordMaxLength))
+		return false
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range value {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		v.Add(field, CodeWeakPassword, "password must contain letters and digits")
+		return false
+	}
+	return true
+}
+
+// URL проверяет абсолютный URL с одной из разрешенных схем (по умолчанию http и https)
+func (v *Validator) URL(field, value string, schemes ...string) bool {
+	if !v.MaxLength(field, value, URLMaxLength) {
+		return false
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		v.Add(field, CodeInvalidFormat, "invalid URL")
+		return false
+	}
+
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(parsed.Scheme, scheme) {
+			return true
+		}
+	}
+
+	v.Add(field, CodeInvalidScheme, "allowed schemes: "+strings.Join(schemes, ", "))
+	return false
+}
+
+// TelegramContact принимает @username или ссылку на t.me
+func (v *Validator) TelegramContact(field, value string) bool {
+	if telegramUsernamePattern.MatchString(value) {
+		return true
+	}
+
+	parsed, err := url.Parse(value)
+	if err == nil && parsed.Scheme == "https" && (parsed.Host == "t.me" || parsed.Host == "telegram.me") {
+		return true
+	}
+
+	v.Add(field, CodeInvalidFormat, "expected @username or a https://t.me/... link")
+	return false
+}
+
+// ImageURL проверяет, что изображение загружено в наше хранилище (baseURL),
+// а не подставлено с произвольного стороннего адреса
+func (v *Validator) ImageURL(field, value, baseURL string) bool {
+	fileName, ok := ImageFileName(value, baseURL)
+	if !ok || fileName == "" {
+		v.Add(field, CodeNotOwned, "image must be uploaded through the service")
+		return false
+	}
+	return true
+}
+
+// VideoURL проверяет, что ролик загружен в наше хранилище (baseURL)
+func (v *Validator) VideoURL(field, value, baseURL string) bool {
+	fileName, ok := VideoFileName(value, baseURL)
+	if !ok || fileName == "" {
+		v.Add(field, CodeNotOwned, "video must be uploaded through the service")
+		return false
+	}
+	return true
+}
+
+// ImageFileName извлекает имя файла из URL изображения нашего хранилища
+func ImageFileName(value, baseURL string) (string, bool) {
+	fileName, ok := storageFileName(value, baseURL)
+	if !ok || !IsImageFileName(fileName) {
+		return "", false
+	}
+	return fileName, true
+}
+
+// VideoFileName извлекает имя файла из URL ролика нашего хранилища
+func VideoFileName(value, baseURL string) (string, bool) {
+	fileName, ok := storageFileName(value, baseURL)
+	if !ok || !IsVideoFileName(fileName) {
+		return "", false
+	}
+	return fileName, true
+}
+
+// UploadFileName извлекает имя файла из URL любой загрузки нашего хранилища:
+// изображения или ролика
+func UploadFileName(value, baseURL string) (string, bool) {
+	fileName, ok := storageFileName(value, baseURL)
+	if !ok || (!IsImageFileName(fileName) && !IsVideoFileName(fileName)) {
+		return "", false
+	}
+	return fileName, true
+}
+
+func storageFileName(value, baseURL string) (string, bool) {
+	prefix := strings.TrimRight(baseURL, "/") + "/"
+	if !strings.HasPrefix(value, prefix) {
+		return "", false
+	}
+
+	fileName := strings.TrimPrefix(value, prefix)
+	if fileName != path.Base(fileName) {
+		return "", false
+	}
+	return fileName, true
+}
+
+// IsImageFileName проверяет, что имя файла имеет формат, выдаваемый при загрузке:
+// SHA-256 содержимого или прежний "xxxxxxxx_xxxxxxxx" загрузок до хранения по хешу
+func IsImageFileName(fileName string) bool {
+	return imageFileNamePattern.MatchString(fileName)
+}
+
+// IsVideoFileName проверяет, что имя файла имеет формат загруженного ролика
+func IsVideoFileName(fileName string) bool {
+	return videoFileNamePattern.MatchString(fileName)
+}
@@ -0,0 +1,9 @@
+-- Счетчики ограничения частоты запросов и блокировок входа
+CREATE TABLE rate_limits (
+    bucket_key VARCHAR(255) PRIMARY KEY,
+    count INTEGER NOT NULL DEFAULT 0,
+    reset_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    blocked_until TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_rate_limits_reset_at ON rate_limits(reset_at);
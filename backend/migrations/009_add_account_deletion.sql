@@ -0,0 +1,5 @@
+-- Удаление аккаунта с отложенной очисткой данных
+ALTER TABLE users ADD COLUMN deletion_scheduled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_users_deletion_scheduled_at ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Публичные профили: настройки приватности и username в Telegram
+ALTER TABLE users ADD COLUMN telegram_username VARCHAR(64);
+ALTER TABLE users ADD COLUMN profile_public BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN show_telegram BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN show_activity BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX idx_users_username ON users(username);
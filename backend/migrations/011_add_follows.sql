@@ -0,0 +1,23 @@
+-- Подписки на мейкеров и проекты
+CREATE TABLE user_follows (
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE TABLE project_follows (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, project_id)
+);
+
+-- Подсчет подписчиков
+CREATE INDEX idx_user_follows_followee_id ON user_follows(followee_id);
+CREATE INDEX idx_project_follows_project_id ON project_follows(project_id);
+
+-- Лента читает последние записи по каждому источнику в порядке (created_at, id)
+CREATE INDEX idx_projects_user_created ON projects(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_comments_project_created ON comments(project_id, created_at DESC, id DESC);
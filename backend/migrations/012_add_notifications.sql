@@ -0,0 +1,25 @@
+-- Центр уведомлений
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    launch_id UUID REFERENCES launches(id) ON DELETE CASCADE,
+    milestone INTEGER,
+    place INTEGER,
+    -- Ключ дедупликации: одно уведомление о каждой отметке лайков и каждом итоге запуска
+    dedup_key VARCHAR(255),
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, dedup_key)
+);
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Настройки уведомлений по типам
+ALTER TABLE users ADD COLUMN notify_comments BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN notify_vote_milestones BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN notify_launch_results BOOLEAN NOT NULL DEFAULT TRUE;
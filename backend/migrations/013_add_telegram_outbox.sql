@@ -0,0 +1,21 @@
+-- Очередь исходящих сообщений Telegram: переживает перезапуски и хранит состояние повторов
+CREATE TABLE telegram_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chat_id BIGINT NOT NULL,
+    text TEXT NOT NULL,
+    notification_id UUID REFERENCES notifications(id) ON DELETE SET NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP
+);
+
+CREATE INDEX idx_telegram_outbox_pending ON telegram_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_telegram_outbox_user_id ON telegram_outbox(user_id);
+
+-- Новый тип уведомлений и канал доставки
+ALTER TABLE users ADD COLUMN notify_launch_started BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN notify_telegram BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,22 @@
+-- Подписка на рассылку (в том числе для незарегистрированных адресов)
+CREATE TABLE newsletter_subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL UNIQUE,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    confirm_token VARCHAR(64) UNIQUE,
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    confirmed_at TIMESTAMP,
+    unsubscribed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_newsletter_subscriptions_confirmed ON newsletter_subscriptions(id) WHERE status = 'confirmed';
+
+-- Отправленные дайджесты: позволяют возобновить рассылку после сбоя без дублей
+CREATE TABLE newsletter_deliveries (
+    digest_key VARCHAR(128) NOT NULL,
+    subscription_id UUID NOT NULL REFERENCES newsletter_subscriptions(id) ON DELETE CASCADE,
+    sent_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (digest_key, subscription_id)
+);
@@ -0,0 +1,60 @@
+-- Полнотекстовый поиск по проектам, комментариям и мейкерам
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE projects ADD COLUMN search_vector tsvector;
+ALTER TABLE comments ADD COLUMN search_vector tsvector;
+
+-- Тексты индексируются в русской и английской конфигурациях: проекты описывают на обоих языках.
+-- Вес: A - название, B - краткое описание и авторы, C - полное описание.
+CREATE OR REPLACE FUNCTION projects_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    -- Обновление рейтинга перезаписывает все колонки; пересчитываем вектор только при изменении текста
+    IF TG_OP = 'UPDATE' AND OLD.search_vector IS NOT NULL
+        AND NEW.name IS NOT DISTINCT FROM OLD.name
+        AND NEW.description IS NOT DISTINCT FROM OLD.description
+        AND NEW.full_description IS NOT DISTINCT FROM OLD.full_description
+        AND NEW.creators IS NOT DISTINCT FROM OLD.creators THEN
+        RETURN NEW;
+    END IF;
+
+    NEW.search_vector :=
+        setweight(to_tsvector('russian', COALESCE(NEW.name, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(NEW.name, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(NEW.description, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(NEW.description, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(array_to_string(NEW.creators, ' '), '')), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(NEW.full_description, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE(NEW.full_description, '')), 'C');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER projects_search_vector
+    BEFORE INSERT OR UPDATE OF name, description, full_description, creators ON projects
+    FOR EACH ROW EXECUTE FUNCTION projects_search_vector_update();
+
+CREATE OR REPLACE FUNCTION comments_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        to_tsvector('russian', COALESCE(NEW.content, '')) ||
+        to_tsvector('english', COALESCE(NEW.content, ''));
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comments_search_vector
+    BEFORE INSERT OR UPDATE OF content ON comments
+    FOR EACH ROW EXECUTE FUNCTION comments_search_vector_update();
+
+-- Заполняем векторы для существующих записей
+UPDATE projects SET name = name;
+UPDATE comments SET content = content;
+
+CREATE INDEX idx_projects_search ON projects USING GIN (search_vector);
+CREATE INDEX idx_comments_search ON comments USING GIN (search_vector);
+
+-- Нечеткий поиск по названиям и именам (опечатки, части слов)
+CREATE INDEX idx_projects_name_trgm ON projects USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_users_username_trgm ON users USING GIN (username gin_trgm_ops);
+CREATE INDEX idx_users_full_name_trgm ON users
+    USING GIN ((COALESCE(first_name, '') || ' ' || COALESCE(last_name, '')) gin_trgm_ops);
@@ -0,0 +1,17 @@
+-- Категории проектов (ведут администраторы) и свободные теги
+CREATE TABLE categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- При удалении категории проекты остаются без категории
+ALTER TABLE projects ADD COLUMN category_id UUID REFERENCES categories(id) ON DELETE SET NULL;
+ALTER TABLE projects ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_projects_launch_category ON projects(launch_id, category_id);
+CREATE INDEX idx_projects_tags ON projects USING GIN (tags);
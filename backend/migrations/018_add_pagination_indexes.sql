@@ -0,0 +1,3 @@
+-- Индексы под курсорную пагинацию списков
+CREATE INDEX idx_projects_launch_rating ON projects(launch_id, rating DESC, created_at, id);
+CREATE INDEX idx_votes_user_created ON votes(user_id, created_at DESC, id DESC);
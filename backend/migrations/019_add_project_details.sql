@@ -0,0 +1,14 @@
+-- Структурированные поля проекта: слоган, типизированные ссылки, модель монетизации, платформы и стадия
+ALTER TABLE projects ADD COLUMN tagline VARCHAR(120) NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN links JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE projects ADD COLUMN pricing_model VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN platforms TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE projects ADD COLUMN stage VARCHAR(16) NOT NULL DEFAULT '';
+
+-- Пустая строка означает, что значение не указано
+ALTER TABLE projects ADD CONSTRAINT projects_pricing_model_check
+    CHECK (pricing_model IN ('', 'free', 'freemium', 'paid', 'subscription', 'open_source'));
+ALTER TABLE projects ADD CONSTRAINT projects_stage_check
+    CHECK (stage IN ('', 'idea', 'mvp', 'revenue'));
+
+CREATE INDEX idx_projects_platforms ON projects USING GIN (platforms);
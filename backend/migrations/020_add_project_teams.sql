@@ -0,0 +1,39 @@
+-- Команды проектов: владелец и соавторы (мейкеры) с зарегистрированными аккаунтами
+CREATE TABLE project_members (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'maker')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX idx_project_members_user ON project_members(user_id);
+
+-- Автор существующих проектов становится владельцем
+INSERT INTO project_members (project_id, user_id, role, created_at)
+SELECT id, user_id, 'owner', created_at FROM projects WHERE user_id IS NOT NULL;
+
+-- Приглашения в команду: по username (invitee_id) или по ссылке из письма (email и хеш токена)
+CREATE TABLE project_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    inviter_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    invitee_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255),
+    token_hash VARCHAR(64) UNIQUE,
+    role VARCHAR(16) NOT NULL DEFAULT 'maker',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP,
+    CHECK (invitee_id IS NOT NULL OR email IS NOT NULL),
+    -- Приглашение по email связывается с аккаунтом только при принятии
+    CHECK (email IS NULL OR invitee_id IS NULL OR status = 'accepted')
+);
+
+-- Не больше одного активного приглашения на человека в проект
+CREATE UNIQUE INDEX idx_project_invitations_pending_user
+    ON project_invitations(project_id, invitee_id) WHERE status = 'pending' AND invitee_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_project_invitations_pending_email
+    ON project_invitations(project_id, LOWER(email)) WHERE status = 'pending' AND email IS NOT NULL;
+CREATE INDEX idx_project_invitations_invitee ON project_invitations(invitee_id) WHERE status = 'pending';
@@ -0,0 +1,56 @@
+-- Загруженные изображения: владелец и параметры файла. Файл без ссылок
+-- (брошенная загрузка) удаляется фоновой очисткой.
+CREATE TABLE images (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    file_name VARCHAR(255) NOT NULL UNIQUE,
+    owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    mime_type VARCHAR(64) NOT NULL DEFAULT '',
+    hash VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_images_owner ON images(owner_id);
+CREATE INDEX idx_images_created_at ON images(created_at);
+
+-- Ссылки на изображения: логотип и галерея проекта или аватар пользователя
+CREATE TABLE image_references (
+    image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((project_id IS NULL) <> (user_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_image_references_project ON image_references(project_id, image_id) WHERE project_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_image_references_user ON image_references(user_id, image_id) WHERE user_id IS NOT NULL;
+CREATE INDEX idx_image_references_image ON image_references(image_id);
+
+-- Уже используемые изображения: владельцем считается автор проекта или пользователь
+-- с аватаром. Размер, тип и хеш для них неизвестны.
+CREATE TEMP TABLE used_images AS
+SELECT regexp_replace(url, '^.*/', '') AS file_name, project_id, user_id, owner_id, created_at
+FROM (
+    SELECT p.logo AS url, p.id AS project_id, NULL::uuid AS user_id, p.user_id AS owner_id, p.created_at
+    FROM projects p
+    UNION ALL
+    SELECT unnest(p.images), p.id, NULL::uuid, p.user_id, p.created_at
+    FROM projects p
+    UNION ALL
+    SELECT u.avatar, NULL::uuid, u.id, u.id, u.created_at
+    FROM users u
+) refs
+WHERE url ~ '/images/[a-zA-Z0-9_-]+\.[a-z0-9]+$';
+
+INSERT INTO images (file_name, owner_id, created_at)
+SELECT DISTINCT ON (file_name) file_name, owner_id, created_at
+FROM used_images
+ORDER BY file_name, created_at
+ON CONFLICT (file_name) DO NOTHING;
+
+INSERT INTO image_references (image_id, project_id, user_id)
+SELECT DISTINCT i.id, u.project_id, u.user_id
+FROM used_images u
+JOIN images i ON i.file_name = u.file_name;
+
+DROP TABLE used_images;
@@ -0,0 +1,26 @@
+-- Файлы изображений хранятся под SHA-256 содержимого: одинаковые загрузки разных
+-- пользователей (images) используют один файл. ref_count - число загрузок файла,
+-- файл удаляется из хранилища, когда счетчик доходит до нуля.
+CREATE TABLE image_blobs (
+    file_name VARCHAR(255) PRIMARY KEY,
+    hash VARCHAR(64) NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    mime_type VARCHAR(64) NOT NULL DEFAULT '',
+    ref_count INTEGER NOT NULL DEFAULT 0 CHECK (ref_count >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Ранее загруженные файлы сохраняют свои имена, у каждого ровно одна загрузка
+INSERT INTO image_blobs (file_name, hash, size, mime_type, ref_count, created_at)
+SELECT file_name, hash, size, mime_type, 1, created_at FROM images;
+
+ALTER TABLE images DROP CONSTRAINT images_file_name_key;
+ALTER TABLE images ADD CONSTRAINT images_file_name_fkey
+    FOREIGN KEY (file_name) REFERENCES image_blobs(file_name);
+ALTER TABLE images DROP COLUMN size;
+ALTER TABLE images DROP COLUMN mime_type;
+ALTER TABLE images DROP COLUMN hash;
+
+-- Повторная загрузка того же файла тем же пользователем не создает новую запись
+CREATE UNIQUE INDEX idx_images_owner_file ON images(owner_id, file_name);
+CREATE INDEX idx_images_file_name ON images(file_name);
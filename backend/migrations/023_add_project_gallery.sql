@@ -0,0 +1,10 @@
+-- Галерея проекта: изображения, загруженные ролики и встраиваемые видео в порядке показа
+ALTER TABLE projects ADD COLUMN gallery JSONB NOT NULL DEFAULT '[]';
+
+-- Прежние изображения становятся элементами галереи. Колонка images остается
+-- и хранит изображения галереи для клиентов, не знающих о галерее.
+UPDATE projects p SET gallery = (
+    SELECT jsonb_agg(jsonb_build_object('type', 'image', 'url', t.url) ORDER BY t.ord)
+    FROM unnest(p.images) WITH ORDINALITY AS t(url, ord)
+)
+WHERE cardinality(p.images) > 0;
@@ -0,0 +1,12 @@
+-- Сессии входа: идентификатор сессии хранится в JWT (sid) и проверяется при каждом запросе,
+-- поэтому выход и удаление аккаунта отзывают уже выданные токены
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
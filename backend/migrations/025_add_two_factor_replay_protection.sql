@@ -0,0 +1,12 @@
+-- Защита 2FA от повторного использования: последний принятый шаг TOTP
+-- и одноразовые токены входа, ожидающие второго фактора
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT;
+
+CREATE TABLE two_factor_challenges (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_two_factor_challenges_expires_at ON two_factor_challenges(expires_at);
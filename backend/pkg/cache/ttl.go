@@ -0,0 +1,97 @@
+// Package cache кеш в памяти процесса с ограниченным временем жизни записей
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTL потокобезопасный кеш с временем жизни записей и сбросом всех записей при
+// изменении исходных данных. Значения отдаются всем читателям без копирования и
+// не должны изменяться.
+type TTL[K comparable, V any] struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.RWMutex
+	entries map[K]entry[V]
+	// generation увеличивается при Clear и Delete, чтобы не сохранить значение,
+	// загруженное до сброса
+	generation uint64
+}
+
+// NewTTL создает кеш; при ttl <= 0 кеширование отключено
+func NewTTL[K comparable, V any](ttl time.Duration, maxEntries int) *TTL[K, V] {
+	return &TTL[K, V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[K]entry[V]),
+	}
+}
+
+// GetOrLoad возвращает значение из кеша или загружает его через load. Ошибки
+// загрузки не кешируются.
+func (c *TTL[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	if c.ttl <= 0 {
+		return load()
+	}
+
+	now := time.Now()
+
+	c.mu.RLock()
+	cached, ok := c.entries[key]
+	generation := c.generation
+	c.mu.RUnlock()
+
+	if ok && now.Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != generation {
+		return value, nil
+	}
+
+	if len(c.entries) >= c.maxEntries {
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			c.entries = make(map[K]entry[V])
+		}
+	}
+
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+	return value, nil
+}
+
+// Clear удаляет все записи
+func (c *TTL[K, V]) Clear() {
+	c.mu.Lock()
+	c.entries = make(map[K]entry[V])
+	c.generation++
+	c.mu.Unlock()
+}
+
+// Delete удаляет запись key. Загрузки, начатые до удаления, не сохраняются ни для
+// одного ключа: иначе чтение, обогнавшее запись, вернуло бы в кеш старое значение.
+func (c *TTL[K, V]) Delete(key K) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.generation++
+	c.mu.Unlock()
+}
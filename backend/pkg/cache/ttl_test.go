@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLDeleteDropsLoadStartedBefore(t *testing.T) {
+	c := NewTTL[string, int](time.Minute, 10)
+
+	// Чтение загружает старое значение, а запись успевает удалить ключ до его сохранения
+	value, err := c.GetOrLoad("user", func() (int, error) {
+		c.Delete("user")
+		return 1, nil
+	})
+	if err != nil || value != 1 {
+		t.Fatalf("GetOrLoad = %d, %v; want 1, nil", value, err)
+	}
+
+	value, _ = c.GetOrLoad("user", func() (int, error) { return 2, nil })
+	if value != 2 {
+		t.Errorf("value after Delete = %d, want 2 (stale value was cached)", value)
+	}
+
+	value, _ = c.GetOrLoad("user", func() (int, error) { return 3, nil })
+	if value != 2 {
+		t.Errorf("cached value = %d, want 2", value)
+	}
+}
+
+func TestTTLDeleteKeepsOtherKeys(t *testing.T) {
+	c := NewTTL[string, int](time.Minute, 10)
+
+	c.GetOrLoad("a", func() (int, error) { return 1, nil })
+	c.GetOrLoad("b", func() (int, error) { return 2, nil })
+	c.Delete("a")
+
+	value, _ := c.GetOrLoad("b", func() (int, error) { return 0, nil })
+	if value != 2 {
+		t.Errorf("b = %d, want cached 2", value)
+	}
+	value, _ = c.GetOrLoad("a", func() (int, error) { return 10, nil })
+	if value != 10 {
+		t.Errorf("a = %d, want reloaded 10", value)
+	}
+}
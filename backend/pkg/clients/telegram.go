@@ -0,0 +1,121 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTelegramAPIURL адрес Telegram Bot API
+const DefaultTelegramAPIURL = "https://api.telegram.org"
+
+// TelegramAPIError ошибка, которую вернул Bot API
+type TelegramAPIError struct {
+	StatusCode  int
+	Description string
+	// RetryAfter задержка, которую Telegram просит выдержать при 429
+	RetryAfter time.Duration
+}
+
+func (e *TelegramAPIError) Error() string {
+	return fmt.Sprintf("telegram api error %d: %s", e.StatusCode, e.Description)
+}
+
+// Temporary сообщает, имеет ли смысл повторить запрос. Ошибки 4xx (кроме 429)
+// означают, что бот заблокирован или чат не существует, и повтор не поможет.
+func (e *TelegramAPIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// TelegramClient минимальный клиент Bot API. Базовый адрес настраивается, чтобы
+// в тестах и при локальной разработке можно было подставить заглушку.
+type TelegramClient struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewTelegramClient создает клиент Bot API; пустой baseURL означает api.telegram.org
+func NewTelegramClient(token, baseURL string, timeout time.Duration) *TelegramClient {
+	if baseURL == "" {
+		baseURL = DefaultTelegramAPIURL
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &TelegramClient{
+		token:      token,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+	ErrorCode   int    `json:"error_code"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// SendMessage отправляет текстовое сообщение в чат
+func (c *TelegramClient) SendMessage(ctx context.Context, chatID int64, text string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode telegram message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", c.baseURL, c.token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Сетевые ошибки не раскрывают URL: в нем содержится токен бота
+		return fmt.Errorf("failed to send telegram message: %w", stripURLError(err))
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && resp.StatusCode == http.StatusOK {
+		return fmt.Errorf("failed to decode telegram response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || !result.OK {
+		apiErr := &TelegramAPIError{
+			StatusCode:  resp.StatusCode,
+			Description: result.Description,
+			RetryAfter:  time.Duration(result.Parameters.RetryAfter) * time.Second,
+		}
+		if apiErr.Description == "" {
+			apiErr.Description = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+
+	return nil
+}
+
+// stripURLError убирает из ошибки http-клиента адрес запроса
+func stripURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTelegramClientSendMessage(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewTelegramClient("123:token", server.URL+"/", time.Second)
+	if err := client.SendMessage(context.Background(), 42, "hello"); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	if gotPath != "/bot123:token/sendMessage" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotBody["chat_id"] != float64(42) || gotBody["text"] != "hello" {
+		t.Errorf("body = %v", gotBody)
+	}
+}
+
+func TestTelegramClientErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		temporary  bool
+		retryAfter time.Duration
+	}{
+		{
+			name:       "too many requests",
+			status:     http.StatusTooManyRequests,
+			body:       `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 17","parameters":{"retry_after":17}}`,
+			temporary:  true,
+			retryAfter: 17 * time.Second,
+		},
+		{
+			name:      "bot blocked",
+			status:    http.StatusForbidden,
+			body:      `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`,
+			temporary: false,
+		},
+		{
+			name:      "server error without body",
+			status:    http.StatusBadGateway,
+			body:      ``,
+			temporary: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := NewTelegramClient("token", server.URL, time.Second).SendMessage(context.Background(), 1, "text")
+
+			var apiErr *TelegramAPIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want TelegramAPIError", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Temporary() != tt.temporary {
+				t.Errorf("Temporary() = %v, want %v", apiErr.Temporary(), tt.temporary)
+			}
+			if apiErr.RetryAfter != tt.retryAfter {
+				t.Errorf("RetryAfter = %v, want %v", apiErr.RetryAfter, tt.retryAfter)
+			}
+			if apiErr.Description == "" {
+				t.Error("Description is empty")
+			}
+		})
+	}
+}
+
+func TestTelegramClientHidesTokenInNetworkErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	err := NewTelegramClient("secret-token", url, time.Second).SendMessage(context.Background(), 1, "text")
+	if err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error leaks the bot token: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+
+	webhookUserAgent = "StartupScout-Webhooks/1.0"
+	// webhookMaxResponseBody сколько байт ответа подписчика сохраняется в журнал
+	webhookMaxResponseBody = 1024
+)
+
+// WebhookRequest событие, подготовленное к отправке подписчику
+type WebhookRequest struct {
+	URL        string
+	Secret     string
+	EventType  string
+	DeliveryID string
+	Payload    []byte
+}
+
+// WebhookResponse ответ подписчика (тело обрезано до 1 КБ)
+type WebhookResponse struct {
+	StatusCode int
+	Body       string
+}
+
+// WebhookClient отправляет подписанные события на адреса подписчиков
+type WebhookClient struct {
+	httpClient *http.Client
+}
+
+func NewWebhookClient(timeout time.Duration) *WebhookClient {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &WebhookClient{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			// Редиректы не выполняются: подписчик должен указать конечный адрес
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send отправляет событие POST-запросом. Ошибка возвращается только если ответ
+// не получен; код ответа проверяет вызывающий.
+func (c *WebhookClient) Send(ctx context.Context, request *WebhookRequest) (*WebhookResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(WebhookEventHeader, request.EventType)
+	req.Header.Set(WebhookDeliveryHeader, request.DeliveryID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(request.Secret, timestamp, request.Payload))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+
+	return &WebhookResponse{
+		StatusCode: resp.StatusCode,
+		Body:       string(bytes.ToValidUTF8(bytes.ReplaceAll(body, []byte{0}, nil), nil)),
+	}, nil
+}
+
+// SignWebhook подпись события: "sha256=" + hex(HMAC-SHA256(secret, "<timestamp>.<payload>")).
+// Метка времени входит в подпись, чтобы подписчик мог отклонять повторы старых запросов.
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
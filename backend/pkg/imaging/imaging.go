@@ -0,0 +1,141 @@
+// Package imaging декодирует, поворачивает по EXIF, масштабирует и кодирует
+// изображения средствами стандартной библиотеки и golang.org/x/image/webp.
+// При перекодировании метаданные (EXIF, XMP, комментарии) не сохраняются.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	// Регистрирует декодер WebP для image.Decode
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+	FormatWebP = "webp"
+
+	jpegQuality = 85
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// Decode декодирует JPEG, PNG, GIF (первый кадр) или статичный WebP и применяет
+// к JPEG поворот из EXIF, чтобы изображение выглядело так же после удаления
+// метаданных. Анимированный WebP декодер не поддерживает.
+func Decode(data []byte) (image.Image, string, error) {
+	if isWebP(data) && isAnimatedWebP(data) {
+		return nil, "", ErrUnsupportedFormat
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch format {
+	case FormatJPEG:
+		if orientation := jpegOrientation(data); orientation > 1 {
+			img = orient(toRGBA(img), orientation)
+		}
+	case FormatPNG, FormatGIF, FormatWebP:
+	default:
+		return nil, "", ErrUnsupportedFormat
+	}
+
+	return img, format, nil
+}
+
+// Encode кодирует изображение в заданном формате; GIF и WebP (кодировщика WebP
+// нет) кодируются как PNG
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case FormatPNG, FormatGIF, FormatWebP:
+		return png.Encode(w, img)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// ReencodeGIF перекодирует GIF со всеми кадрами, отбрасывая комментарии и
+// прочие расширения
+func ReencodeGIF(data []byte) ([]byte, error) {
+	animation, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, &gif.GIF{
+		Image:     animation.Image,
+		Delay:     animation.Delay,
+		LoopCount: animation.LoopCount,
+		Disposal:  animation.Disposal,
+		Config:    animation.Config,
+	}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Fit уменьшает изображение, чтобы оно поместилось в width x height с сохранением
+// пропорций. Изображения меньше рамки не увеличиваются.
+func Fit(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW <= width && srcH <= height {
+		return img
+	}
+
+	dstW, dstH := width, srcH*width/srcW
+	if dstH > height {
+		dstW, dstH = srcW*height/srcH, height
+	}
+
+	return resize(toRGBA(img), max(dstW, 1), max(dstH, 1))
+}
+
+// Fill обрезает изображение по центру до пропорций width x height и уменьшает
+// до этого размера (например, квадратные аватары)
+func Fill(img image.Image, width, height int) image.Image {
+	src := toRGBA(img)
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+
+	cropW, cropH := srcW, srcW*height/width
+	if cropH > srcH {
+		cropW, cropH = srcH*width/height, srcH
+	}
+	x0 := (srcW - cropW) / 2
+	y0 := (srcH - cropH) / 2
+	cropped := src.SubImage(image.Rect(x0, y0, x0+cropW, y0+cropH)).(*image.RGBA)
+
+	if cropW <= width && cropH <= height {
+		return cropped
+	}
+	return resize(cropped, width, height)
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
@@ -0,0 +1,224 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+)
+
+var (
+	ErrTooLarge   = errors.New("image dimensions exceed the allowed maximum")
+	errInvalidGIF = errors.New("invalid gif file")
+)
+
+// CheckDimensions читает размеры изображения из заголовка и проверяет их до
+// декодирования: сжатый файл небольшого размера может распаковаться в гигабайты
+// пикселей. maxSide ограничивает ширину и высоту, maxPixels - число пикселей
+// всех кадров анимации; 0 отключает ограничение.
+func CheckDimensions(data []byte, maxSide int, maxPixels int64) error {
+	var width, height, frames int
+	if isWebP(data) {
+		var err error
+		width, height, frames, err = webpDimensions(data)
+		if err != nil {
+			return err
+		}
+	} else {
+		config, format, err := image.DecodeConfig(bytes.NewReader(data))
+		if errors.Is(err, image.ErrFormat) {
+			return ErrUnsupportedFormat
+		}
+		if err != nil {
+			return err
+		}
+		width, height, frames = config.Width, config.Height, 1
+
+		if format == FormatGIF {
+			// Каждый кадр GIF декодируется в буфер не больше логического экрана
+			frames, err = gifFrameCount(data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid image dimensions %dx%d", width, height)
+	}
+	if maxSide > 0 && (width > maxSide || height > maxSide) {
+		return fmt.Errorf("%w: %dx%d, max side %d", ErrTooLarge, width, height, maxSide)
+	}
+	if pixels := int64(width) * int64(height) * int64(max(frames, 1)); maxPixels > 0 && pixels > maxPixels {
+		return fmt.Errorf("%w: %d pixels in %d frames, max %d", ErrTooLarge, pixels, frames, maxPixels)
+	}
+
+	return nil
+}
+
+// gifFrameCount считает кадры GIF по блокам файла, не распаковывая их
+func gifFrameCount(data []byte) (int, error) {
+	if len(data) < 13 || (string(data[:6]) != "GIF87a" && string(data[:6]) != "GIF89a") {
+		return 0, errInvalidGIF
+	}
+
+	pos := 13
+	// Глобальная палитра
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << (flags&0x07 + 1)
+	}
+
+	frames := 0
+	for pos < len(data) {
+		var err error
+		switch data[pos] {
+		case 0x21: // расширение: метка и подблоки
+			pos, err = skipGIFSubBlocks(data, pos+2)
+		case 0x2C: // кадр: дескриптор, локальная палитра, размер кода LZW и подблоки
+			if pos+10 > len(data) {
+				return 0, errInvalidGIF
+			}
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1)
+			}
+			pos, err = skipGIFSubBlocks(data, pos+1)
+			frames++
+		case 0x3B: // конец файла
+			return frames, nil
+		default:
+			return 0, errInvalidGIF
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return 0, errInvalidGIF
+}
+
+func skipGIFSubBlocks(data []byte, pos int) (int, error) {
+	for {
+		if pos >= len(data) {
+			return 0, errInvalidGIF
+		}
+		size := int(data[pos])
+		pos++
+		if size == 0 {
+			return pos, nil
+		}
+		pos += size
+	}
+}
+
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+// webpDimensions читает размеры холста WebP из первого чанка (VP8, VP8L или VP8X)
+// и число кадров анимации без декодирования пикселей
+func webpDimensions(data []byte) (int, int, int, error) {
+	chunks, err := webpChunks(data)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if len(chunks) == 0 {
+		return 0, 0, 0, errInvalidWebP
+	}
+
+	var width, height int
+	first := chunks[0]
+	switch first.fourCC {
+	case "VP8 ":
+		// Тег кадра (3 байта), стартовый код 9d 01 2a, затем 14-битные ширина и высота
+		if len(first.payload) < 10 || !bytes.Equal(first.payload[3:6], []byte{0x9d, 0x01, 0x2a}) {
+			return 0, 0, 0, errInvalidWebP
+		}
+		width = int(binary.LittleEndian.Uint16(first.payload[6:]) & 0x3FFF)
+		height = int(binary.LittleEndian.Uint16(first.payload[8:]) & 0x3FFF)
+	case "VP8L":
+		// Сигнатура 0x2f, затем ширина-1 и высота-1 по 14 бит
+		if len(first.payload) < 5 || first.payload[0] != 0x2f {
+			return 0, 0, 0, errInvalidWebP
+		}
+		bits := binary.LittleEndian.Uint32(first.payload[1:])
+		width = int(bits&0x3FFF) + 1
+		height = int(bits>>14&0x3FFF) + 1
+	case "VP8X":
+		// Флаги (1 байт), резерв (3 байта), ширина-1 и высота-1 по 24 бита
+		if len(first.payload) < 10 {
+			return 0, 0, 0, errInvalidWebP
+		}
+		p := first.payload
+		width = int(uint32(p[4])|uint32(p[5])<<8|uint32(p[6])<<16) + 1
+		height = int(uint32(p[7])|uint32(p[8])<<8|uint32(p[9])<<16) + 1
+	default:
+		return 0, 0, 0, errInvalidWebP
+	}
+
+	frames := 0
+	for _, chunk := range chunks {
+		if chunk.fourCC == "ANMF" {
+			frames++
+		}
+	}
+
+	return width, height, max(frames, 1), nil
+}
+
+// isAnimatedWebP сообщает, содержит ли WebP анимацию (чанк ANIM)
+func isAnimatedWebP(data []byte) bool {
+	chunks, err := webpChunks(data)
+	if err != nil {
+		return false
+	}
+	for _, chunk := range chunks {
+		if chunk.fourCC == "ANIM" {
+			return true
+		}
+	}
+	return false
+}
+
+type webpChunk struct {
+	fourCC  string
+	payload []byte
+	// raw чанк целиком вместе с заголовком и выравниванием
+	raw []byte
+}
+
+// webpChunks разбирает чанки в пределах размера RIFF; данные после него
+// (например, приклеенный к картинке архив) не учитываются
+func webpChunks(data []byte) ([]webpChunk, error) {
+	if !isWebP(data) {
+		return nil, errInvalidWebP
+	}
+
+	riffEnd := 8 + int(binary.LittleEndian.Uint32(data[4:]))
+	if riffEnd < 12 || riffEnd > len(data) {
+		return nil, errInvalidWebP
+	}
+
+	var chunks []webpChunk
+	for pos := 12; pos < riffEnd; {
+		if pos+8 > riffEnd {
+			return nil, errInvalidWebP
+		}
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		// Чанки выравниваются по четной границе
+		end := pos + 8 + size + size%2
+		if size < 0 || end > riffEnd {
+			return nil, errInvalidWebP
+		}
+		chunks = append(chunks, webpChunk{
+			fourCC:  string(data[pos : pos+4]),
+			payload: data[pos+8 : pos+8+size],
+			raw:     data[pos:end],
+		})
+		pos = end
+	}
+
+	return chunks, nil
+}
@@ -0,0 +1,162 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+)
+
+const exifOrientationTag = 0x0112
+
+// jpegOrientation читает тег Orientation (1-8) из EXIF сегмента JPEG; 1, если тега нет
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		// Начало сжатых данных: дальше метаданных нет
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+
+	return 1
+}
+
+// exifOrientation ищет тег Orientation в IFD0 TIFF-структуры EXIF
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// orient поворачивает и отражает изображение согласно EXIF Orientation
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	// Ориентации 5-8 меняют ширину и высоту местами
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // отражение по горизонтали
+				sx, sy = w-1-x, y
+			case 3: // поворот на 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // отражение по вертикали
+				sx, sy = x, h-1-y
+			case 5: // транспонирование
+				sx, sy = y, x
+			case 6: // поворот на 90° по часовой стрелке
+				sx, sy = y, h-1-x
+			case 7: // поперечное транспонирование
+				sx, sy = w-1-y, h-1-x
+			case 8: // поворот на 90° против часовой стрелки
+				sx, sy = w-1-y, x
+			default:
+				sx, sy = x, y
+			}
+			s := src.PixOffset(bounds.Min.X+sx, bounds.Min.Y+sy)
+			d := dst.PixOffset(x, y)
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+
+	return dst
+}
+
+var errInvalidWebP = errors.New("invalid webp file")
+
+// webpImageChunks чанки WebP, нужные для отображения изображения
+var webpImageChunks = map[string]bool{
+	"VP8 ": true,
+	"VP8L": true,
+	"VP8X": true,
+	"ALPH": true,
+	"ANIM": true,
+	"ANMF": true,
+	"ICCP": true,
+}
+
+// StripWebPMetadata пересобирает WebP только из чанков изображения: EXIF, XMP,
+// неизвестные чанки и данные после конца RIFF отбрасываются. Изображение не
+// перекодируется: кодировщика WebP нет.
+func StripWebPMetadata(data []byte) ([]byte, error) {
+	chunks, err := webpChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+
+	for _, chunk := range chunks {
+		if !webpImageChunks[chunk.fourCC] {
+			continue
+		}
+		if chunk.fourCC == "VP8X" && len(chunk.payload) > 0 {
+			raw := append([]byte(nil), chunk.raw...)
+			// Флаги наличия EXIF (0x08) и XMP (0x04)
+			raw[8] &^= 0x08 | 0x04
+			out = append(out, raw...)
+			continue
+		}
+		out = append(out, chunk.raw...)
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package imaging
+
+import (
+	"image"
+	"math"
+)
+
+// contribution вклад пикселя исходной строки (столбца) в пиксель результата
+type contribution struct {
+	index  int
+	weight float32
+}
+
+// areaWeights вычисляет для каждого пикселя результата веса покрываемых им
+// исходных пикселей (усреднение по площади, подходит для уменьшения)
+func areaWeights(srcLen, dstLen int) [][]contribution {
+	scale := float64(srcLen) / float64(dstLen)
+	weights := make([][]contribution, dstLen)
+
+	for i := range weights {
+		start := float64(i) * scale
+		end := math.Min(float64(i+1)*scale, float64(srcLen))
+		for s := int(start); float64(s) < end; s++ {
+			coverage := math.Min(end, float64(s+1)) - math.Max(start, float64(s))
+			if coverage <= 0 {
+				continue
+			}
+			weights[i] = append(weights[i], contribution{index: s, weight: float32(coverage / scale)})
+		}
+	}
+
+	return weights
+}
+
+// resize уменьшает изображение до width x height. Пиксели image.RGBA хранятся с
+// premultiplied alpha, поэтому каналы усредняются независимо без ореолов на краях
+// прозрачных областей.
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	// Горизонтальный проход: srcH строк шириной width
+	columns := areaWeights(srcW, width)
+	tmp := make([]float32, width*srcH*4)
+	for y := 0; y < srcH; y++ {
+		row := src.Pix[src.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		for x, contributions := range columns {
+			var r, g, b, a float32
+			for _, c := range contributions {
+				p := row[c.index*4 : c.index*4+4 : c.index*4+4]
+				r += float32(p[0]) * c.weight
+				g += float32(p[1]) * c.weight
+				b += float32(p[2]) * c.weight
+				a += float32(p[3]) * c.weight
+			}
+			i := (y*width + x) * 4
+			tmp[i], tmp[i+1], tmp[i+2], tmp[i+3] = r, g, b, a
+		}
+	}
+
+	// Вертикальный проход
+	rows := areaWeights(srcH, height)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y, contributions := range rows {
+		for x := 0; x < width; x++ {
+			var r, g, b, a float32
+			for _, c := range contributions {
+				i := (c.index*width + x) * 4
+				r += tmp[i] * c.weight
+				g += tmp[i+1] * c.weight
+				b += tmp[i+2] * c.weight
+				a += tmp[i+3] * c.weight
+			}
+			o := dst.PixOffset(x, y)
+			dst.Pix[o] = clampByte(r)
+			dst.Pix[o+1] = clampByte(g)
+			dst.Pix[o+2] = clampByte(b)
+			dst.Pix[o+3] = clampByte(a)
+		}
+	}
+
+	return dst
+}
+
+func clampByte(v float32) uint8 {
+	v = float32(math.Round(float64(v)))
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// FileSender сохраняет письма в каталог в виде .eml файлов вместо отправки.
+// Используется для локальной разработки и тестов.
+type FileSender struct {
+	dir     string
+	counter atomic.Uint64
+}
+
+func NewFileSender(dir string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	return &FileSender{dir: dir}, nil
+}
+
+func (s *FileSender) Send(ctx context.Context, message *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := Build(message)
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_", "<", "", ">", "").Replace(message.To)
+	name := fmt.Sprintf("%d-%04d-%s.eml", time.Now().UnixNano(), s.counter.Add(1), recipient)
+
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	return nil
+}
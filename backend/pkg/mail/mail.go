@@ -0,0 +1,128 @@
+// Package mail отправляет письма через подключаемый транспорт: SMTP в продакшене
+// и запись в файлы при локальной разработке и в тестах.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"sort"
+	"startup-scout/config"
+	"strings"
+	"time"
+)
+
+// Message письмо с HTML и текстовой версией
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+	// Headers дополнительные заголовки, например List-Unsubscribe
+	Headers map[string]string
+}
+
+// Sender транспорт доставки писем
+type Sender interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// Build собирает письмо в формате RFC 5322 (multipart/alternative)
+func Build(message *Message) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	if err := writePart(writer, "text/plain; charset=UTF-8", message.Text); err != nil {
+		return nil, err
+	}
+	if err := writePart(writer, "text/html; charset=UTF-8", message.HTML); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{
+		"From":         message.From,
+		"To":           message.To,
+		"Subject":      mime.QEncoding.Encode("UTF-8", message.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"Message-ID":   messageID(message.From),
+		"MIME-Version": "1.0",
+		"Content-Type": "multipart/alternative; boundary=" + writer.Boundary(),
+	}
+	for key, value := range message.Headers {
+		headers[key] = value
+	}
+
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var result bytes.Buffer
+	for _, key := range keys {
+		value := headers[key]
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid header %s", key)
+		}
+		fmt.Fprintf(&result, "%s: %s\r\n", key, value)
+	}
+	result.WriteString("\r\n")
+	result.Write(body.Bytes())
+
+	return result.Bytes(), nil
+}
+
+func writePart(writer *multipart.Writer, contentType, content string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+
+	random := make([]byte, 12)
+	rand.Read(random)
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain)
+}
+
+// NewSender создает транспорт по конфигурации: "smtp" или "file" (по умолчанию)
+func NewSender(cfg *config.MailConfig) (Sender, error) {
+	switch cfg.Transport {
+	case "smtp":
+		return NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword), nil
+	case "file", "":
+		dir := cfg.FileDir
+		if dir == "" {
+			dir = "./mail"
+		}
+		return NewFileSender(dir)
+	default:
+		return nil, fmt.Errorf("unknown mail transport: %s", cfg.Transport)
+	}
+}
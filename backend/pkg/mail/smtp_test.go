@@ -0,0 +1,152 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer принимает одно письмо по минимальному SMTP-диалогу без STARTTLS и AUTH
+type fakeSMTPServer struct {
+	listener net.Listener
+	received chan string
+}
+
+func newFakeSMTPServer(t *testing.T, greet bool) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTPServer{listener: listener, received: make(chan string, 1)}
+	go server.serve(greet)
+	return server
+}
+
+func (s *fakeSMTPServer) serve(greet bool) {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if !greet {
+		// Зависший сервер: держит соединение, но не отвечает
+		buf := make([]byte, 1)
+		conn.Read(buf)
+		return
+	}
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(command, "MAIL"), strings.HasPrefix(command, "RCPT"):
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.received <- data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) sender(t *testing.T) *SMTPSender {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(s.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("split address: %v", err)
+	}
+	return NewSMTPSender(host, port, "", "")
+}
+
+func testMessage() *Message {
+	return &Message{
+		From:    "Startup Scout <noreply@startup-scout.test>",
+		To:      "reader@example.com",
+		Subject: "Проверка",
+		Text:    "Текст письма",
+		HTML:    "<p>Текст письма</p>",
+	}
+}
+
+func TestSMTPSenderDeliversMessage(t *testing.T) {
+	server := newFakeSMTPServer(t, true)
+
+	if err := server.sender(t).Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	select {
+	case data := <-server.received:
+		if !strings.Contains(data, "To: reader@example.com") {
+			t.Errorf("message has no recipient header:\n%s", data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server did not receive the message")
+	}
+}
+
+func TestSMTPSenderHonoursContext(t *testing.T) {
+	server := newFakeSMTPServer(t, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	err := server.sender(t).Send(ctx, testMessage())
+	if err == nil {
+		t.Fatal("Send succeeded against a stalled server")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("Send returned after %v, want it to stop at the context deadline", elapsed)
+	}
+}
+
+func TestSMTPSenderStopsOnCancel(t *testing.T) {
+	server := newFakeSMTPServer(t, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	err := server.sender(t).Send(ctx, testMessage())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestLocalStorage(t *testing.T) *LocalStorage {
+	t.Helper()
+
+	store, err := NewLocalStorage(t.TempDir(), "https://api.test/uploads/")
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	return store
+}
+
+// putString сохраняет объект с содержимым data
+func putString(t *testing.T, store Storage, key, data, contentType string) {
+	t.Helper()
+
+	if err := store.Put(context.Background(), key, strings.NewReader(data), int64(len(data)), contentType); err != nil {
+		t.Fatalf("Put(%s): %v", key, err)
+	}
+}
+
+// readString читает объект целиком
+func readString(t *testing.T, store Storage, key string) (string, *Object) {
+	t.Helper()
+
+	reader, object, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", key, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read %s: %v", key, err)
+	}
+	return string(data), object
+}
+
+// listKeys возвращает ключи объектов хранилища
+func listKeys(t *testing.T, store Storage) []string {
+	t.Helper()
+
+	var keys []string
+	err := store.List(context.Background(), func(object *Object) error {
+		keys = append(keys, object.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	return keys
+}
+
+func TestLocalStoragePutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := newTestLocalStorage(t)
+
+	putString(t, store, "logo.png", "first", "image/png")
+	putString(t, store, "logo.png", "second version", "image/png")
+
+	data, object := readString(t, store, "logo.png")
+	if data != "second version" {
+		t.Errorf("data = %q, want the overwritten object", data)
+	}
+	if object.Size != int64(len(data)) || object.ContentType != "image/png" {
+		t.Errorf("object = %+v", object)
+	}
+
+	stat, err := store.Stat(ctx, "logo.png")
+	if err != nil || stat.Size != object.Size {
+		t.Fatalf("Stat = %+v, %v", stat, err)
+	}
+
+	url, err := store.PresignGet(ctx, "logo.png", 0)
+	if err != nil || url != "https://api.test/uploads/logo.png" {
+		t.Errorf("PresignGet = %q, %v", url, err)
+	}
+
+	if err := store.Delete(ctx, "logo.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Stat(ctx, "logo.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat after delete: %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStorageNotFound(t *testing.T) {
+	ctx := context.Background()
+	store := newTestLocalStorage(t)
+
+	if _, _, err := store.Get(ctx, "missing.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get: %v, want ErrNotFound", err)
+	}
+	if _, err := store.Stat(ctx, "missing.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat: %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "missing.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete: %v, want ErrNotFound", err)
+	}
+	if _, err := store.PresignGet(ctx, "missing.png", 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("PresignGet: %v, want ErrNotFound", err)
+	}
+
+	// Подкаталог в папке загрузок не отдается как объект
+	if err := os.Mkdir(filepath.Join(store.dir, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.Get(ctx, "nested"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(directory): %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStorageRejectsInvalidKeys(t *testing.T) {
+	ctx := context.Background()
+	store := newTestLocalStorage(t)
+
+	for _, key := range []string{"", ".", "..", "../escape.png", "nested/file.png", `dir\file.png`, "nul\x00.png"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q): %v, want ErrInvalidKey", key, err)
+		}
+		if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Get(%q): %v, want ErrInvalidKey", key, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(store.dir), "escape.png")); !os.IsNotExist(err) {
+		t.Error("file was written outside the storage directory")
+	}
+}
+
+func TestLocalStorageListSkipsTemporaryFiles(t *testing.T) {
+	store := newTestLocalStorage(t)
+
+	putString(t, store, "a.png", "a", "image/png")
+	putString(t, store, "b.webp", "bb", "image/webp")
+	// Незавершенная загрузка и подкаталог
+	if err := os.WriteFile(filepath.Join(store.dir, ".upload-123"), []byte("partial"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(store.dir, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := listKeys(t, store)
+	if strings.Join(keys, ",") != "a.png,b.webp" {
+		t.Errorf("List = %v, want [a.png b.webp]", keys)
+	}
+
+	// Ошибка колбэка прерывает обход
+	stop := errors.New("stop")
+	visited := 0
+	err := store.List(context.Background(), func(*Object) error {
+		visited++
+		return stop
+	})
+	if !errors.Is(err, stop) || visited != 1 {
+		t.Errorf("List = %v after %d objects, want the callback error after the first", err, visited)
+	}
+}
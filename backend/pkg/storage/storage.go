@@ -0,0 +1,118 @@
+// Package storage хранит загруженные файлы в подключаемом хранилище: на локальном
+// диске или в S3-совместимом объектном хранилище (AWS S3, MinIO).
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"startup-scout/config"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Object метаданные сохраненного объекта
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage хранилище объектов. Ключи - плоские имена файлов без каталогов.
+type Storage interface {
+	// Put сохраняет объект; существующий объект с тем же ключом перезаписывается
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get открывает объект для чтения; ErrNotFound, если объекта нет
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	// Stat возвращает метаданные объекта; ErrNotFound, если объекта нет
+	Stat(ctx context.Context, key string) (*Object, error)
+	// Delete удаляет объект; ErrNotFound, если объекта нет
+	Delete(ctx context.Context, key string) error
+	// List вызывает fn для каждого объекта хранилища
+	List(ctx context.Context, fn func(*Object) error) error
+	// PresignGet возвращает ссылку для скачивания объекта в обход API,
+	// действующую ttl (для локального хранилища - постоянный публичный адрес)
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// New создает хранилище по конфигурации: "local" (по умолчанию) или "s3"
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Type {
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:     cfg.S3Endpoint,
+			Region:       cfg.S3Region,
+			Bucket:       cfg.S3Bucket,
+			AccessKey:    cfg.S3AccessKey,
+			SecretKey:    cfg.S3SecretKey,
+			UsePathStyle: cfg.S3UsePathStyle,
+		})
+	case "local", "":
+		return NewLocalStorage(cfg.LocalPath, cfg.BaseURL)
+	default:
+		return nil, fmt.Errorf("unknown storage type: %s", cfg.Type)
+	}
+}
+
+// ValidateKey проверяет, что ключ - имя файла без каталогов и спецсимволов пути
+func ValidateKey(key string) error {
+	if key == "" || key == "." || key == ".." || path.Base(key) != key ||
+		strings.ContainsAny(key, "\\\x00") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// MigrateResult итог переноса объектов между хранилищами
+type MigrateResult struct {
+	Copied  int
+	Skipped int
+}
+
+// Migrate копирует все объекты из src в dst. Объекты, уже существующие в dst
+// с тем же размером, пропускаются, поэтому прерванный перенос можно повторить.
+// Исходные объекты не удаляются.
+func Migrate(ctx context.Context, src, dst Storage) (MigrateResult, error) {
+	var result MigrateResult
+
+	err := src.List(ctx, func(object *Object) error {
+		existing, err := dst.Stat(ctx, object.Key)
+		if err == nil && existing.Size == object.Size {
+			result.Skipped++
+			return nil
+		}
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("failed to check %s in destination: %w", object.Key, err)
+		}
+
+		if err := copyObject(ctx, src, dst, object.Key); err != nil {
+			return err
+		}
+		result.Copied++
+		return nil
+	})
+
+	return result, err
+}
+
+func copyObject(ctx context.Context, src, dst Storage, key string) error {
+	reader, object, err := src.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	defer reader.Close()
+
+	if err := dst.Put(ctx, key, reader, object.Size, object.ContentType); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+
+	return nil
+}
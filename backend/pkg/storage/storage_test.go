@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// failingStorage пропускает limit загрузок, а затем отказывает, имитируя
+// перенос, прерванный на середине
+type failingStorage struct {
+	Storage
+	limit int
+	puts  int
+}
+
+var errInterrupted = errors.New("interrupted")
+
+func (s *failingStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if s.puts >= s.limit {
+		return errInterrupted
+	}
+	s.puts++
+	return s.Storage.Put(ctx, key, r, size, contentType)
+}
+
+func TestMigrateCopiesObjects(t *testing.T) {
+	ctx := context.Background()
+	src := newTestLocalStorage(t)
+	dst := newTestLocalStorage(t)
+
+	putString(t, src, "a.png", "aaa", "image/png")
+	putString(t, src, "b.png", "bbbb", "image/png")
+
+	result, err := Migrate(ctx, src, dst)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if result != (MigrateResult{Copied: 2}) {
+		t.Errorf("result = %+v, want 2 copied", result)
+	}
+
+	for key, want := range map[string]string{"a.png": "aaa", "b.png": "bbbb"} {
+		if data, _ := readString(t, dst, key); data != want {
+			t.Errorf("%s = %q, want %q", key, data, want)
+		}
+	}
+	// Исходные объекты остаются на месте
+	if keys := listKeys(t, src); len(keys) != 2 {
+		t.Errorf("source objects = %v", keys)
+	}
+}
+
+func TestMigrateSkipsObjectsWithSameSize(t *testing.T) {
+	ctx := context.Background()
+	src := newTestLocalStorage(t)
+	dst := newTestLocalStorage(t)
+
+	putString(t, src, "same.png", "new", "image/png")
+	putString(t, src, "resized.png", "longer", "image/png")
+	putString(t, dst, "same.png", "old", "image/png")
+	putString(t, dst, "resized.png", "short", "image/png")
+
+	result, err := Migrate(ctx, src, dst)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if result != (MigrateResult{Copied: 1, Skipped: 1}) {
+		t.Errorf("result = %+v, want 1 copied and 1 skipped", result)
+	}
+
+	// Совпадение размера считается признаком уже перенесенного объекта
+	if data, _ := readString(t, dst, "same.png"); data != "old" {
+		t.Errorf("same.png was overwritten: %q", data)
+	}
+	if data, _ := readString(t, dst, "resized.png"); data != "longer" {
+		t.Errorf("resized.png = %q, want it copied again", data)
+	}
+}
+
+func TestMigrateResumesAfterInterruption(t *testing.T) {
+	ctx := context.Background()
+	src := newTestLocalStorage(t)
+	dst := newTestLocalStorage(t)
+
+	for _, key := range []string{"a.png", "b.png", "c.png"} {
+		putString(t, src, key, "data-"+key, "image/png")
+	}
+
+	result, err := Migrate(ctx, src, &failingStorage{Storage: dst, limit: 1})
+	if !errors.Is(err, errInterrupted) {
+		t.Fatalf("Migrate error = %v, want interruption", err)
+	}
+	if result.Copied != 1 {
+		t.Fatalf("copied before interruption = %d, want 1", result.Copied)
+	}
+
+	result, err = Migrate(ctx, src, dst)
+	if err != nil {
+		t.Fatalf("repeated Migrate: %v", err)
+	}
+	if result != (MigrateResult{Copied: 2, Skipped: 1}) {
+		t.Errorf("result = %+v, want 2 copied and 1 skipped", result)
+	}
+
+	result, err = Migrate(ctx, src, dst)
+	if err != nil || result != (MigrateResult{Skipped: 3}) {
+		t.Errorf("third Migrate = %+v, %v; want everything skipped", result, err)
+	}
+}
@@ -0,0 +1,122 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret ключ SHA1 из приложения B RFC 6238 ("12345678901234567890") в base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	// RFC приводит 8-значные коды; 6-значный код - их последние шесть цифр
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Code(%d): %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCodeAcceptsLowercaseAndPaddedSecret(t *testing.T) {
+	at := time.Unix(59, 0)
+	for _, secret := range []string{strings.ToLower(rfcSecret), rfcSecret + "===="} {
+		got, err := Code(secret, at)
+		if err != nil {
+			t.Fatalf("Code(%q): %v", secret, err)
+		}
+		if got != "287082" {
+			t.Errorf("Code(%q) = %s, want 287082", secret, got)
+		}
+	}
+}
+
+func TestCodeRejectsInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", time.Now()); err == nil {
+		t.Error("Code with invalid secret: want error")
+	}
+}
+
+func TestMatchStepWindow(t *testing.T) {
+	// Начало шага 1000, чтобы соседние шаги лежали ровно на границах
+	stepStart := time.Unix(1000*Period, 0)
+	codeAt := func(step int64) string {
+		code, err := Code(rfcSecret, time.Unix(step*Period, 0))
+		if err != nil {
+			t.Fatalf("Code: %v", err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		at       time.Time
+		skew     int
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", codeAt(1000), stepStart, 1, 1000, true},
+		{"last second of step", codeAt(1000), stepStart.Add((Period - 1) * time.Second), 0, 1000, true},
+		{"next step without skew", codeAt(1000), stepStart.Add(Period * time.Second), 0, 0, false},
+		{"previous step within skew", codeAt(999), stepStart, 1, 999, true},
+		{"next step within skew", codeAt(1001), stepStart, 1, 1001, true},
+		{"two steps back outside skew", codeAt(998), stepStart, 1, 0, false},
+		{"two steps ahead outside skew", codeAt(1002), stepStart, 1, 0, false},
+		{"surrounding spaces", " " + codeAt(1000) + " ", stepStart, 0, 1000, true},
+		{"wrong length", codeAt(1000)[:Digits-1], stepStart, 1, 0, false},
+		{"not digits", "abcdef", stepStart, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := MatchStep(rfcSecret, tt.code, tt.at, tt.skew)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("MatchStep = %d, %v; want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+			if Validate(rfcSecret, tt.code, tt.at, tt.skew) != tt.wantOK {
+				t.Errorf("Validate = %v, want %v", !tt.wantOK, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		t.Fatalf("decodeSecret: %v", err)
+	}
+	if len(key) != secretSize {
+		t.Errorf("secret length = %d bytes, want %d", len(key), secretSize)
+	}
+
+	now := time.Now()
+	code, err := Code(secret, now)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	if !Validate(secret, code, now, 0) {
+		t.Error("generated code does not validate")
+	}
+}
@@ -0,0 +1,310 @@
+// Package video проверяет структуру контейнеров MP4 и WebM и читает длительность
+// ролика без декодирования кадров
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+const (
+	FormatMP4  = "mp4"
+	FormatWebM = "webm"
+)
+
+var ErrInvalid = errors.New("invalid video file")
+
+// Info параметры ролика из заголовков контейнера
+type Info struct {
+	Format   string
+	Duration time.Duration
+}
+
+// Probe разбирает контейнер MP4 или WebM. Ролик без известной длительности
+// (например, фрагментированный MP4 или запись трансляции) отклоняется, так как
+// ограничение длительности для него проверить нельзя.
+func Probe(data []byte) (*Info, error) {
+	var (
+		info *Info
+		err  error
+	)
+	switch {
+	case len(data) >= 8 && string(data[4:8]) == "ftyp":
+		info, err = probeMP4(data)
+	case bytes.HasPrefix(data, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		info, err = probeWebM(data)
+	default:
+		return nil, fmt.Errorf("%w: unknown container", ErrInvalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("%w: duration is unknown", ErrInvalid)
+	}
+	return info, nil
+}
+
+// seconds переводит длительность в секундах в time.Duration без переполнения
+func seconds(value float64) time.Duration {
+	if math.IsNaN(value) || value < 0 {
+		return 0
+	}
+	if value >= float64(math.MaxInt64)/float64(time.Second) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(value * float64(time.Second))
+}
+
+// probeMP4 проверяет, что файл состоит из боксов ISO BMFF, начинается с ftyp, и
+// читает длительность из moov/mvhd
+func probeMP4(data []byte) (*Info, error) {
+	var duration time.Duration
+	found := false
+
+	for pos := 0; pos < len(data); {
+		size, boxType, header, err := mp4Box(data, pos)
+		if err != nil {
+			return nil, err
+		}
+		if pos == 0 && boxType != "ftyp" {
+			return nil, fmt.Errorf("%w: ftyp box expected", ErrInvalid)
+		}
+		if boxType == "moov" {
+			duration, err = mp4MovieDuration(data[pos+header : pos+size])
+			if err != nil {
+				return nil, err
+			}
+			found = true
+		}
+		pos += size
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: moov box not found", ErrInvalid)
+	}
+	return &Info{Format: FormatMP4, Duration: duration}, nil
+}
+
+// mp4Box читает заголовок бокса: полный размер, тип и длину заголовка
+func mp4Box(data []byte, pos int) (int, string, int, error) {
+	if pos+8 > len(data) {
+		return 0, "", 0, fmt.Errorf("%w: truncated box", ErrInvalid)
+	}
+
+	size := int(binary.BigEndian.Uint32(data[pos:]))
+	boxType := string(data[pos+4 : pos+8])
+	header := 8
+
+	switch size {
+	case 0:
+		// Бокс продолжается до конца файла
+		size = len(data) - pos
+	case 1:
+		if pos+16 > len(data) {
+			return 0, "", 0, fmt.Errorf("%w: truncated box", ErrInvalid)
+		}
+		largeSize := binary.BigEndian.Uint64(data[pos+8:])
+		if largeSize > uint64(len(data)-pos) {
+			return 0, "", 0, fmt.Errorf("%w: box %q exceeds file", ErrInvalid, boxType)
+		}
+		size = int(largeSize)
+		header = 16
+	}
+
+	if size < header || pos+size > len(data) {
+		return 0, "", 0, fmt.Errorf("%w: box %q exceeds file", ErrInvalid, boxType)
+	}
+	return size, boxType, header, nil
+}
+
+func mp4MovieDuration(moov []byte) (time.Duration, error) {
+	for pos := 0; pos < len(moov); {
+		size, boxType, header, err := mp4Box(moov, pos)
+		if err != nil {
+			return 0, err
+		}
+		if boxType != "mvhd" {
+			pos += size
+			continue
+		}
+
+		// Версия 0: 32-битные время создания, изменения и длительность;
+		// версия 1: 64-битные
+		body := moov[pos+header : pos+size]
+		var timescale uint32
+		var duration uint64
+		switch {
+		case len(body) >= 32 && body[0] == 1:
+			timescale = binary.BigEndian.Uint32(body[20:])
+			duration = binary.BigEndian.Uint64(body[24:])
+		case len(body) >= 20 && body[0] == 0:
+			timescale = binary.BigEndian.Uint32(body[12:])
+			duration = uint64(binary.BigEndian.Uint32(body[16:]))
+		default:
+			return 0, fmt.Errorf("%w: malformed mvhd box", ErrInvalid)
+		}
+		if timescale == 0 {
+			return 0, fmt.Errorf("%w: zero timescale", ErrInvalid)
+		}
+
+		return seconds(float64(duration) / float64(timescale)), nil
+	}
+
+	return 0, fmt.Errorf("%w: mvhd box not found", ErrInvalid)
+}
+
+// Идентификаторы элементов EBML/Matroska
+const (
+	ebmlHeaderID     = 0x1A45DFA3
+	ebmlDocTypeID    = 0x4282
+	segmentID        = 0x18538067
+	segmentInfoID    = 0x1549A966
+	timecodeScaleID  = 0x2AD7B1
+	durationID       = 0x4489
+	clusterID        = 0x1F43B675
+	defaultTimescale = 1000000 // наносекунд в единице времени по умолчанию
+)
+
+// probeWebM проверяет заголовок EBML с DocType webm и читает длительность из
+// Segment/Info
+func probeWebM(data []byte) (*Info, error) {
+	id, start, end, err := ebmlElement(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if id != ebmlHeaderID {
+		return nil, fmt.Errorf("%w: EBML header expected", ErrInvalid)
+	}
+
+	docType := ""
+	for pos := start; pos < end; {
+		childID, childStart, childEnd, err := ebmlElement(data[:end], pos)
+		if err != nil {
+			return nil, err
+		}
+		if childID == ebmlDocTypeID {
+			docType = string(bytes.TrimRight(data[childStart:childEnd], "\x00"))
+		}
+		pos = childEnd
+	}
+	if docType != "webm" {
+		return nil, fmt.Errorf("%w: unsupported doctype %q", ErrInvalid, docType)
+	}
+
+	id, start, end, err = ebmlElement(data, end)
+	if err != nil {
+		return nil, err
+	}
+	if id != segmentID {
+		return nil, fmt.Errorf("%w: segment expected", ErrInvalid)
+	}
+
+	for pos := start; pos < end; {
+		childID, childStart, childEnd, err := ebmlElement(data[:end], pos)
+		if err != nil {
+			return nil, err
+		}
+		switch childID {
+		case segmentInfoID:
+			duration, err := webmDuration(data[childStart:childEnd])
+			if err != nil {
+				return nil, err
+			}
+			return &Info{Format: FormatWebM, Duration: duration}, nil
+		case clusterID:
+			// Info всегда предшествует кадрам
+			return nil, fmt.Errorf("%w: segment info not found", ErrInvalid)
+		}
+		pos = childEnd
+	}
+
+	return nil, fmt.Errorf("%w: segment info not found", ErrInvalid)
+}
+
+func webmDuration(info []byte) (time.Duration, error) {
+	timescale := uint64(defaultTimescale)
+	var duration float64
+
+	for pos := 0; pos < len(info); {
+		id, start, end, err := ebmlElement(info, pos)
+		if err != nil {
+			return 0, err
+		}
+		payload := info[start:end]
+		switch id {
+		case timecodeScaleID:
+			if len(payload) == 0 || len(payload) > 8 {
+				return 0, fmt.Errorf("%w: malformed timecode scale", ErrInvalid)
+			}
+			timescale = 0
+			for _, b := range payload {
+				timescale = timescale<<8 | uint64(b)
+			}
+		case durationID:
+			switch len(payload) {
+			case 4:
+				duration = float64(math.Float32frombits(binary.BigEndian.Uint32(payload)))
+			case 8:
+				duration = math.Float64frombits(binary.BigEndian.Uint64(payload))
+			default:
+				return 0, fmt.Errorf("%w: malformed duration", ErrInvalid)
+			}
+		}
+		pos = end
+	}
+
+	return seconds(duration * float64(timescale) / float64(time.Second)), nil
+}
+
+// ebmlElement читает элемент EBML с позиции pos: идентификатор и границы данных.
+// Элемент неизвестного размера продолжается до конца data.
+func ebmlElement(data []byte, pos int) (uint64, int, int, error) {
+	id, idLength, err := ebmlVint(data, pos, true)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	size, sizeLength, err := ebmlVint(data, pos+idLength, false)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	start := pos + idLength + sizeLength
+	if size == 1<<(7*sizeLength)-1 {
+		return id, start, len(data), nil
+	}
+	if size > uint64(len(data)-start) {
+		return 0, 0, 0, fmt.Errorf("%w: element exceeds file", ErrInvalid)
+	}
+	return id, start, start + int(size), nil
+}
+
+// ebmlVint читает число переменной длины; у идентификаторов маркер длины
+// остается частью значения
+func ebmlVint(data []byte, pos int, keepMarker bool) (uint64, int, error) {
+	if pos >= len(data) {
+		return 0, 0, fmt.Errorf("%w: truncated element", ErrInvalid)
+	}
+
+	first := data[pos]
+	length := bits.LeadingZeros8(first) + 1
+	if length > 8 || pos+length > len(data) {
+		return 0, 0, fmt.Errorf("%w: malformed element", ErrInvalid)
+	}
+
+	value := uint64(first)
+	if !keepMarker {
+		value &= 0xFF >> length
+	}
+	for i := 1; i < length; i++ {
+		value = value<<8 | uint64(data[pos+i])
+	}
+
+	return value, length, nil
+}
@@ -2,16 +2,29 @@ import {
   Project, 
   VoteResponse, 
   Comment, 
+  CommentWithUser,
   ProfileResponse, 
-  VotesResponse,
-  CommentsResponse,
+  Vote,
+  Page,
   ProjectCreateRequest,
-  ProjectsResponse,
   AuthResponse,
   StatsResponse
 } from '../types';
 import { API_CONFIG, API_ENDPOINTS } from '../config/api';
 
+// Максимальный размер страницы, который принимает API (pagination.MaxLimit)
+const PAGE_LIMIT = 100;
+
+// Ошибки API приходят в JSON: {"error": "<код>", "message": "...", "request_id": "..."}
+const parseErrorMessage = (errorText: string): string => {
+  try {
+    const errorData = JSON.parse(errorText);
+    return typeof errorData.message === 'string' ? errorData.message : errorText;
+  } catch {
+    return errorText;
+  }
+};
+
 class ApiClient {
   private baseURL: string;
 
@@ -56,7 +69,7 @@ class ApiClient {
         try {
           const errorText = await response.text();
           if (errorText) {
-            errorMessage = errorText;
+            errorMessage = parseErrorMessage(errorText);
           }
         } catch (e) {
           // If we can't read the response body, use the default message
@@ -95,15 +108,31 @@ class ApiClient {
     }
   }
 
+  // Списки отдаются страницами {items, next_cursor}: идем по курсору до последней страницы
+  private async requestAll<T>(endpoint: string): Promise<T[]> {
+    const items: T[] = [];
+    let cursor: string | undefined;
+
+    do {
+      const params = new URLSearchParams({ limit: String(PAGE_LIMIT) });
+      if (cursor) {
+        params.set('cursor', cursor);
+      }
+      const page = await this.request<Page<T>>(`${endpoint}?${params}`);
+      items.push(...(page.items || []));
+      cursor = page.next_cursor;
+    } while (cursor);
+
+    return items;
+  }
+
   // Projects
-  async getProjects(): Promise<ProjectsResponse> {
-    const response = await this.request<ProjectsResponse>(API_ENDPOINTS.PROJECTS);
-    return response;
+  async getProjects(): Promise<Project[]> {
+    return this.requestAll<Project>(API_ENDPOINTS.PROJECTS);
   }
 
-  async getUserProjects(userId: string): Promise<ProjectsResponse> {
-    const response = await this.request<ProjectsResponse>(`/users/${userId}/projects`);
-    return response;
+  async getUserProjects(userId: string): Promise<Project[]> {
+    return this.requestAll<Project>(`/users/${userId}/projects`);
   }
 
   async getProject(id: string): Promise<Project> {
@@ -138,8 +167,8 @@ class ApiClient {
     return { success: true, message: 'Vote removed successfully' };
   }
 
-  async getUserVotes(): Promise<VotesResponse> {
-    return this.request<VotesResponse>(API_ENDPOINTS.VOTES);
+  async getUserVotes(): Promise<Vote[]> {
+    return this.requestAll<Vote>(API_ENDPOINTS.VOTES);
   }
 
   // Auth
@@ -191,9 +220,8 @@ class ApiClient {
   }
 
   // Comments
-  async getProjectComments(projectId: string): Promise<CommentsResponse> {
-    const response = await this.request<CommentsResponse>(API_ENDPOINTS.PROJECT_COMMENTS(projectId));
-    return response;
+  async getProjectComments(projectId: string): Promise<CommentWithUser[]> {
+    return this.requestAll<CommentWithUser>(API_ENDPOINTS.PROJECT_COMMENTS(projectId));
   }
 
   async createComment(projectId: string, content: string): Promise<Comment> {
@@ -236,7 +264,7 @@ class ApiClient {
       try {
         const errorText = await response.text();
         if (errorText) {
-          errorMessage = errorText;
+          errorMessage = parseErrorMessage(errorText);
         }
       } catch (e) {
         console.warn('Could not read error response body:', e);
@@ -92,7 +92,7 @@ export const ImageUpload: React.FC<ImageUploadProps> = ({
           let errorMessage = 'Ошибка загрузки изображения';
           try {
             const errorData = await response.json();
-            errorMessage = errorData.error || errorMessage;
+            errorMessage = errorData.message || errorData.error || errorMessage;
           } catch {
             // Если ответ не JSON, используем текст ответа
             const errorText = await response.text();
@@ -11,8 +11,8 @@ export const useComments = (projectId: string) => {
     setLoading(true);
     setError(null);
     try {
-      const response = await apiClient.getProjectComments(projectId);
-      setComments(response.comments);
+      const comments = await apiClient.getProjectComments(projectId);
+      setComments(comments);
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Failed to fetch comments');
     } finally {
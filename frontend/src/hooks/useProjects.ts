@@ -1,6 +1,6 @@
 import { useState, useEffect, useCallback } from 'react';
 import { apiClient } from '../api/client';
-import { Project, ProjectCreateRequest, ProjectsResponse } from '../types';
+import { Project, ProjectCreateRequest } from '../types';
 
 export const useProjects = () => {
   const [projects, setProjects] = useState<Project[]>([]);
@@ -11,8 +11,8 @@ export const useProjects = () => {
     try {
       setLoading(true);
       setError(null);
-      const response: ProjectsResponse = await apiClient.getProjects();
-      setProjects(response.projects);
+      const projects = await apiClient.getProjects();
+      setProjects(projects);
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Failed to fetch projects');
     } finally {
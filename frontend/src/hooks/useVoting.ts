@@ -21,15 +21,8 @@ export const useVoting = (projectId?: string) => {
       try {
         setLoadingVote(true);
         const votes = await apiClient.getUserVotes();
-        
-        // Проверяем, что votes и votes.votes существуют
-        if (votes && votes.votes && Array.isArray(votes.votes)) {
-          const projectVote = votes.votes.find(vote => vote.project_id === projectId);
-          setUserVote(projectVote ? true : false);
-        } else {
-          // Если нет голосов или структура неверная, считаем что голоса нет
-          setUserVote(false);
-        }
+        const projectVote = votes.find(vote => vote.project_id === projectId);
+        setUserVote(projectVote ? true : false);
       } catch (err) {
         console.error('Failed to fetch user vote:', err);
         // При ошибке (например, 401 для неавторизованных пользователей) 
@@ -39,14 +39,14 @@ export const ProfilePage: React.FC = () => {
           apiClient.getProjects()
         ]);
         
-        setVotes(votesData.votes);
-        setProjects(projectsData.projects);
+        setVotes(votesData);
+        setProjects(projectsData);
         
         // Получаем проекты пользователя
         if (user.id) {
           try {
             const userProjectsData = await apiClient.getUserProjects(user.id);
-            setUserProjects(userProjectsData.projects || []);
+            setUserProjects(userProjectsData);
           } catch (err) {
             console.warn('Failed to load user projects:', err);
             setUserProjects([]);
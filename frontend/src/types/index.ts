@@ -5,6 +5,12 @@ export interface ApiResponse<T> {
   message?: string;
 }
 
+// Страница списка с курсорной пагинацией; next_cursor отсутствует на последней странице
+export interface Page<T> {
+  items: T[];
+  next_cursor?: string;
+}
+
 // Project types
 export interface Project {
 	id: string; // UUID
@@ -35,9 +41,7 @@ export interface ProjectCreateRequest {
   website: string;
 }
 
-export interface ProjectsResponse {
-	projects: Project[];
-}
+export type ProjectsResponse = Page<Project>;
 
 // Launch types
 export interface Launch {
@@ -103,9 +107,7 @@ export interface CommentWithUser {
 	avatar: string;
 }
 
-export interface CommentsResponse {
-	comments: CommentWithUser[];
-}
+export type CommentsResponse = Page<CommentWithUser>;
 
 // Auth types
 export interface AuthResponse {
@@ -118,9 +120,7 @@ export interface ProfileResponse {
 	user: User;
 }
 
-export interface VotesResponse {
-	votes: Vote[];
-}
+export type VotesResponse = Page<Vote>;
 
 // Stats types
 export interface StatsResponse {